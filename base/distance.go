@@ -1,13 +1,22 @@
 package base
 
 import (
+   "fmt"
    "math"
+
+   "gonum.org/v1/gonum/mat"
 )
 
 type Distancer interface {
    Distance(NumericTuple, NumericTuple) float64
 }
 
+// A distance that works on any tuple (eg a GeneralTuple with string and nil features), not just NumericTuple.
+type GeneralDistancer interface {
+   Distancer
+   GeneralDistance(Tuple, Tuple) float64
+}
+
 type Euclidean struct{}
 
 func (e Euclidean) Distance(x NumericTuple, y NumericTuple) float64 {
@@ -19,3 +28,408 @@ func (e Euclidean) Distance(x NumericTuple, y NumericTuple) float64 {
 
    return math.Sqrt(sum);
 }
+
+// Euclidean distance where each squared difference is scaled by a (non-negative) per-feature weight.
+type WeightedEuclidean struct {
+   Weights []float64
+}
+
+func NewWeightedEuclidean(weights []float64) WeightedEuclidean {
+   for i, weight := range(weights) {
+      if (weight < 0) {
+         panic(fmt.Sprintf("Weights must be non-negative. Weight[%d]: %v", i, weight));
+      }
+   }
+
+   return WeightedEuclidean{append([]float64(nil), weights...)};
+}
+
+func (this WeightedEuclidean) Distance(x NumericTuple, y NumericTuple) float64 {
+   if (x.DataSize() != len(this.Weights)) {
+      panic(fmt.Sprintf("Expected %d features (one per weight), got %d.", len(this.Weights), x.DataSize()));
+   }
+
+   var sum float64 = 0;
+
+   for i := 0; i < x.DataSize(); i++ {
+      var diff float64 = x.GetNumericData(i) - y.GetNumericData(i);
+      sum += this.Weights[i] * diff * diff;
+   }
+
+   return math.Sqrt(sum);
+}
+
+// Also known as taxicab or L1 distance.
+type Manhattan struct{}
+
+func (this Manhattan) Distance(x NumericTuple, y NumericTuple) float64 {
+   var sum float64 = 0;
+
+   for i := 0; i < x.DataSize(); i++ {
+      sum += math.Abs(x.GetNumericData(i) - y.GetNumericData(i));
+   }
+
+   return sum;
+}
+
+// The largest difference in any feature (L-infinity distance).
+type Chebyshev struct{}
+
+func (this Chebyshev) Distance(x NumericTuple, y NumericTuple) float64 {
+   var max float64 = 0;
+
+   for i := 0; i < x.DataSize(); i++ {
+      max = math.Max(max, math.Abs(x.GetNumericData(i) - y.GetNumericData(i)));
+   }
+
+   return max;
+}
+
+// The Lp distance: (sum(|x - y|^p))^(1/p).
+// p = 1 is Manhattan, p = 2 is Euclidean.
+type Minkowski struct {
+   P float64
+}
+
+// |p| must be at least 1 (otherwise it is not a metric).
+func NewMinkowski(p float64) Minkowski {
+   if (p < 1) {
+      panic(fmt.Sprintf("Minkowski p must be >= 1, got: %v", p));
+   }
+
+   return Minkowski{p};
+}
+
+func (this Minkowski) Distance(x NumericTuple, y NumericTuple) float64 {
+   var sum float64 = 0;
+
+   for i := 0; i < x.DataSize(); i++ {
+      sum += math.Pow(math.Abs(x.GetNumericData(i) - y.GetNumericData(i)), this.P);
+   }
+
+   return math.Pow(sum, 1.0 / this.P);
+}
+
+// 1 - cos(angle between x and y).
+// Ranges over [0, 2].
+// A zero vector is 0 away from another zero vector and 1 away from everything else.
+// Note that this is not a true metric (no triangle inequality).
+type Cosine struct{}
+
+func (this Cosine) Distance(x NumericTuple, y NumericTuple) float64 {
+   return cosineDistance(x.ToFloatSlice(), y.ToFloatSlice());
+}
+
+// 1 - the Pearson correlation between x and y (the cosine distance of the centered vectors).
+// Ranges over [0, 2].
+// Note that this is not a true metric (no triangle inequality).
+type Correlation struct{}
+
+func (this Correlation) Distance(x NumericTuple, y NumericTuple) float64 {
+   return cosineDistance(centerSlice(x.ToFloatSlice()), centerSlice(y.ToFloatSlice()));
+}
+
+// The fraction of features that are not equal.
+// Meant for IntTuple and boolean data.
+type Hamming struct{}
+
+func (this Hamming) Distance(x NumericTuple, y NumericTuple) float64 {
+   if (x.DataSize() == 0) {
+      return 0;
+   }
+
+   var count int = 0;
+
+   for i := 0; i < x.DataSize(); i++ {
+      if (x.GetNumericData(i) != y.GetNumericData(i)) {
+         count++;
+      }
+   }
+
+   return float64(count) / float64(x.DataSize());
+}
+
+// 1 - |intersection| / |union| where any non-zero feature is considered to be in the set.
+// Meant for boolean data (or IntTuples of 0/1 indicators).
+// Two empty sets are 0 apart.
+type Jaccard struct{}
+
+func (this Jaccard) Distance(x NumericTuple, y NumericTuple) float64 {
+   var intersection int = 0;
+   var union int = 0;
+
+   for i := 0; i < x.DataSize(); i++ {
+      var inX bool = x.GetNumericData(i) != 0;
+      var inY bool = y.GetNumericData(i) != 0;
+
+      if (inX && inY) {
+         intersection++;
+      }
+
+      if (inX || inY) {
+         union++;
+      }
+   }
+
+   if (union == 0) {
+      return 0;
+   }
+
+   return 1.0 - float64(intersection) / float64(union);
+}
+
+// sqrt((x - y)' S^-1 (x - y)) where S is the covariance of some data.
+// Features are effectively decorrelated and scaled to unit variance.
+type Mahalanobis struct {
+   // The (pseudo-)inverse of the covariance matrix.
+   precision [][]float64
+}
+
+// Fit the covariance from |data|.
+// If the covariance is singular (eg a constant feature), then the pseudo-inverse is used.
+func NewMahalanobis(data []NumericTuple) Mahalanobis {
+   if (len(data) < 2) {
+      panic("Need at least two tuples to fit a covariance.");
+   }
+
+   var numFeatures int = data[0].DataSize();
+
+   var means []float64 = make([]float64, numFeatures);
+   for _, tuple := range(data) {
+      for j := 0; j < numFeatures; j++ {
+         means[j] += tuple.GetNumericData(j);
+      }
+   }
+
+   for j, _ := range(means) {
+      means[j] /= float64(len(data));
+   }
+
+   var centered *mat.Dense = mat.NewDense(len(data), numFeatures, nil);
+   for i, tuple := range(data) {
+      for j := 0; j < numFeatures; j++ {
+         centered.Set(i, j, tuple.GetNumericData(j) - means[j]);
+      }
+   }
+
+   var covariance *mat.SymDense = mat.NewSymDense(numFeatures, nil);
+   covariance.SymOuterK(1.0 / float64(len(data) - 1), centered.T());
+
+   return Mahalanobis{pseudoInverse(covariance)};
+}
+
+func (this Mahalanobis) Distance(x NumericTuple, y NumericTuple) float64 {
+   if (x.DataSize() != len(this.precision)) {
+      panic(fmt.Sprintf("Expected %d features, got %d.", len(this.precision), x.DataSize()));
+   }
+
+   var diff []float64 = make([]float64, x.DataSize());
+   for i, _ := range(diff) {
+      diff[i] = x.GetNumericData(i) - y.GetNumericData(i);
+   }
+
+   var sum float64 = 0;
+   for i, _ := range(diff) {
+      for j, _ := range(diff) {
+         sum += diff[i] * this.precision[i][j] * diff[j];
+      }
+   }
+
+   // Rounding can make this very slightly negative.
+   return math.Sqrt(math.Max(0, sum));
+}
+
+// Gower's distance for mixed feature types (Gower 1971).
+// The mean of the per-feature dissimilarities, which are all in [0, 1]:
+//  - numeric (int and float) features: |x - y| / range, capped at 1.
+//  - everything else (strings, bools, mixed types): 0 if equal, 1 otherwise.
+// Features that are nil in either tuple are skipped.
+// If every feature is skipped, then the distance is 0.
+type Gower struct {
+   // The range (max - min) of each feature in the fitting data.
+   // Features that never had a numeric value have a zero range.
+   ranges []float64
+}
+
+// Fit the numeric feature ranges from |data|.
+// The ranges only normalize differences, so data is not required to have every value.
+func NewGower(data []Tuple) Gower {
+   if (len(data) == 0) {
+      panic("Need at least one tuple to fit Gower ranges.");
+   }
+
+   var numFeatures int = data[0].DataSize();
+   var mins []float64 = make([]float64, numFeatures);
+   var maxes []float64 = make([]float64, numFeatures);
+   for i, _ := range(mins) {
+      mins[i] = math.Inf(1);
+      maxes[i] = math.Inf(-1);
+   }
+
+   for _, tuple := range(data) {
+      for i := 0; i < numFeatures; i++ {
+         value, ok := gowerNumericValue(tuple.GetData(i));
+         if (ok) {
+            mins[i] = math.Min(mins[i], value);
+            maxes[i] = math.Max(maxes[i], value);
+         }
+      }
+   }
+
+   var ranges []float64 = make([]float64, numFeatures);
+   for i, _ := range(ranges) {
+      if (maxes[i] > mins[i]) {
+         ranges[i] = maxes[i] - mins[i];
+      }
+   }
+
+   return Gower{ranges};
+}
+
+func (this Gower) Distance(x NumericTuple, y NumericTuple) float64 {
+   return this.GeneralDistance(x, y);
+}
+
+func (this Gower) GeneralDistance(x Tuple, y Tuple) float64 {
+   if (x.DataSize() != len(this.ranges)) {
+      panic(fmt.Sprintf("Expected %d features, got %d.", len(this.ranges), x.DataSize()));
+   }
+
+   var sum float64 = 0;
+   var count int = 0;
+
+   for i := 0; i < x.DataSize(); i++ {
+      var xFeature Feature = x.GetData(i);
+      var yFeature Feature = y.GetData(i);
+
+      if (isNil(xFeature) || isNil(yFeature)) {
+         continue;
+      }
+      count++;
+
+      xValue, xNumeric := gowerNumericValue(xFeature);
+      yValue, yNumeric := gowerNumericValue(yFeature);
+
+      if (xNumeric && yNumeric) {
+         if (xValue == yValue) {
+            continue;
+         }
+
+         // Values outside of the fitted range (or a constant feature) can be at most totally different.
+         if (this.ranges[i] == 0) {
+            sum += 1;
+         } else {
+            sum += math.Min(1, math.Abs(xValue - yValue) / this.ranges[i]);
+         }
+      } else if (xFeature != yFeature) {
+         sum += 1;
+      }
+   }
+
+   if (count == 0) {
+      return 0;
+   }
+
+   return sum / float64(count);
+}
+
+// Bools are treated as categories.
+func gowerNumericValue(feature Feature) (float64, bool) {
+   switch typedFeature := feature.(type) {
+   case IntFeature:
+      return typedFeature.NumericValue(), true;
+   case FloatFeature:
+      return typedFeature.NumericValue(), true;
+   default:
+      return 0, false;
+   }
+}
+
+func isNil(feature Feature) bool {
+   if (feature == nil) {
+      return true;
+   }
+
+   _, ok := feature.(NilFeature);
+   return ok;
+}
+
+// Moore-Penrose pseudo-inverse of a symmetric positive semi-definite matrix (through its eigen decomposition).
+func pseudoInverse(matrix *mat.SymDense) [][]float64 {
+   var size int = matrix.SymmetricDim();
+
+   var eigen mat.EigenSym;
+   if (!eigen.Factorize(matrix, true)) {
+      panic("Eigen decomposition failed.");
+   }
+
+   var values []float64 = eigen.Values(nil);
+   var vectors mat.Dense;
+   eigen.VectorsTo(&vectors);
+
+   var maxValue float64 = 0;
+   for _, value := range(values) {
+      maxValue = math.Max(maxValue, math.Abs(value));
+   }
+
+   var inverse [][]float64 = make([][]float64, size);
+   for i, _ := range(inverse) {
+      inverse[i] = make([]float64, size);
+   }
+
+   for k, value := range(values) {
+      // Treat tiny eigenvalues as zero.
+      if (value <= maxValue * float64(size) * 1e-12) {
+         continue;
+      }
+
+      for i := 0; i < size; i++ {
+         for j := 0; j < size; j++ {
+            inverse[i][j] += vectors.At(i, k) * vectors.At(j, k) / value;
+         }
+      }
+   }
+
+   return inverse;
+}
+
+func cosineDistance(x []float64, y []float64) float64 {
+   var dot float64 = 0;
+   var xNorm float64 = 0;
+   var yNorm float64 = 0;
+
+   for i, _ := range(x) {
+      dot += x[i] * y[i];
+      xNorm += x[i] * x[i];
+      yNorm += y[i] * y[i];
+   }
+
+   if (xNorm == 0 && yNorm == 0) {
+      return 0;
+   } else if (xNorm == 0 || yNorm == 0) {
+      return 1;
+   }
+
+   // Rounding can push this slightly outside of [-1, 1].
+   var similarity float64 = math.Max(-1, math.Min(1, dot / math.Sqrt(xNorm * yNorm)));
+   return 1.0 - similarity;
+}
+
+func centerSlice(values []float64) []float64 {
+   if (len(values) == 0) {
+      return values;
+   }
+
+   var mean float64 = 0;
+   for _, value := range(values) {
+      mean += value;
+   }
+   mean /= float64(len(values));
+
+   var centered []float64 = make([]float64, len(values));
+   for i, value := range(values) {
+      centered[i] = value - mean;
+   }
+
+   return centered;
+}
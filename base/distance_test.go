@@ -69,3 +69,215 @@ func TestEuclidean(t *testing.T) {
       }
    }
 }
+
+type DistancerTestData struct {
+   Title     string
+   Distancer Distancer
+   A         NumericTuple
+   B         NumericTuple
+   Distance  float64
+}
+
+func TestDistancers(t *testing.T) {
+   var testData []DistancerTestData = []DistancerTestData{
+      DistancerTestData{
+         "Manhattan",
+         Manhattan{},
+         NewNumericTuple([]interface{}{1, 2, 3}, nil),
+         NewNumericTuple([]interface{}{3, 2, -1}, nil),
+         6,
+      },
+      DistancerTestData{
+         "Chebyshev",
+         Chebyshev{},
+         NewNumericTuple([]interface{}{1, 2, 3}, nil),
+         NewNumericTuple([]interface{}{3, 2, -1}, nil),
+         4,
+      },
+      DistancerTestData{
+         "Minkowski - 1",
+         NewMinkowski(1),
+         NewNumericTuple([]interface{}{1, 2, 3}, nil),
+         NewNumericTuple([]interface{}{3, 2, -1}, nil),
+         6,
+      },
+      DistancerTestData{
+         "Minkowski - 2",
+         NewMinkowski(2),
+         NewNumericTuple([]interface{}{1, 2, 3}, nil),
+         NewNumericTuple([]interface{}{3, 2, -1}, nil),
+         math.Sqrt(20),
+      },
+      DistancerTestData{
+         "Minkowski - 3",
+         NewMinkowski(3),
+         NewNumericTuple([]interface{}{1, 2, 3}, nil),
+         NewNumericTuple([]interface{}{3, 2, -1}, nil),
+         math.Cbrt(72),
+      },
+      DistancerTestData{
+         "Weighted Euclidean",
+         NewWeightedEuclidean([]float64{2, 5, 0.5}),
+         NewNumericTuple([]interface{}{1, 2, 3}, nil),
+         NewNumericTuple([]interface{}{3, 2, -1}, nil),
+         math.Sqrt(16),
+      },
+      DistancerTestData{
+         "Cosine - Orthogonal",
+         Cosine{},
+         NewNumericTuple([]interface{}{1, 0}, nil),
+         NewNumericTuple([]interface{}{0, 5}, nil),
+         1,
+      },
+      DistancerTestData{
+         "Cosine - Scaled",
+         Cosine{},
+         NewNumericTuple([]interface{}{1, 2, 3}, nil),
+         NewNumericTuple([]interface{}{2, 4, 6}, nil),
+         0,
+      },
+      DistancerTestData{
+         "Cosine - Opposite",
+         Cosine{},
+         NewNumericTuple([]interface{}{1, 2, 3}, nil),
+         NewNumericTuple([]interface{}{-1, -2, -3}, nil),
+         2,
+      },
+      DistancerTestData{
+         "Cosine - Zero",
+         Cosine{},
+         NewNumericTuple([]interface{}{0, 0}, nil),
+         NewNumericTuple([]interface{}{1, 2}, nil),
+         1,
+      },
+      DistancerTestData{
+         "Correlation - Shifted",
+         Correlation{},
+         NewNumericTuple([]interface{}{1, 2, 3}, nil),
+         NewNumericTuple([]interface{}{11, 12, 13}, nil),
+         0,
+      },
+      DistancerTestData{
+         "Correlation - Anti",
+         Correlation{},
+         NewNumericTuple([]interface{}{1, 2, 3}, nil),
+         NewNumericTuple([]interface{}{3, 2, 1}, nil),
+         2,
+      },
+      DistancerTestData{
+         "Hamming - Int",
+         Hamming{},
+         NewIntTuple([]interface{}{1, 2, 3, 4}, nil),
+         NewIntTuple([]interface{}{1, 0, 3, 5}, nil),
+         0.5,
+      },
+      DistancerTestData{
+         "Hamming - Bool",
+         Hamming{},
+         NewNumericTuple([]interface{}{true, false, true}, nil),
+         NewNumericTuple([]interface{}{true, true, true}, nil),
+         1.0 / 3.0,
+      },
+      DistancerTestData{
+         "Jaccard",
+         Jaccard{},
+         NewIntTuple([]interface{}{1, 1, 0, 0, 1}, nil),
+         NewIntTuple([]interface{}{1, 0, 1, 0, 1}, nil),
+         0.5,
+      },
+      DistancerTestData{
+         "Jaccard - Empty",
+         Jaccard{},
+         NewIntTuple([]interface{}{0, 0}, nil),
+         NewIntTuple([]interface{}{0, 0}, nil),
+         0,
+      },
+   }
+
+   for _, testCase := range testData {
+      var actual float64 = testCase.Distancer.Distance(testCase.A, testCase.B)
+      if !util.FloatEquals(actual, testCase.Distance) {
+         t.Errorf("Distance error (%s). Expected: %v, Got: %v", testCase.Title, testCase.Distance, actual)
+      }
+
+      var reversed float64 = testCase.Distancer.Distance(testCase.B, testCase.A)
+      if !util.FloatEquals(actual, reversed) {
+         t.Errorf("Distance is not symmetric (%s). Forward: %v, Reverse: %v", testCase.Title, actual, reversed)
+      }
+   }
+}
+
+func TestMahalanobis(t *testing.T) {
+   // Independent features with variances 4 and 1 (and a constant feature).
+   var data []NumericTuple = []NumericTuple{
+      NewFloatTuple([]float64{-2, -1, 5}, nil),
+      NewFloatTuple([]float64{-2, 1, 5}, nil),
+      NewFloatTuple([]float64{2, -1, 5}, nil),
+      NewFloatTuple([]float64{2, 1, 5}, nil),
+   }
+
+   var distancer Distancer = NewMahalanobis(data)
+
+   // Sample variances are 16/3 and 4/3, the constant feature is ignored.
+   var a NumericTuple = NewFloatTuple([]float64{0, 0, 0}, nil)
+   var b NumericTuple = NewFloatTuple([]float64{4, 2, 100}, nil)
+   var expected float64 = math.Sqrt(16.0 / (16.0 / 3.0) + 4.0 / (4.0 / 3.0))
+
+   var actual float64 = distancer.Distance(a, b)
+   if math.Abs(actual - expected) > 1e-9 {
+      t.Errorf("Mahalanobis distance error. Expected: %v, Got: %v", expected, actual)
+   }
+}
+
+type GeneralDistanceTestData struct {
+   Title    string
+   A        Tuple
+   B        Tuple
+   Distance float64
+}
+
+func TestGower(t *testing.T) {
+   var data []Tuple = []Tuple{
+      NewTuple([]interface{}{"a", 0, 1.0, true}, nil),
+      NewTuple([]interface{}{"b", 10, 5.0, false}, nil),
+      NewTuple([]interface{}{"c", nil, 3.0, nil}, nil),
+   }
+
+   var gower Gower = NewGower(data)
+
+   var testData []GeneralDistanceTestData = []GeneralDistanceTestData{
+      GeneralDistanceTestData{
+         "Same",
+         NewTuple([]interface{}{"a", 5, 2.0, true}, nil),
+         NewTuple([]interface{}{"a", 5, 2.0, true}, nil),
+         0,
+      },
+      GeneralDistanceTestData{
+         "Mixed",
+         // (1 + 0.5 + 0.25 + 1) / 4
+         NewTuple([]interface{}{"a", 0, 1.0, true}, nil),
+         NewTuple([]interface{}{"b", 5, 2.0, false}, nil),
+         2.75 / 4.0,
+      },
+      GeneralDistanceTestData{
+         "Nil Skipped",
+         // (0 + 1) / 2
+         NewTuple([]interface{}{"a", nil, 1.0, nil}, nil),
+         NewTuple([]interface{}{"a", 5, 9.0, false}, nil),
+         0.5,
+      },
+      GeneralDistanceTestData{
+         "All Nil",
+         NewTuple([]interface{}{nil, nil, nil, nil}, nil),
+         NewTuple([]interface{}{"a", 5, 2.0, true}, nil),
+         0,
+      },
+   }
+
+   for _, testCase := range testData {
+      var actual float64 = gower.GeneralDistance(testCase.A, testCase.B)
+      if !util.FloatEquals(actual, testCase.Distance) {
+         t.Errorf("Gower distance error (%s). Expected: %v, Got: %v", testCase.Title, testCase.Distance, actual)
+      }
+   }
+}
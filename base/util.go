@@ -17,6 +17,12 @@ func Seed(seed int64) {
    random = rand.New(rand.NewSource(seed));
 }
 
+// Get a new random number generator seeded from the package random (see Seed()).
+// rand.Rand is not safe for concurrent use, so each goroutine should get its own.
+func NewRandom() *rand.Rand {
+   return rand.New(rand.NewSource(random.Int63()));
+}
+
 func GetMaxProcs() int {
    return maxProcs;
 }
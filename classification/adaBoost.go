@@ -0,0 +1,226 @@
+package classification
+
+// AdaBoost using the multiclass SAMME update (Zhu et al. 2009).
+// Any Classifier can be boosted.
+// If the base classifier is a WeightedClassifier, then the sample weights are passed directly.
+// Otherwise, each round trains on a (with replacement) resample of the data drawn according to the weights.
+
+import (
+   "fmt"
+   "math"
+   "math/rand"
+   "sort"
+
+   "github.com/eriq-augustine/goml/base"
+   "github.com/eriq-augustine/goml/features"
+   "github.com/eriq-augustine/goml/util"
+)
+
+const (
+   ADABOOST_DEFAULT_NUM_ROUNDS = 50
+   ADABOOST_DEFAULT_LEARNING_RATE = 1.0
+)
+
+type AdaBoost struct {
+   reducer features.Reducer
+   newClassifier func() Classifier
+   numRounds int
+   learningRate float64
+
+   labels []base.Feature
+   classifiers []Classifier
+   alphas []float64
+}
+
+// |newClassifier| is called once per round to get a fresh (untrained) base classifier.
+// The base classifiers will see the tuples after |reducer| has been applied.
+// A nil |newClassifier| will use decision stumps (DecisionTree with a max depth of 1).
+// Pass a non-positive value for |numRounds| or |learningRate| to get the default.
+func NewAdaBoost(reducer features.Reducer, newClassifier func() Classifier, numRounds int, learningRate float64) *AdaBoost {
+   if (reducer == nil) {
+      reducer = features.NoReducer{};
+   }
+
+   if (newClassifier == nil) {
+      newClassifier = func() Classifier {
+         return NewDecisionTree(nil, TREE_GINI, 1, 0, 0);
+      };
+   }
+
+   if (numRounds <= 0) {
+      numRounds = ADABOOST_DEFAULT_NUM_ROUNDS;
+   }
+
+   if (learningRate <= 0) {
+      learningRate = ADABOOST_DEFAULT_LEARNING_RATE;
+   }
+
+   var adaBoost AdaBoost = AdaBoost{
+      reducer: reducer,
+      newClassifier: newClassifier,
+      numRounds: numRounds,
+      learningRate: learningRate,
+   };
+
+   return &adaBoost;
+}
+
+func (this *AdaBoost) Train(tuples []base.Tuple) {
+   if (tuples == nil || len(tuples) == 0) {
+      panic("Must provide tuples for training.")
+   }
+
+   this.reducer.Init(tuples);
+   tuples = this.reducer.Reduce(tuples);
+
+   var dataLabels []int;
+   this.labels, dataLabels = mapLabels(tuples);
+   var numLabels int = len(this.labels);
+
+   var random *rand.Rand = base.NewRandom();
+
+   var weights []float64 = make([]float64, len(tuples));
+   for i, _ := range(weights) {
+      weights[i] = 1.0 / float64(len(tuples));
+   }
+
+   this.classifiers = make([]Classifier, 0, this.numRounds);
+   this.alphas = make([]float64, 0, this.numRounds);
+
+   // With a single class there is no error to boost (and no random guessing to beat),
+   // so just keep one base classifier.
+   if (numLabels == 1) {
+      var classifier Classifier = this.newClassifier();
+      trainWeighted(classifier, tuples, weights, random);
+
+      this.classifiers = append(this.classifiers, classifier);
+      this.alphas = append(this.alphas, 1.0);
+      return;
+   }
+
+   for round := 0; round < this.numRounds; round++ {
+      var classifier Classifier = this.newClassifier();
+      trainWeighted(classifier, tuples, weights, random);
+
+      predictions, _ := classifier.Classify(tuples);
+
+      var incorrect []bool = make([]bool, len(tuples));
+      var error float64 = 0;
+      for i, prediction := range(predictions) {
+         incorrect[i] = (prediction != this.labels[dataLabels[i]]);
+         if (incorrect[i]) {
+            error += weights[i];
+         }
+      }
+
+      // The base classifier must do better than random guessing.
+      if (error >= 1.0 - 1.0 / float64(numLabels)) {
+         if (len(this.classifiers) == 0) {
+            panic(fmt.Sprintf("AdaBoost base classifier is no better than random guessing. Weighted error: %v", error));
+         }
+         break;
+      }
+
+      // A perfect classifier, there is nothing left to boost.
+      if (error <= 0) {
+         this.classifiers = append(this.classifiers, classifier);
+         this.alphas = append(this.alphas, 1.0);
+         break;
+      }
+
+      var alpha float64 = this.learningRate * (math.Log((1.0 - error) / error) + math.Log(float64(numLabels - 1)));
+      this.classifiers = append(this.classifiers, classifier);
+      this.alphas = append(this.alphas, alpha);
+
+      var sum float64 = 0;
+      for i, _ := range(weights) {
+         if (incorrect[i]) {
+            weights[i] *= math.Exp(alpha);
+         }
+         sum += weights[i];
+      }
+
+      for i, _ := range(weights) {
+         weights[i] /= sum;
+      }
+   }
+}
+
+// The confidence is the fraction of the total classifier weight (alpha) that voted for the chosen class.
+func (this AdaBoost) Classify(tuples []base.Tuple) ([]base.Feature, []float64) {
+   tuples = this.reducer.Reduce(tuples);
+
+   var labelIndexes map[base.Feature]int = make(map[base.Feature]int);
+   for i, label := range(this.labels) {
+      labelIndexes[label] = i;
+   }
+
+   // [tuple][label]
+   var votes [][]float64 = make2DFloat(len(tuples), len(this.labels));
+   var totalAlpha float64 = 0;
+
+   for round, classifier := range(this.classifiers) {
+      totalAlpha += this.alphas[round];
+
+      predictions, _ := classifier.Classify(tuples);
+      for i, prediction := range(predictions) {
+         labelIndex, ok := labelIndexes[prediction];
+         if (ok) {
+            votes[i][labelIndex] += this.alphas[round];
+         }
+      }
+   }
+
+   var results []base.Feature = make([]base.Feature, len(tuples));
+   var confidences []float64 = make([]float64, len(tuples));
+
+   for i, tupleVotes := range(votes) {
+      bestIndex, bestVotes := util.Max(tupleVotes);
+      results[i] = this.labels[bestIndex];
+      confidences[i] = bestVotes / totalAlpha;
+   }
+
+   return results, confidences;
+}
+
+// The number of boosting rounds actually kept.
+// May be less than the requested number if a perfect (or useless) base classifier was found.
+func (this AdaBoost) NumRounds() int {
+   return len(this.classifiers);
+}
+
+// Use the classifier's weighted training if it exists, otherwise train on a weighted resample.
+// Weighted classifiers get a copy of |weights| rescaled to sum to the number of tuples
+// (so uniform weights are all 1, the same as Train()).
+func trainWeighted(classifier Classifier, tuples []base.Tuple, weights []float64, random *rand.Rand) {
+   weightedClassifier, ok := classifier.(WeightedClassifier);
+   if (ok) {
+      var total float64 = 0;
+      for _, weight := range(weights) {
+         total += weight;
+      }
+
+      var scaledWeights []float64 = make([]float64, len(weights));
+      for i, weight := range(weights) {
+         scaledWeights[i] = weight * float64(len(weights)) / total;
+      }
+
+      weightedClassifier.TrainWeighted(tuples, scaledWeights);
+      return;
+   }
+
+   var cumulativeWeights []float64 = make([]float64, len(weights));
+   var sum float64 = 0;
+   for i, weight := range(weights) {
+      sum += weight;
+      cumulativeWeights[i] = sum;
+   }
+
+   var sample []base.Tuple = make([]base.Tuple, len(tuples));
+   for i, _ := range(sample) {
+      var index int = sort.SearchFloat64s(cumulativeWeights, random.Float64() * sum);
+      sample[i] = tuples[util.MinInt(index, len(tuples) - 1)];
+   }
+
+   classifier.Train(sample);
+}
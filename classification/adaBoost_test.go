@@ -0,0 +1,289 @@
+package classification
+
+import (
+   "math"
+   "reflect"
+   "testing"
+
+   "github.com/eriq-augustine/goml/base"
+   "github.com/eriq-augustine/goml/features"
+   "github.com/eriq-augustine/goml/optimize"
+)
+
+type adaBoostTestCase struct {
+   Name string
+   Reducer features.Reducer
+   NewClassifier func() Classifier
+   TestData []base.Tuple
+   Input []base.Tuple
+   ExpectedClasses []base.Feature
+   MinExpectedConfidences []float64
+}
+
+func TestAdaBoostBase(t *testing.T) {
+   base.Seed(4);
+
+   // "A" is inside [-2, 2], "B" is outside.
+   // No single stump can separate this.
+   var intervalData []base.Tuple = make([]base.Tuple, 0);
+   for x := -5.0; x <= 5.0; x += 0.5 {
+      var class string = "B";
+      if (x >= -2 && x <= 2) {
+         class = "A";
+      }
+      intervalData = append(intervalData, base.NewFloatTuple([]float64{x}, class));
+   }
+
+   var separableData []base.Tuple = []base.Tuple{
+      base.NewFloatTuple([]float64{10.0, 10.0}, "A"),
+      base.NewFloatTuple([]float64{9.0, 9.0}, "A"),
+      base.NewFloatTuple([]float64{11.0, 11.0}, "A"),
+      base.NewFloatTuple([]float64{-10.0, -10.0}, "B"),
+      base.NewFloatTuple([]float64{-9.0, -9.0}, "B"),
+      base.NewFloatTuple([]float64{-11.0, -11.0}, "B"),
+   };
+
+   var separableInput []base.Tuple = []base.Tuple{
+      base.NewFloatTuple([]float64{8.0, 8.0}, nil),
+      base.NewFloatTuple([]float64{-8.0, -8.0}, nil),
+   };
+
+   var testCases []adaBoostTestCase = []adaBoostTestCase{
+      adaBoostTestCase{
+         "Stumps - Interval",
+         nil,
+         nil,
+         intervalData,
+         []base.Tuple{
+            base.NewFloatTuple([]float64{0.25}, nil),
+            base.NewFloatTuple([]float64{-4.25}, nil),
+            base.NewFloatTuple([]float64{4.25}, nil),
+         },
+         []base.Feature{
+            base.String("A"),
+            base.String("B"),
+            base.String("B"),
+         },
+         []float64{
+            0.5,
+            0.5,
+            0.5,
+         },
+      },
+      adaBoostTestCase{
+         "Stumps - Reduced",
+         features.NewManualReducer([]int{1, 3}),
+         nil,
+         []base.Tuple{
+            base.NewIntTuple([]interface{}{1,  10, 0,  10, 6}, "A"),
+            base.NewIntTuple([]interface{}{2,  9,  0,  9,  5}, "A"),
+            base.NewIntTuple([]interface{}{3,  11, 0,  11, 4}, "A"),
+            base.NewIntTuple([]interface{}{4, -10, 0, -10, 3}, "B"),
+            base.NewIntTuple([]interface{}{5, -9,  0, -9,  2}, "B"),
+            base.NewIntTuple([]interface{}{6, -11, 0, -11, 1}, "B"),
+         },
+         []base.Tuple{
+            base.NewIntTuple([]interface{}{1,  8, 0,  8, 2}, nil),
+            base.NewIntTuple([]interface{}{2, -8, 0, -8, 1}, nil),
+         },
+         []base.Feature{
+            base.String("A"),
+            base.String("B"),
+         },
+         []float64{
+            0.9,
+            0.9,
+         },
+      },
+      adaBoostTestCase{
+         "Weighted - Logistic Regression",
+         nil,
+         func() Classifier {
+            return NewLogisticRegression(nil, optimize.NewGradientDescent(0, 0, 0), -1);
+         },
+         separableData,
+         separableInput,
+         []base.Feature{
+            base.String("A"),
+            base.String("B"),
+         },
+         []float64{
+            0.9,
+            0.9,
+         },
+      },
+      adaBoostTestCase{
+         "Weighted - Knn",
+         nil,
+         func() Classifier {
+            return NewKnn(3, nil, nil);
+         },
+         separableData,
+         separableInput,
+         []base.Feature{
+            base.String("A"),
+            base.String("B"),
+         },
+         []float64{
+            0.9,
+            0.9,
+         },
+      },
+      adaBoostTestCase{
+         "Resampled - Naive Bayes",
+         nil,
+         func() Classifier {
+            return NewNaiveBayes(nil, -1);
+         },
+         separableData,
+         separableInput,
+         []base.Feature{
+            base.String("A"),
+            base.String("B"),
+         },
+         []float64{
+            0.9,
+            0.9,
+         },
+      },
+      adaBoostTestCase{
+         "Stumps - Single Class",
+         nil,
+         nil,
+         []base.Tuple{
+            base.NewFloatTuple([]float64{1.0}, "A"),
+            base.NewFloatTuple([]float64{2.0}, "A"),
+         },
+         []base.Tuple{
+            base.NewFloatTuple([]float64{1.5}, nil),
+            base.NewFloatTuple([]float64{-10.0}, nil),
+         },
+         []base.Feature{
+            base.String("A"),
+            base.String("A"),
+         },
+         []float64{
+            1.0,
+            1.0,
+         },
+      },
+   };
+
+   for _, testCase := range(testCases) {
+      var adaBoost Classifier = NewAdaBoost(testCase.Reducer, testCase.NewClassifier, 0, 0);
+      adaBoost.Train(testCase.TestData);
+      var actualClasses []base.Feature;
+      var actualConfidences []float64;
+
+      actualClasses, actualConfidences = adaBoost.Classify(testCase.Input);
+
+      if (len(actualClasses) != len(testCase.ExpectedClasses)) {
+         t.Errorf("(%s) -- Length of expected (%d) and actual classes (%d) do not match", testCase.Name, len(testCase.ExpectedClasses), len(actualClasses));
+         continue;
+      }
+
+      if (len(actualConfidences) != len(testCase.MinExpectedConfidences)) {
+         t.Errorf("(%s) -- Length of expected (%d) and actual confidences (%d) do not match", testCase.Name, len(testCase.MinExpectedConfidences), len(actualConfidences));
+         continue;
+      }
+
+      // Go over each value explicitly to make output more readable.
+      for i, _ := range(actualClasses) {
+         if (actualClasses[i] != testCase.ExpectedClasses[i]) {
+            t.Errorf("(%s)[%d] -- Bad classification. Expected classes: %v, Got: %v", testCase.Name, i, testCase.ExpectedClasses[i], actualClasses[i]);
+         }
+
+         if (actualConfidences[i] < testCase.MinExpectedConfidences[i] || actualConfidences[i] > 1.0) {
+            t.Errorf("(%s)[%d] -- Bad confidence. Expected min confidence: %v, Got: %v", testCase.Name, i, testCase.MinExpectedConfidences[i], actualConfidences[i]);
+         }
+      }
+   }
+}
+
+// The flipped tuple in the middle cannot be fit in a single round, so boosting runs for several rounds.
+// Every base classifier must keep the weights it was trained with, even as AdaBoost keeps updating its own.
+func TestAdaBoostWeightedRounds(t *testing.T) {
+   base.Seed(5);
+
+   var data []base.Tuple = make([]base.Tuple, 0);
+   for x := -5.0; x <= 5.0; x += 0.5 {
+      var class string = "B";
+      if (x >= -2 && x <= 2 && x != 0) {
+         class = "A";
+      }
+      data = append(data, base.NewFloatTuple([]float64{x}, class));
+   }
+
+   var adaBoost *AdaBoost = NewAdaBoost(nil, func() Classifier {
+      return NewKnn(3, nil, nil);
+   }, 5, 0);
+   adaBoost.Train(data);
+
+   if (adaBoost.NumRounds() < 2) {
+      t.Fatalf("Expected several boosting rounds, got: %d", adaBoost.NumRounds());
+   }
+
+   // The first round was trained with uniform weights (rescaled to all be 1).
+   var firstWeights []float64 = adaBoost.classifiers[0].(*Knn).trainingWeights;
+   for i, weight := range(firstWeights) {
+      if (math.Abs(weight - 1.0) > 1e-9) {
+         t.Errorf("[%d] -- First round weights changed after training. Expected: 1, Got: %v", i, weight);
+      }
+   }
+
+   var secondWeights []float64 = adaBoost.classifiers[1].(*Knn).trainingWeights;
+   if (reflect.DeepEqual(firstWeights, secondWeights)) {
+      t.Errorf("Later rounds should be trained with different weights. Got: %v", secondWeights);
+   }
+
+   results, _ := adaBoost.Classify([]base.Tuple{
+      base.NewFloatTuple([]float64{1.25}, nil),
+      base.NewFloatTuple([]float64{4.25}, nil),
+   });
+
+   var expected []base.Feature = []base.Feature{base.String("A"), base.String("B")};
+   if (!reflect.DeepEqual(results, expected)) {
+      t.Errorf("Bad classification. Expected: %v, Got: %v", expected, results);
+   }
+}
+
+// AdaBoost's normalized weights are rescaled so uniform weights train the same as Train().
+func TestAdaBoostUniformWeights(t *testing.T) {
+   var data []base.Tuple = []base.Tuple{
+      base.NewFloatTuple([]float64{1.0, 2.0}, "A"),
+      base.NewFloatTuple([]float64{2.0, 1.0}, "A"),
+      base.NewFloatTuple([]float64{1.5, 1.0}, "B"),
+      base.NewFloatTuple([]float64{-1.0, -2.0}, "B"),
+      base.NewFloatTuple([]float64{-2.0, -1.0}, "B"),
+   };
+
+   var weights []float64 = make([]float64, len(data));
+   for i, _ := range(weights) {
+      weights[i] = 1.0 / float64(len(data));
+   }
+
+   var unweighted *LogisticRegression = NewLogisticRegression(nil, optimize.NewGradientDescent(0, 0, 0), -1);
+   unweighted.Train(data);
+
+   var weighted *LogisticRegression = NewLogisticRegression(nil, optimize.NewGradientDescent(0, 0, 0), -1);
+   trainWeighted(weighted, data, weights, base.NewRandom());
+
+   var ones *LogisticRegression = NewLogisticRegression(nil, optimize.NewGradientDescent(0, 0, 0), -1);
+   ones.TrainWeighted(data, []float64{1, 1, 1, 1, 1});
+
+   for _, lr := range([]*LogisticRegression{weighted, ones}) {
+      for i, _ := range(unweighted.weights) {
+         for j, _ := range(unweighted.weights[i]) {
+            if (math.Abs(unweighted.weights[i][j] - lr.weights[i][j]) > 1e-9) {
+               t.Errorf("[%d][%d] -- Weighted training differs from Train(). Expected: %v, Got: %v", i, j, unweighted.weights[i][j], lr.weights[i][j]);
+            }
+         }
+      }
+
+      for i, _ := range(unweighted.intercepts) {
+         if (math.Abs(unweighted.intercepts[i] - lr.intercepts[i]) > 1e-9) {
+            t.Errorf("[%d] -- Weighted training differs from Train(). Expected: %v, Got: %v", i, unweighted.intercepts[i], lr.intercepts[i]);
+         }
+      }
+   }
+}
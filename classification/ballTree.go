@@ -0,0 +1,120 @@
+package classification
+
+// A ball tree over the training tuples.
+// Each node is a ball (center + radius) that holds all of its points.
+// By the triangle inequality, no point in a ball can be closer than distance(query, center) - radius.
+// So this works for any distance that is a true metric.
+
+import (
+   "math"
+
+   "github.com/eriq-augustine/goml/base"
+   "github.com/eriq-augustine/goml/util"
+)
+
+type ballTree struct {
+   distancer base.Distancer
+   data []base.NumericTuple
+   root *ballNode
+}
+
+type ballNode struct {
+   center base.NumericTuple
+   radius float64
+
+   // Only set for leaves.
+   indexes []int
+
+   // Only set for internal nodes.
+   left *ballNode
+   right *ballNode
+}
+
+func newBallTree(distancer base.Distancer, data []base.NumericTuple) *ballTree {
+   var points [][]float64 = toFloatSlices(data);
+
+   var tree ballTree = ballTree{
+      distancer: distancer,
+      data: data,
+      root: nil,
+   };
+
+   if (len(data) > 0) {
+      tree.root = tree.buildNode(points, util.RangeSlice(len(data)));
+   }
+
+   return &tree;
+}
+
+// Balls are centered on the mean of their points and split the same way as a KD-tree.
+func (this ballTree) buildNode(points [][]float64, indexes []int) *ballNode {
+   var node *ballNode = &ballNode{};
+
+   var center []float64 = make([]float64, len(points[indexes[0]]));
+   for _, index := range(indexes) {
+      for dimension, value := range(points[index]) {
+         center[dimension] += value;
+      }
+   }
+
+   for dimension, _ := range(center) {
+      center[dimension] /= float64(len(indexes));
+   }
+
+   node.center = base.NewFloatTuple(center, nil);
+   for _, index := range(indexes) {
+      node.radius = math.Max(node.radius, this.distancer.Distance(node.center, this.data[index]));
+   }
+
+   if (len(indexes) <= KNN_INDEX_LEAF_SIZE) {
+      node.indexes = indexes;
+      return node;
+   }
+
+   left, right, _, _, ok := splitAtMedian(points, indexes);
+   if (!ok) {
+      node.indexes = indexes;
+      return node;
+   }
+
+   node.left = this.buildNode(points, left);
+   node.right = this.buildNode(points, right);
+
+   return node;
+}
+
+func (this ballTree) query(tuple base.NumericTuple, k int) []DistanceRecord {
+   var neighbors neighborHeap = make(neighborHeap, 0, k + 1);
+   if (this.root != nil) {
+      this.search(this.root, tuple, this.distancer.Distance(this.root.center, tuple), k, &neighbors);
+   }
+
+   return neighbors.sorted();
+}
+
+// |centerDistance| is the distance from the tuple to the node's center.
+func (this ballTree) search(node *ballNode, tuple base.NumericTuple, centerDistance float64, k int, neighbors *neighborHeap) {
+   // Don't prune on ties, a tied point with a lower index may still be in there.
+   if (centerDistance - node.radius > neighbors.worstDistance(k)) {
+      return;
+   }
+
+   if (node.indexes != nil) {
+      for _, index := range(node.indexes) {
+         neighbors.offer(DistanceRecord{this.distancer.Distance(this.data[index], tuple), index}, k);
+      }
+      return;
+   }
+
+   var leftDistance float64 = this.distancer.Distance(node.left.center, tuple);
+   var rightDistance float64 = this.distancer.Distance(node.right.center, tuple);
+
+   // Closer ball first.
+   if (leftDistance <= rightDistance) {
+      this.search(node.left, tuple, leftDistance, k, neighbors);
+      this.search(node.right, tuple, rightDistance, k, neighbors);
+   } else {
+      this.search(node.right, tuple, rightDistance, k, neighbors);
+      this.search(node.left, tuple, leftDistance, k, neighbors);
+   }
+}
@@ -12,3 +12,10 @@ type Classifier interface {
    // between different classifiers or even instances of the same classifier.
    Classify([]base.Tuple) ([]base.Feature, []float64)
 }
+
+// A Classifier that can take a (non-negative) weight for each training tuple.
+// Training with all weights equal should be the same as just calling Train().
+type WeightedClassifier interface {
+   Classifier
+   TrainWeighted(tuples []base.Tuple, weights []float64)
+}
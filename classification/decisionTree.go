@@ -0,0 +1,651 @@
+package classification
+
+// A CART decision tree.
+// Numeric features are split by a threshold (value <= threshold goes left).
+// Non-numeric (eg StringFeature) and BoolFeature features are split by category (value == category goes left).
+// NilFeature values always go right.
+// Unlike most other classifiers, this trains directly on any base.Tuple (eg base.GeneralTuple).
+
+import (
+   "fmt"
+   "math"
+   "math/rand"
+   "sort"
+
+   "github.com/eriq-augustine/goml/base"
+   "github.com/eriq-augustine/goml/features"
+   "github.com/eriq-augustine/goml/util"
+)
+
+const (
+   TREE_DEFAULT_MIN_SAMPLES_LEAF = 1
+   // Splits must improve impurity by at least this much.
+   TREE_MIN_IMPURITY_DECREASE = 1e-12
+)
+
+type TreeCriterion int
+
+const (
+   TREE_GINI TreeCriterion = iota
+   TREE_ENTROPY
+)
+
+type DecisionTree struct {
+   reducer features.Reducer
+   criterion TreeCriterion
+   // Non-positive means no limit.
+   maxDepth int
+   minSamplesLeaf int
+   // The complexity parameter for minimal cost-complexity pruning.
+   // Non-positive means no pruning.
+   ccpAlpha float64
+
+   labels []base.Feature
+   numFeatures int
+   root *treeNode
+}
+
+// Pass a non-positive value for any numeric param to get the default.
+// By default, there is no max depth and no pruning.
+func NewDecisionTree(reducer features.Reducer, criterion TreeCriterion, maxDepth int, minSamplesLeaf int, ccpAlpha float64) *DecisionTree {
+   if (reducer == nil) {
+      reducer = features.NoReducer{};
+   }
+
+   if (minSamplesLeaf <= 0) {
+      minSamplesLeaf = TREE_DEFAULT_MIN_SAMPLES_LEAF;
+   }
+
+   var tree DecisionTree = DecisionTree{
+      reducer: reducer,
+      criterion: criterion,
+      maxDepth: maxDepth,
+      minSamplesLeaf: minSamplesLeaf,
+      ccpAlpha: ccpAlpha,
+      labels: nil,
+      numFeatures: 0,
+      root: nil,
+   };
+
+   return &tree;
+}
+
+func (this *DecisionTree) Train(tuples []base.Tuple) {
+   this.TrainWeighted(tuples, nil);
+}
+
+// Class counts (and therefore impurities and leaf purities) use the sample weights.
+// Samples with a zero weight are ignored.
+// Passing nil for |weights| weights every tuple equally.
+func (this *DecisionTree) TrainWeighted(tuples []base.Tuple, weights []float64) {
+   if (tuples == nil || len(tuples) == 0) {
+      panic("Must provide tuples for training.")
+   }
+
+   if (weights == nil) {
+      weights = make([]float64, len(tuples));
+      for i, _ := range(weights) {
+         weights[i] = 1;
+      }
+   } else if (len(weights) != len(tuples)) {
+      panic(fmt.Sprintf("Number of weights (%d) must match the number of tuples (%d).", len(weights), len(tuples)));
+   }
+
+   this.reducer.Init(tuples);
+   tuples = this.reducer.Reduce(tuples);
+
+   var dataLabels []int;
+   this.labels, dataLabels = mapLabels(tuples);
+   this.numFeatures = tuples[0].DataSize();
+
+   this.root = this.newBuilder(tuples, dataLabels, weights).build();
+   this.prune();
+}
+
+// The confidence is the purity of the leaf the tuple ended up in
+// (the fraction of training samples in that leaf with the chosen class).
+func (this DecisionTree) Classify(tuples []base.Tuple) ([]base.Feature, []float64) {
+   tuples = this.reducer.Reduce(tuples);
+
+   var results []base.Feature = make([]base.Feature, len(tuples));
+   var confidences []float64 = make([]float64, len(tuples));
+
+   for i, tuple := range(tuples) {
+      var leaf *treeNode = this.root.findLeaf(tuple);
+      results[i] = this.labels[leaf.prediction];
+      confidences[i] = leaf.purity();
+   }
+
+   return results, confidences;
+}
+
+func (this DecisionTree) NumLeaves() int {
+   if (this.root == nil) {
+      return 0;
+   }
+
+   return this.root.numLeaves();
+}
+
+func (this DecisionTree) Depth() int {
+   if (this.root == nil) {
+      return 0;
+   }
+
+   return this.root.depth();
+}
+
+// The total (weighted) impurity decrease from the splits on each feature, normalized to sum to 1.
+// Indexes are into the reduced features.
+func (this DecisionTree) FeatureImportances() []float64 {
+   var importances []float64 = make([]float64, this.numFeatures);
+   if (this.root != nil) {
+      this.root.addImportances(importances);
+   }
+
+   return normalizeImportances(importances);
+}
+
+func (this DecisionTree) newBuilder(tuples []base.Tuple, dataLabels []int, weights []float64) *treeBuilder {
+   return &treeBuilder{
+      data: tuples,
+      labels: dataLabels,
+      weights: weights,
+      numLabels: len(this.labels),
+      numericFeatures: inferNumericFeatures(tuples),
+      criterion: this.criterion,
+      maxDepth: this.maxDepth,
+      minSamplesLeaf: this.minSamplesLeaf,
+      maxFeatures: 0,
+      random: nil,
+   };
+}
+
+// Minimal cost-complexity pruning.
+// Repeatedly collapse the weakest link (the internal node whose subtree improves the
+// weighted impurity the least per extra leaf) until the weakest link is stronger than ccpAlpha.
+// The leaf count and cost of every subtree are computed once, and only the ancestors of a collapsed node are updated.
+func (this *DecisionTree) prune() {
+   if (this.ccpAlpha <= 0) {
+      return;
+   }
+
+   var totalWeight float64 = this.root.weight;
+   var records map[*treeNode]*pruneRecord = make(map[*treeNode]*pruneRecord);
+   this.root.collectPruneRecords(nil, totalWeight, records);
+
+   for {
+      weakest, alpha := this.root.weakestLink(totalWeight, records);
+      if (weakest == nil || alpha > this.ccpAlpha) {
+         break;
+      }
+
+      var record *pruneRecord = records[weakest];
+      var lostLeaves int = record.numLeaves - 1;
+      var addedCost float64 = weakest.impurity * weakest.weight / totalWeight - record.cost;
+
+      for node := record.parent; node != nil; node = records[node].parent {
+         records[node].numLeaves -= lostLeaves;
+         records[node].cost += addedCost;
+      }
+
+      weakest.left = nil;
+      weakest.right = nil;
+      delete(records, weakest);
+   }
+}
+
+// The current leaves under an internal node while pruning.
+type pruneRecord struct {
+   parent *treeNode
+   numLeaves int
+   // The total weighted impurity of the leaves (normalized by the total weight).
+   cost float64
+}
+
+type treeNode struct {
+   // The (weighted) count of training samples that reached this node for each class.
+   classWeights []float64
+   weight float64
+   impurity float64
+   prediction int
+
+   // Only set for internal nodes.
+   left *treeNode
+   right *treeNode
+   featureIndex int
+   numericSplit bool
+   threshold float64
+   category base.Feature
+}
+
+func newTreeNode(classWeights []float64, criterion TreeCriterion) *treeNode {
+   var weight float64 = 0;
+   for _, classWeight := range(classWeights) {
+      weight += classWeight;
+   }
+
+   var prediction int = 0;
+   for i, classWeight := range(classWeights) {
+      if (classWeight > classWeights[prediction]) {
+         prediction = i;
+      }
+   }
+
+   return &treeNode{
+      classWeights: classWeights,
+      weight: weight,
+      impurity: impurity(classWeights, weight, criterion),
+      prediction: prediction,
+   };
+}
+
+func (this treeNode) isLeaf() bool {
+   return this.left == nil;
+}
+
+func (this treeNode) purity() float64 {
+   if (this.weight == 0) {
+      return 0;
+   }
+
+   return this.classWeights[this.prediction] / this.weight;
+}
+
+func (this treeNode) goesLeft(tuple base.Tuple) bool {
+   return splitGoesLeft(tuple.GetData(this.featureIndex), this.numericSplit, this.threshold, this.category);
+}
+
+func (this *treeNode) findLeaf(tuple base.Tuple) *treeNode {
+   var node *treeNode = this;
+   for (!node.isLeaf()) {
+      if (node.goesLeft(tuple)) {
+         node = node.left;
+      } else {
+         node = node.right;
+      }
+   }
+
+   return node;
+}
+
+func (this treeNode) numLeaves() int {
+   if (this.isLeaf()) {
+      return 1;
+   }
+
+   return this.left.numLeaves() + this.right.numLeaves();
+}
+
+func (this treeNode) depth() int {
+   if (this.isLeaf()) {
+      return 0;
+   }
+
+   return 1 + util.MaxInt(this.left.depth(), this.right.depth());
+}
+
+func (this treeNode) addImportances(importances []float64) {
+   if (this.isLeaf()) {
+      return;
+   }
+
+   importances[this.featureIndex] += (this.impurity * this.weight) -
+         (this.left.impurity * this.left.weight) -
+         (this.right.impurity * this.right.weight);
+
+   this.left.addImportances(importances);
+   this.right.addImportances(importances);
+}
+
+// Record every internal node under (and including) this one in a single post-order pass.
+// Returns the number of leaves and the total weighted impurity of the leaves (normalized by |totalWeight|).
+func (this *treeNode) collectPruneRecords(parent *treeNode, totalWeight float64, records map[*treeNode]*pruneRecord) (int, float64) {
+   if (this.isLeaf()) {
+      return 1, this.impurity * this.weight / totalWeight;
+   }
+
+   leftLeaves, leftCost := this.left.collectPruneRecords(this, totalWeight, records);
+   rightLeaves, rightCost := this.right.collectPruneRecords(this, totalWeight, records);
+
+   records[this] = &pruneRecord{
+      parent: parent,
+      numLeaves: leftLeaves + rightLeaves,
+      cost: leftCost + rightCost,
+   };
+
+   return leftLeaves + rightLeaves, leftCost + rightCost;
+}
+
+// Returns the internal node with the smallest effective alpha and that alpha.
+// Returns nil if there are no internal nodes.
+func (this *treeNode) weakestLink(totalWeight float64, records map[*treeNode]*pruneRecord) (*treeNode, float64) {
+   if (this.isLeaf()) {
+      return nil, math.Inf(1);
+   }
+
+   var record *pruneRecord = records[this];
+   var nodeCost float64 = this.impurity * this.weight / totalWeight;
+   var bestNode *treeNode = this;
+   var bestAlpha float64 = (nodeCost - record.cost) / float64(record.numLeaves - 1);
+
+   for _, child := range([]*treeNode{this.left, this.right}) {
+      node, alpha := child.weakestLink(totalWeight, records);
+      if (node != nil && alpha < bestAlpha) {
+         bestNode = node;
+         bestAlpha = alpha;
+      }
+   }
+
+   return bestNode, bestAlpha;
+}
+
+type treeBuilder struct {
+   data []base.Tuple
+   labels []int
+   // Samples with no weight are ignored.
+   weights []float64
+   numLabels int
+   numericFeatures []bool
+
+   criterion TreeCriterion
+   maxDepth int
+   minSamplesLeaf int
+
+   // If positive, only this many randomly chosen features are considered at each split.
+   maxFeatures int
+   // Only required if using maxFeatures.
+   random *rand.Rand
+}
+
+func (this treeBuilder) build() *treeNode {
+   var indexes []int = make([]int, 0, len(this.data));
+   for i, weight := range(this.weights) {
+      if (weight > 0) {
+         indexes = append(indexes, i);
+      }
+   }
+
+   return this.buildNode(indexes, 0);
+}
+
+func (this treeBuilder) buildNode(indexes []int, depth int) *treeNode {
+   var node *treeNode = newTreeNode(this.classWeights(indexes), this.criterion);
+
+   if (node.impurity == 0 || (this.maxDepth > 0 && depth >= this.maxDepth) || len(indexes) < 2 * this.minSamplesLeaf) {
+      return node;
+   }
+
+   var split treeSplit = this.findBestSplit(indexes, node);
+   if (split.featureIndex == -1) {
+      return node;
+   }
+
+   node.featureIndex = split.featureIndex;
+   node.numericSplit = split.numeric;
+   node.threshold = split.threshold;
+   node.category = split.category;
+
+   var leftIndexes []int = make([]int, 0);
+   var rightIndexes []int = make([]int, 0);
+   for _, index := range(indexes) {
+      if (node.goesLeft(this.data[index])) {
+         leftIndexes = append(leftIndexes, index);
+      } else {
+         rightIndexes = append(rightIndexes, index);
+      }
+   }
+
+   node.left = this.buildNode(leftIndexes, depth + 1);
+   node.right = this.buildNode(rightIndexes, depth + 1);
+
+   return node;
+}
+
+type treeSplit struct {
+   // -1 if there is no valid split.
+   featureIndex int
+   numeric bool
+   threshold float64
+   category base.Feature
+   // Decrease in (weighted) impurity.
+   gain float64
+}
+
+func (this treeBuilder) findBestSplit(indexes []int, node *treeNode) treeSplit {
+   var bestSplit treeSplit = treeSplit{featureIndex: -1, gain: TREE_MIN_IMPURITY_DECREASE};
+
+   for _, featureIndex := range(this.candidateFeatures()) {
+      var split treeSplit;
+      if (this.numericFeatures[featureIndex]) {
+         split = this.findBestNumericSplit(indexes, node, featureIndex);
+      } else {
+         split = this.findBestCategoricalSplit(indexes, node, featureIndex);
+      }
+
+      if (split.featureIndex != -1 && split.gain > bestSplit.gain) {
+         bestSplit = split;
+      }
+   }
+
+   return bestSplit;
+}
+
+func (this treeBuilder) candidateFeatures() []int {
+   if (this.maxFeatures <= 0 || this.maxFeatures >= len(this.numericFeatures)) {
+      return util.RangeSlice(len(this.numericFeatures));
+   }
+
+   return this.random.Perm(len(this.numericFeatures))[:this.maxFeatures];
+}
+
+// Sort the samples by value and sweep over them moving one sample at a time to the left.
+func (this treeBuilder) findBestNumericSplit(indexes []int, node *treeNode, featureIndex int) treeSplit {
+   var bestSplit treeSplit = treeSplit{featureIndex: -1, gain: math.Inf(-1)};
+
+   var records []ValueRecord = make([]ValueRecord, 0, len(indexes));
+   for _, index := range(indexes) {
+      var feature base.Feature = this.data[index].GetData(featureIndex);
+      if (!isNilFeature(feature)) {
+         records = append(records, ValueRecord{numericFeatureValue(feature), index});
+      }
+   }
+   sort.Sort(ByValue(records));
+
+   var leftWeights []float64 = make([]float64, this.numLabels);
+   var rightWeights []float64 = append([]float64(nil), node.classWeights...);
+   var leftWeight float64 = 0;
+
+   for i := 0; i < len(records) - 1; i++ {
+      var index int = records[i].Index;
+      leftWeights[this.labels[index]] += this.weights[index];
+      rightWeights[this.labels[index]] -= this.weights[index];
+      leftWeight += this.weights[index];
+
+      if (records[i].Value == records[i + 1].Value) {
+         continue;
+      }
+
+      if (i + 1 < this.minSamplesLeaf || len(indexes) - (i + 1) < this.minSamplesLeaf) {
+         continue;
+      }
+
+      var gain float64 = this.gain(node, leftWeights, leftWeight, rightWeights, node.weight - leftWeight);
+      if (gain > bestSplit.gain) {
+         bestSplit = treeSplit{
+            featureIndex: featureIndex,
+            numeric: true,
+            threshold: (records[i].Value + records[i + 1].Value) / 2.0,
+            gain: gain,
+         };
+      }
+   }
+
+   return bestSplit;
+}
+
+// Try each category against all the rest.
+func (this treeBuilder) findBestCategoricalSplit(indexes []int, node *treeNode, featureIndex int) treeSplit {
+   var bestSplit treeSplit = treeSplit{featureIndex: -1, gain: math.Inf(-1)};
+
+   // {category -> [class weight, ...]}
+   var categoryWeights map[base.Feature][]float64 = make(map[base.Feature][]float64);
+   var categoryCounts map[base.Feature]int = make(map[base.Feature]int);
+   // Keep the categories in the order seen so ties are broken consistently.
+   var categories []base.Feature = make([]base.Feature, 0);
+
+   for _, index := range(indexes) {
+      var feature base.Feature = this.data[index].GetData(featureIndex);
+      if (isNilFeature(feature)) {
+         continue;
+      }
+
+      _, ok := categoryWeights[feature];
+      if (!ok) {
+         categoryWeights[feature] = make([]float64, this.numLabels);
+         categories = append(categories, feature);
+      }
+
+      categoryWeights[feature][this.labels[index]] += this.weights[index];
+      categoryCounts[feature]++;
+   }
+
+   for _, category := range(categories) {
+      var leftCount int = categoryCounts[category];
+      if (leftCount < this.minSamplesLeaf || len(indexes) - leftCount < this.minSamplesLeaf) {
+         continue;
+      }
+
+      var leftWeights []float64 = categoryWeights[category];
+      var rightWeights []float64 = make([]float64, this.numLabels);
+      var leftWeight float64 = 0;
+      for i, _ := range(rightWeights) {
+         rightWeights[i] = node.classWeights[i] - leftWeights[i];
+         leftWeight += leftWeights[i];
+      }
+
+      var gain float64 = this.gain(node, leftWeights, leftWeight, rightWeights, node.weight - leftWeight);
+      if (gain > bestSplit.gain) {
+         bestSplit = treeSplit{
+            featureIndex: featureIndex,
+            numeric: false,
+            category: category,
+            gain: gain,
+         };
+      }
+   }
+
+   return bestSplit;
+}
+
+func (this treeBuilder) gain(node *treeNode, leftWeights []float64, leftWeight float64, rightWeights []float64, rightWeight float64) float64 {
+   return node.impurity * node.weight -
+         (impurity(leftWeights, leftWeight, this.criterion) * leftWeight) -
+         (impurity(rightWeights, rightWeight, this.criterion) * rightWeight);
+}
+
+func (this treeBuilder) classWeights(indexes []int) []float64 {
+   var classWeights []float64 = make([]float64, this.numLabels);
+   for _, index := range(indexes) {
+      classWeights[this.labels[index]] += this.weights[index];
+   }
+   return classWeights;
+}
+
+func impurity(classWeights []float64, totalWeight float64, criterion TreeCriterion) float64 {
+   if (totalWeight <= 0) {
+      return 0;
+   }
+
+   var rtn float64 = 0;
+
+   switch criterion {
+   case TREE_GINI:
+      rtn = 1;
+      for _, classWeight := range(classWeights) {
+         rtn -= math.Pow(classWeight / totalWeight, 2);
+      }
+   case TREE_ENTROPY:
+      for _, classWeight := range(classWeights) {
+         if (classWeight <= 0) {
+            continue;
+         }
+
+         var probability float64 = classWeight / totalWeight;
+         rtn -= probability * math.Log2(probability);
+      }
+   default:
+      panic(fmt.Sprintf("Unknown tree criterion: %d", criterion));
+   }
+
+   // Avoid tiny negatives from floating point error.
+   return math.Max(0, rtn);
+}
+
+func splitGoesLeft(feature base.Feature, numeric bool, threshold float64, category base.Feature) bool {
+   if (isNilFeature(feature)) {
+      return false;
+   }
+
+   if (numeric) {
+      return numericFeatureValue(feature) <= threshold;
+   }
+
+   return feature == category;
+}
+
+// Scale |importances| (in place) to sum to 1 (unless they are all zero).
+func normalizeImportances(importances []float64) []float64 {
+   var sum float64 = 0;
+   for _, importance := range(importances) {
+      sum += importance;
+   }
+
+   if (sum > 0) {
+      for i, _ := range(importances) {
+         importances[i] /= sum;
+      }
+   }
+
+   return importances;
+}
+
+// Decide which features are numeric (split by threshold) based off of the first non-nil value.
+// BoolFeatures are treated as categorical.
+func inferNumericFeatures(tuples []base.Tuple) []bool {
+   var numericFeatures []bool = make([]bool, tuples[0].DataSize());
+
+   for featureIndex, _ := range(numericFeatures) {
+      for _, tuple := range(tuples) {
+         var feature base.Feature = tuple.GetData(featureIndex);
+         if (isNilFeature(feature)) {
+            continue;
+         }
+
+         _, isBool := feature.(base.BoolFeature);
+         numericFeatures[featureIndex] = feature.IsNumeric() && !isBool;
+         break;
+      }
+   }
+
+   return numericFeatures;
+}
+
+type ValueRecord struct {
+   Value float64
+   Index int
+}
+
+type ByValue []ValueRecord;
+
+func (a ByValue) Len() int {
+   return len(a);
+}
+
+func (a ByValue) Swap(i, j int) {
+   a[i], a[j] = a[j], a[i];
+}
+
+func (a ByValue) Less(i, j int) bool {
+   return a[i].Value < a[j].Value;
+}
@@ -0,0 +1,340 @@
+package classification
+
+import (
+   "math"
+   "math/rand"
+   "testing"
+
+   "github.com/eriq-augustine/goml/base"
+   "github.com/eriq-augustine/goml/features"
+   "github.com/eriq-augustine/goml/util"
+)
+
+type treeTestCase struct {
+   Name string
+   Reducer features.Reducer
+   Criterion TreeCriterion
+   MaxDepth int
+   MinSamplesLeaf int
+   CcpAlpha float64
+   TestData []base.Tuple
+   Input []base.Tuple
+   ExpectedClasses []base.Feature
+   ExpectedConfidences []float64
+   ExpectedLeaves int
+}
+
+func TestDecisionTreeBase(t *testing.T) {
+   var testCases []treeTestCase = []treeTestCase{
+      treeTestCase{
+         "Base",
+         features.NoReducer{},
+         TREE_GINI,
+         0,
+         0,
+         0,
+         []base.Tuple{
+            base.NewIntTuple([]interface{}{10, 10}, "A"),
+            base.NewIntTuple([]interface{}{9, 9}, "A"),
+            base.NewIntTuple([]interface{}{11, 11}, "A"),
+            base.NewIntTuple([]interface{}{-10, -10}, "B"),
+            base.NewIntTuple([]interface{}{-9, -9}, "B"),
+            base.NewIntTuple([]interface{}{-11, -11}, "B"),
+         },
+         []base.Tuple{
+            base.NewIntTuple([]interface{}{8, 8}, nil),
+            base.NewIntTuple([]interface{}{-8, -8}, nil),
+         },
+         []base.Feature{
+            base.String("A"),
+            base.String("B"),
+         },
+         []float64{
+            1.0,
+            1.0,
+         },
+         2,
+      },
+      treeTestCase{
+         "Reduced - Entropy",
+         features.NewManualReducer([]int{1, 3}),
+         TREE_ENTROPY,
+         0,
+         0,
+         0,
+         []base.Tuple{
+            base.NewIntTuple([]interface{}{1,  10, 0,  10, 6}, "A"),
+            base.NewIntTuple([]interface{}{2,  9,  0,  9,  5}, "A"),
+            base.NewIntTuple([]interface{}{3,  11, 0,  11, 4}, "A"),
+            base.NewIntTuple([]interface{}{4, -10, 0, -10, 3}, "B"),
+            base.NewIntTuple([]interface{}{5, -9,  0, -9,  2}, "B"),
+            base.NewIntTuple([]interface{}{6, -11, 0, -11, 1}, "B"),
+         },
+         []base.Tuple{
+            base.NewIntTuple([]interface{}{1,  8, 0,  8, 2}, nil),
+            base.NewIntTuple([]interface{}{2, -8, 0, -8, 1}, nil),
+         },
+         []base.Feature{
+            base.String("A"),
+            base.String("B"),
+         },
+         []float64{
+            1.0,
+            1.0,
+         },
+         2,
+      },
+      treeTestCase{
+         "Mixed Features",
+         nil,
+         TREE_GINI,
+         0,
+         0,
+         0,
+         []base.Tuple{
+            base.NewTuple([]interface{}{"red", true, 1.0}, "A"),
+            base.NewTuple([]interface{}{"red", false, 2.0}, "A"),
+            base.NewTuple([]interface{}{"red", nil, 3.0}, "A"),
+            base.NewTuple([]interface{}{"blue", true, 10.0}, "B"),
+            base.NewTuple([]interface{}{"blue", true, 11.0}, "B"),
+            base.NewTuple([]interface{}{"blue", false, 12.0}, "C"),
+            base.NewTuple([]interface{}{"blue", false, 13.0}, "C"),
+         },
+         []base.Tuple{
+            base.NewTuple([]interface{}{"red", true, 11.0}, nil),
+            base.NewTuple([]interface{}{"blue", true, 1.0}, nil),
+            base.NewTuple([]interface{}{"blue", false, nil}, nil),
+         },
+         []base.Feature{
+            base.String("A"),
+            base.String("B"),
+            base.String("C"),
+         },
+         []float64{
+            1.0,
+            1.0,
+            1.0,
+         },
+         3,
+      },
+      treeTestCase{
+         "Max Depth",
+         nil,
+         TREE_GINI,
+         1,
+         0,
+         0,
+         []base.Tuple{
+            base.NewIntTuple([]interface{}{1}, "A"),
+            base.NewIntTuple([]interface{}{2}, "A"),
+            base.NewIntTuple([]interface{}{3}, "A"),
+            base.NewIntTuple([]interface{}{4}, "B"),
+            base.NewIntTuple([]interface{}{5}, "B"),
+            base.NewIntTuple([]interface{}{6}, "A"),
+         },
+         []base.Tuple{
+            base.NewIntTuple([]interface{}{0}, nil),
+            base.NewIntTuple([]interface{}{6}, nil),
+         },
+         []base.Feature{
+            base.String("A"),
+            base.String("B"),
+         },
+         []float64{
+            1.0,
+            2.0 / 3.0,
+         },
+         2,
+      },
+      treeTestCase{
+         "Min Samples Leaf",
+         nil,
+         TREE_GINI,
+         0,
+         2,
+         0,
+         []base.Tuple{
+            base.NewIntTuple([]interface{}{1}, "A"),
+            base.NewIntTuple([]interface{}{2}, "A"),
+            base.NewIntTuple([]interface{}{3}, "A"),
+            base.NewIntTuple([]interface{}{4}, "B"),
+            base.NewIntTuple([]interface{}{5}, "A"),
+            base.NewIntTuple([]interface{}{6}, "A"),
+         },
+         []base.Tuple{
+            base.NewIntTuple([]interface{}{4}, nil),
+         },
+         []base.Feature{
+            base.String("A"),
+         },
+         []float64{
+            2.0 / 3.0,
+         },
+         2,
+      },
+      treeTestCase{
+         "Pruned",
+         nil,
+         TREE_GINI,
+         0,
+         0,
+         0.1,
+         []base.Tuple{
+            base.NewIntTuple([]interface{}{1}, "A"),
+            base.NewIntTuple([]interface{}{2}, "A"),
+            base.NewIntTuple([]interface{}{3}, "A"),
+            base.NewIntTuple([]interface{}{4}, "B"),
+            base.NewIntTuple([]interface{}{5}, "A"),
+            base.NewIntTuple([]interface{}{6}, "A"),
+            base.NewIntTuple([]interface{}{7}, "A"),
+            base.NewIntTuple([]interface{}{8}, "A"),
+            base.NewIntTuple([]interface{}{9}, "A"),
+            base.NewIntTuple([]interface{}{10}, "A"),
+         },
+         []base.Tuple{
+            base.NewIntTuple([]interface{}{4}, nil),
+         },
+         []base.Feature{
+            base.String("A"),
+         },
+         []float64{
+            0.9,
+         },
+         1,
+      },
+   };
+
+   for _, testCase := range(testCases) {
+      var tree *DecisionTree = NewDecisionTree(testCase.Reducer, testCase.Criterion, testCase.MaxDepth, testCase.MinSamplesLeaf, testCase.CcpAlpha);
+      tree.Train(testCase.TestData);
+      var actualClasses []base.Feature;
+      var actualConfidences []float64;
+
+      actualClasses, actualConfidences = tree.Classify(testCase.Input);
+
+      if (tree.NumLeaves() != testCase.ExpectedLeaves) {
+         t.Errorf("(%s) -- Bad number of leaves. Expected: %d, Got: %d", testCase.Name, testCase.ExpectedLeaves, tree.NumLeaves());
+      }
+
+      if (len(actualClasses) != len(testCase.ExpectedClasses)) {
+         t.Errorf("(%s) -- Length of expected (%d) and actual classes (%d) do not match", testCase.Name, len(testCase.ExpectedClasses), len(actualClasses));
+         continue;
+      }
+
+      if (len(actualConfidences) != len(testCase.ExpectedConfidences)) {
+         t.Errorf("(%s) -- Length of expected (%d) and actual confidences (%d) do not match", testCase.Name, len(testCase.ExpectedConfidences), len(actualConfidences));
+         continue;
+      }
+
+      // Go over each value explicitly to make output more readable.
+      for i, _ := range(actualClasses) {
+         if (actualClasses[i] != testCase.ExpectedClasses[i]) {
+            t.Errorf("(%s)[%d] -- Bad classification. Expected classes: %v, Got: %v", testCase.Name, i, testCase.ExpectedClasses[i], actualClasses[i]);
+         }
+
+         if (!util.FloatEquals(actualConfidences[i], testCase.ExpectedConfidences[i])) {
+            t.Errorf("(%s)[%d] -- Bad classification. Expected confidence: %v, Got: %v", testCase.Name, i, testCase.ExpectedConfidences[i], actualConfidences[i]);
+         }
+      }
+   }
+}
+
+// Pruning keeps running leaf counts and costs.
+// It must give the same tree as recomputing them from scratch at every step.
+func TestDecisionTreePruneMatchesRecompute(t *testing.T) {
+   // Quadrants with some random labels, so the unpruned tree is deep.
+   var random *rand.Rand = rand.New(rand.NewSource(4));
+   var data []base.Tuple = make([]base.Tuple, 500);
+   for i, _ := range(data) {
+      var x float64 = random.Float64();
+      var y float64 = random.Float64();
+
+      var class string = "A";
+      if ((x < 0.5) != (y < 0.5)) {
+         class = "B";
+      }
+
+      if (random.Float64() < 0.15) {
+         class = "C";
+      }
+
+      data[i] = base.NewFloatTuple([]float64{x, y}, class);
+   }
+
+   var unpruned *DecisionTree = NewDecisionTree(nil, TREE_GINI, 0, 0, 0);
+   unpruned.Train(data);
+
+   for _, alpha := range([]float64{0.002, 0.003, 0.005, 0.01, 0.05}) {
+      var tree *DecisionTree = NewDecisionTree(nil, TREE_GINI, 0, 0, alpha);
+      tree.Train(data);
+
+      var expected *DecisionTree = NewDecisionTree(nil, TREE_GINI, 0, 0, 0);
+      expected.Train(data);
+      recomputePrune(expected.root, alpha);
+
+      if (tree.NumLeaves() != expected.NumLeaves()) {
+         t.Errorf("(%v) -- Bad number of leaves. Expected: %d, Got: %d", alpha, expected.NumLeaves(), tree.NumLeaves());
+      }
+
+      if (tree.NumLeaves() >= unpruned.NumLeaves()) {
+         t.Errorf("(%v) -- Expected pruning. Unpruned leaves: %d, Got: %d", alpha, unpruned.NumLeaves(), tree.NumLeaves());
+      }
+
+      actual, _ := tree.Classify(data);
+      reference, _ := expected.Classify(data);
+      for i, _ := range(actual) {
+         if (actual[i] != reference[i]) {
+            t.Errorf("(%v)[%d] -- Bad classification. Expected: %v, Got: %v", alpha, i, reference[i], actual[i]);
+            break;
+         }
+      }
+   }
+}
+
+// Cost-complexity pruning that recomputes every subtree at every step.
+func recomputePrune(root *treeNode, ccpAlpha float64) {
+   var numLeaves func(node *treeNode) int;
+   numLeaves = func(node *treeNode) int {
+      if (node.isLeaf()) {
+         return 1;
+      }
+      return numLeaves(node.left) + numLeaves(node.right);
+   };
+
+   var cost func(node *treeNode) float64;
+   cost = func(node *treeNode) float64 {
+      if (node.isLeaf()) {
+         return node.impurity * node.weight / root.weight;
+      }
+      return cost(node.left) + cost(node.right);
+   };
+
+   var weakest func(node *treeNode) (*treeNode, float64);
+   weakest = func(node *treeNode) (*treeNode, float64) {
+      if (node.isLeaf()) {
+         return nil, math.Inf(1);
+      }
+
+      var bestNode *treeNode = node;
+      var bestAlpha float64 = (node.impurity * node.weight / root.weight - cost(node)) / float64(numLeaves(node) - 1);
+      for _, child := range([]*treeNode{node.left, node.right}) {
+         childNode, alpha := weakest(child);
+         if (childNode != nil && alpha < bestAlpha) {
+            bestNode = childNode;
+            bestAlpha = alpha;
+         }
+      }
+
+      return bestNode, bestAlpha;
+   };
+
+   for {
+      node, alpha := weakest(root);
+      if (node == nil || alpha > ccpAlpha) {
+         break;
+      }
+
+      node.left = nil;
+      node.right = nil;
+   }
+}
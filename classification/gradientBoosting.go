@@ -0,0 +1,382 @@
+package classification
+
+// Gradient boosted regression trees (Friedman 2001).
+// Classification uses the multiclass log loss (softmax over one tree per class each round).
+// Regression uses squared error with the (numeric) class of each tuple as the target.
+// Early stopping is done by holding out a fraction of the training data.
+
+import (
+   "fmt"
+   "math"
+   "math/rand"
+
+   "github.com/eriq-augustine/goml/base"
+   "github.com/eriq-augustine/goml/features"
+   "github.com/eriq-augustine/goml/util"
+)
+
+const (
+   GB_DEFAULT_NUM_ROUNDS = 100
+   GB_DEFAULT_LEARNING_RATE = 0.1
+   GB_DEFAULT_MAX_DEPTH = 3
+   GB_DEFAULT_SUBSAMPLE = 1.0
+   GB_MIN_SAMPLES_LEAF = 1
+   // Stop after this many rounds without improvement on the held-out data.
+   GB_EARLY_STOPPING_ROUNDS = 10
+)
+
+type GBLoss int
+
+const (
+   GB_LOG_LOSS GBLoss = iota
+   GB_SQUARED_ERROR
+)
+
+type GradientBoosting struct {
+   reducer features.Reducer
+   loss GBLoss
+   numRounds int
+   learningRate float64
+   maxDepth int
+   // The fraction of the training data (sampled without replacement) used to fit each round.
+   subsample float64
+   // The fraction of the training data held out for early stopping.
+   // Zero means no early stopping.
+   validationFraction float64
+
+   // Only used for GB_LOG_LOSS.
+   labels []base.Feature
+   // One per class for GB_LOG_LOSS, just one for GB_SQUARED_ERROR.
+   initialScores []float64
+   // [round][class]
+   stages [][]*regressionNode
+}
+
+// Pass a non-positive value for any numeric param to get the default.
+// (Which means no early stopping for |validationFraction|.)
+// |subsample| must be at most 1 and |validationFraction| must be less than 1.
+func NewGradientBoosting(reducer features.Reducer, loss GBLoss, numRounds int, learningRate float64,
+                         maxDepth int, subsample float64, validationFraction float64) *GradientBoosting {
+   if (reducer == nil) {
+      reducer = features.NoReducer{};
+   }
+
+   if (numRounds <= 0) {
+      numRounds = GB_DEFAULT_NUM_ROUNDS;
+   }
+
+   if (learningRate <= 0) {
+      learningRate = GB_DEFAULT_LEARNING_RATE;
+   }
+
+   if (maxDepth <= 0) {
+      maxDepth = GB_DEFAULT_MAX_DEPTH;
+   }
+
+   if (subsample <= 0) {
+      subsample = GB_DEFAULT_SUBSAMPLE;
+   }
+
+   if (subsample > 1) {
+      panic(fmt.Sprintf("Subsample must be in (0, 1], got: %v", subsample));
+   }
+
+   if (validationFraction <= 0) {
+      validationFraction = 0;
+   }
+
+   if (validationFraction >= 1) {
+      panic(fmt.Sprintf("Validation fraction must be in [0, 1), got: %v", validationFraction));
+   }
+
+   var gb GradientBoosting = GradientBoosting{
+      reducer: reducer,
+      loss: loss,
+      numRounds: numRounds,
+      learningRate: learningRate,
+      maxDepth: maxDepth,
+      subsample: subsample,
+      validationFraction: validationFraction,
+   };
+
+   return &gb;
+}
+
+func (this *GradientBoosting) Train(tuples []base.Tuple) {
+   if (tuples == nil || len(tuples) == 0) {
+      panic("Must provide tuples for training.")
+   }
+
+   this.reducer.Init(tuples);
+   tuples = this.reducer.Reduce(tuples);
+
+   var random *rand.Rand = base.NewRandom();
+
+   // [tuple][output]
+   var targets [][]float64 = this.makeTargets(tuples);
+   var numOutputs int = len(targets[0]);
+
+   var trainIndexes []int = random.Perm(len(tuples));
+   var validationIndexes []int = make([]int, 0);
+   var numValidation int = int(this.validationFraction * float64(len(tuples)));
+   if (numValidation > 0 && numValidation < len(tuples)) {
+      validationIndexes = trainIndexes[:numValidation];
+      trainIndexes = trainIndexes[numValidation:];
+   }
+
+   this.initialScores = this.calcInitialScores(targets, trainIndexes);
+
+   // [tuple][output]
+   var scores [][]float64 = make([][]float64, len(tuples));
+   for i, _ := range(scores) {
+      scores[i] = append([]float64(nil), this.initialScores...);
+   }
+
+   var builder regressionTreeBuilder = regressionTreeBuilder{
+      data: tuples,
+      targets: make([]float64, len(tuples)),
+      numericFeatures: inferNumericFeatures(tuples),
+      maxDepth: this.maxDepth,
+      minSamplesLeaf: GB_MIN_SAMPLES_LEAF,
+      leafValue: nil,
+   };
+
+   if (this.loss == GB_LOG_LOSS) {
+      builder.leafValue = func(residuals []float64, indexes []int) float64 {
+         return logLossLeafValue(residuals, indexes, numOutputs);
+      };
+   }
+
+   this.stages = make([][]*regressionNode, 0, this.numRounds);
+   var bestLoss float64 = math.Inf(1);
+   var bestRounds int = 0;
+
+   for round := 0; round < this.numRounds; round++ {
+      var sampleIndexes []int = subsampleIndexes(trainIndexes, this.subsample, random);
+
+      // Get all the gradients before touching the scores.
+      // [output][tuple]
+      var residuals [][]float64 = this.negativeGradients(scores, targets, sampleIndexes, numOutputs);
+
+      var stage []*regressionNode = make([]*regressionNode, numOutputs);
+      for output := 0; output < numOutputs; output++ {
+         builder.targets = residuals[output];
+         stage[output] = builder.build(sampleIndexes);
+      }
+
+      for i, tuple := range(tuples) {
+         for output, tree := range(stage) {
+            scores[i][output] += this.learningRate * tree.predict(tuple);
+         }
+      }
+
+      this.stages = append(this.stages, stage);
+
+      if (len(validationIndexes) == 0) {
+         continue;
+      }
+
+      var loss float64 = this.calcLoss(scores, targets, validationIndexes);
+      if (loss < bestLoss) {
+         bestLoss = loss;
+         bestRounds = len(this.stages);
+      } else if (len(this.stages) - bestRounds >= GB_EARLY_STOPPING_ROUNDS) {
+         break;
+      }
+   }
+
+   if (len(validationIndexes) != 0) {
+      this.stages = this.stages[:bestRounds];
+   }
+}
+
+// For GB_LOG_LOSS, the confidence is the probability of the chosen class.
+// For GB_SQUARED_ERROR, predictions are returned as base.FloatFeature and confidences are nil.
+func (this GradientBoosting) Classify(tuples []base.Tuple) ([]base.Feature, []float64) {
+   tuples = this.reducer.Reduce(tuples);
+
+   var results []base.Feature = make([]base.Feature, len(tuples));
+
+   if (this.loss == GB_SQUARED_ERROR) {
+      for i, tuple := range(tuples) {
+         results[i] = base.Float(this.scores(tuple)[0]);
+      }
+      return results, nil;
+   }
+
+   var confidences []float64 = make([]float64, len(tuples));
+   for i, tuple := range(tuples) {
+      var probabilities []float64 = softmax(this.scores(tuple));
+      bestIndex, bestProbability := util.Max(probabilities);
+
+      results[i] = this.labels[bestIndex];
+      confidences[i] = bestProbability;
+   }
+
+   return results, confidences;
+}
+
+// Get the raw predictions for a GB_SQUARED_ERROR model.
+func (this GradientBoosting) Predict(tuples []base.Tuple) []float64 {
+   if (this.loss != GB_SQUARED_ERROR) {
+      panic("Predict() is only supported for GB_SQUARED_ERROR, use Classify().");
+   }
+
+   tuples = this.reducer.Reduce(tuples);
+
+   var predictions []float64 = make([]float64, len(tuples));
+   for i, tuple := range(tuples) {
+      predictions[i] = this.scores(tuple)[0];
+   }
+
+   return predictions;
+}
+
+// The number of boosting rounds actually kept (after early stopping).
+func (this GradientBoosting) NumRounds() int {
+   return len(this.stages);
+}
+
+func (this GradientBoosting) scores(tuple base.Tuple) []float64 {
+   var scores []float64 = append([]float64(nil), this.initialScores...);
+   for _, stage := range(this.stages) {
+      for output, tree := range(stage) {
+         scores[output] += this.learningRate * tree.predict(tuple);
+      }
+   }
+   return scores;
+}
+
+// GB_LOG_LOSS: one hot encoding of the label.
+// GB_SQUARED_ERROR: the numeric class.
+func (this *GradientBoosting) makeTargets(tuples []base.Tuple) [][]float64 {
+   var targets [][]float64 = make([][]float64, len(tuples));
+
+   switch this.loss {
+   case GB_LOG_LOSS:
+      var dataLabels []int;
+      this.labels, dataLabels = mapLabels(tuples);
+
+      for i, label := range(dataLabels) {
+         targets[i] = make([]float64, len(this.labels));
+         targets[i][label] = 1;
+      }
+   case GB_SQUARED_ERROR:
+      for i, tuple := range(tuples) {
+         numericClass, ok := tuple.GetClass().(base.NumericFeature);
+         if (!ok) {
+            panic(fmt.Sprintf("GB_SQUARED_ERROR requires numeric classes. Tuple[%d] class: %T", i, tuple.GetClass()));
+         }
+         targets[i] = []float64{numericClass.NumericValue()};
+      }
+   default:
+      panic(fmt.Sprintf("Unknown boosting loss: %d", this.loss));
+   }
+
+   return targets;
+}
+
+// GB_LOG_LOSS: the (smoothed) log prior of each class.
+// GB_SQUARED_ERROR: the mean target.
+func (this GradientBoosting) calcInitialScores(targets [][]float64, indexes []int) []float64 {
+   var numOutputs int = len(targets[0]);
+   var sums []float64 = make([]float64, numOutputs);
+
+   for _, index := range(indexes) {
+      for output, target := range(targets[index]) {
+         sums[output] += target;
+      }
+   }
+
+   var scores []float64 = make([]float64, numOutputs);
+   for output, sum := range(sums) {
+      if (this.loss == GB_LOG_LOSS) {
+         scores[output] = math.Log((sum + 1.0) / float64(len(indexes) + numOutputs));
+      } else {
+         scores[output] = sum / float64(len(indexes));
+      }
+   }
+
+   return scores;
+}
+
+// Returns [output][tuple] (only |indexes| will be populated).
+func (this GradientBoosting) negativeGradients(scores [][]float64, targets [][]float64, indexes []int, numOutputs int) [][]float64 {
+   var residuals [][]float64 = make2DFloat(numOutputs, len(scores));
+
+   for _, index := range(indexes) {
+      var predictions []float64 = scores[index];
+      if (this.loss == GB_LOG_LOSS) {
+         predictions = softmax(scores[index]);
+      }
+
+      for output := 0; output < numOutputs; output++ {
+         residuals[output][index] = targets[index][output] - predictions[output];
+      }
+   }
+
+   return residuals;
+}
+
+// GB_LOG_LOSS: mean negative log likelihood.
+// GB_SQUARED_ERROR: mean squared error.
+func (this GradientBoosting) calcLoss(scores [][]float64, targets [][]float64, indexes []int) float64 {
+   var loss float64 = 0;
+
+   for _, index := range(indexes) {
+      if (this.loss == GB_LOG_LOSS) {
+         var normalization float64 = util.LogSumExp(scores[index]);
+         for output, target := range(targets[index]) {
+            loss -= target * (scores[index][output] - normalization);
+         }
+      } else {
+         loss += math.Pow(targets[index][0] - scores[index][0], 2);
+      }
+   }
+
+   return loss / float64(len(indexes));
+}
+
+// A single Newton-Raphson step for the multiclass log loss.
+// gamma = (K - 1) / K * sum(r) / sum(|r| * (1 - |r|))
+func logLossLeafValue(residuals []float64, indexes []int, numClasses int) float64 {
+   var numerator float64 = 0;
+   var denominator float64 = 0;
+
+   for _, index := range(indexes) {
+      numerator += residuals[index];
+      denominator += math.Abs(residuals[index]) * (1.0 - math.Abs(residuals[index]));
+   }
+
+   if (denominator < util.EPSILON) {
+      return 0;
+   }
+
+   return float64(numClasses - 1) / float64(numClasses) * numerator / denominator;
+}
+
+func softmax(scores []float64) []float64 {
+   var normalization float64 = util.LogSumExp(scores);
+
+   var probabilities []float64 = make([]float64, len(scores));
+   for i, score := range(scores) {
+      probabilities[i] = math.Exp(score - normalization);
+   }
+
+   return probabilities;
+}
+
+// Choose a random |fraction| of |indexes| without replacement.
+func subsampleIndexes(indexes []int, fraction float64, random *rand.Rand) []int {
+   if (fraction >= 1) {
+      return indexes;
+   }
+
+   var size int = util.MaxInt(1, int(fraction * float64(len(indexes))));
+   var chosen []int = make([]int, size);
+   for i, permutationIndex := range(random.Perm(len(indexes))[:size]) {
+      chosen[i] = indexes[permutationIndex];
+   }
+
+   return chosen;
+}
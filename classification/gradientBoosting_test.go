@@ -0,0 +1,198 @@
+package classification
+
+import (
+   "math"
+   "testing"
+
+   "github.com/eriq-augustine/goml/base"
+   "github.com/eriq-augustine/goml/features"
+)
+
+type gbTestCase struct {
+   Name string
+   Reducer features.Reducer
+   TestData []base.Tuple
+   Input []base.Tuple
+   ExpectedClasses []base.Feature
+   MinExpectedConfidences []float64
+}
+
+func TestGradientBoostingBase(t *testing.T) {
+   base.Seed(4);
+
+   var testCases []gbTestCase = []gbTestCase{
+      gbTestCase{
+         "Base",
+         features.NoReducer{},
+         []base.Tuple{
+            base.NewIntTuple([]interface{}{10, 10}, "A"),
+            base.NewIntTuple([]interface{}{9, 9}, "A"),
+            base.NewIntTuple([]interface{}{11, 11}, "A"),
+            base.NewIntTuple([]interface{}{-10, -10}, "B"),
+            base.NewIntTuple([]interface{}{-9, -9}, "B"),
+            base.NewIntTuple([]interface{}{-11, -11}, "B"),
+         },
+         []base.Tuple{
+            base.NewIntTuple([]interface{}{8, 8}, nil),
+            base.NewIntTuple([]interface{}{-8, -8}, nil),
+         },
+         []base.Feature{
+            base.String("A"),
+            base.String("B"),
+         },
+         []float64{
+            0.9,
+            0.9,
+         },
+      },
+      gbTestCase{
+         "Reduced",
+         features.NewManualReducer([]int{1, 3}),
+         []base.Tuple{
+            base.NewIntTuple([]interface{}{1,  10, 0,  10, 6}, 1),
+            base.NewIntTuple([]interface{}{2,  9,  0,  9,  5}, 1),
+            base.NewIntTuple([]interface{}{3,  11, 0,  11, 4}, 1),
+            base.NewIntTuple([]interface{}{4, -10, 0, -10, 3}, 0),
+            base.NewIntTuple([]interface{}{5, -9,  0, -9,  2}, 0),
+            base.NewIntTuple([]interface{}{6, -11, 0, -11, 1}, 0),
+         },
+         []base.Tuple{
+            base.NewIntTuple([]interface{}{1,  8, 0,  8, 2}, nil),
+            base.NewIntTuple([]interface{}{2, -8, 0, -8, 1}, nil),
+         },
+         []base.Feature{
+            base.Int(1),
+            base.Int(0),
+         },
+         []float64{
+            0.9,
+            0.9,
+         },
+      },
+      gbTestCase{
+         "Multiclass - Mixed Features",
+         nil,
+         []base.Tuple{
+            base.NewTuple([]interface{}{"red", 1.0}, "A"),
+            base.NewTuple([]interface{}{"red", 2.0}, "A"),
+            base.NewTuple([]interface{}{"red", 3.0}, "A"),
+            base.NewTuple([]interface{}{"blue", 10.0}, "B"),
+            base.NewTuple([]interface{}{"blue", 11.0}, "B"),
+            base.NewTuple([]interface{}{"blue", 12.0}, "B"),
+            base.NewTuple([]interface{}{"blue", 20.0}, "C"),
+            base.NewTuple([]interface{}{"blue", 21.0}, "C"),
+            base.NewTuple([]interface{}{"blue", 22.0}, "C"),
+         },
+         []base.Tuple{
+            base.NewTuple([]interface{}{"red", 0.0}, nil),
+            base.NewTuple([]interface{}{"blue", 11.0}, nil),
+            base.NewTuple([]interface{}{"blue", 25.0}, nil),
+         },
+         []base.Feature{
+            base.String("A"),
+            base.String("B"),
+            base.String("C"),
+         },
+         []float64{
+            0.9,
+            0.9,
+            0.9,
+         },
+      },
+   };
+
+   for _, testCase := range(testCases) {
+      var gb Classifier = NewGradientBoosting(testCase.Reducer, GB_LOG_LOSS, 0, 0, 0, 0, 0);
+      gb.Train(testCase.TestData);
+      var actualClasses []base.Feature;
+      var actualConfidences []float64;
+
+      actualClasses, actualConfidences = gb.Classify(testCase.Input);
+
+      if (len(actualClasses) != len(testCase.ExpectedClasses)) {
+         t.Errorf("(%s) -- Length of expected (%d) and actual classes (%d) do not match", testCase.Name, len(testCase.ExpectedClasses), len(actualClasses));
+         continue;
+      }
+
+      if (len(actualConfidences) != len(testCase.MinExpectedConfidences)) {
+         t.Errorf("(%s) -- Length of expected (%d) and actual confidences (%d) do not match", testCase.Name, len(testCase.MinExpectedConfidences), len(actualConfidences));
+         continue;
+      }
+
+      // Go over each value explicitly to make output more readable.
+      for i, _ := range(actualClasses) {
+         if (actualClasses[i] != testCase.ExpectedClasses[i]) {
+            t.Errorf("(%s)[%d] -- Bad classification. Expected classes: %v, Got: %v", testCase.Name, i, testCase.ExpectedClasses[i], actualClasses[i]);
+         }
+
+         if (actualConfidences[i] < testCase.MinExpectedConfidences[i] || actualConfidences[i] > 1.0) {
+            t.Errorf("(%s)[%d] -- Bad confidence. Expected min confidence: %v, Got: %v", testCase.Name, i, testCase.MinExpectedConfidences[i], actualConfidences[i]);
+         }
+      }
+   }
+}
+
+func TestGradientBoostingRegression(t *testing.T) {
+   base.Seed(4);
+
+   // y = x^2 on [-5, 5].
+   var data []base.Tuple = make([]base.Tuple, 0);
+   for x := -5.0; x <= 5.0; x += 0.1 {
+      data = append(data, base.NewFloatTuple([]float64{x}, x * x));
+   }
+
+   var gb *GradientBoosting = NewGradientBoosting(nil, GB_SQUARED_ERROR, 200, 0.1, 3, 0.8, 0);
+   gb.Train(data);
+
+   var input []base.Tuple = []base.Tuple{
+      base.NewFloatTuple([]float64{-4.05}, nil),
+      base.NewFloatTuple([]float64{0.05}, nil),
+      base.NewFloatTuple([]float64{2.55}, nil),
+   };
+   var expected []float64 = []float64{16.4025, 0.0025, 6.5025};
+
+   var predictions []float64 = gb.Predict(input);
+   for i, _ := range(expected) {
+      if (math.Abs(predictions[i] - expected[i]) > 0.5) {
+         t.Errorf("[%d] -- Bad prediction. Expected: %v, Got: %v", i, expected[i], predictions[i]);
+      }
+   }
+
+   classes, confidences := gb.Classify(input);
+   if (confidences != nil) {
+      t.Errorf("Expected nil confidences for regression, Got: %v", confidences);
+   }
+
+   for i, _ := range(expected) {
+      if (classes[i] != base.Float(predictions[i])) {
+         t.Errorf("[%d] -- Classify() and Predict() disagree. Classify: %v, Predict: %v", i, classes[i], predictions[i]);
+      }
+   }
+}
+
+func TestGradientBoostingEarlyStopping(t *testing.T) {
+   base.Seed(4);
+
+   var data []base.Tuple = base.FakeData(200, 2, 2, 0, nil, nil, 4);
+
+   var gb *GradientBoosting = NewGradientBoosting(nil, GB_LOG_LOSS, 500, 0.5, 0, 0, 0.2);
+   gb.Train(data);
+
+   if (gb.NumRounds() == 0 || gb.NumRounds() >= 500) {
+      t.Errorf("Expected early stopping. Max rounds: %d, Got: %d", 500, gb.NumRounds());
+   }
+}
+
+// A negative validation fraction is the same as zero: no early stopping.
+func TestGradientBoostingNoEarlyStopping(t *testing.T) {
+   base.Seed(4);
+
+   var data []base.Tuple = base.FakeData(200, 2, 2, 0, nil, nil, 4);
+
+   var gb *GradientBoosting = NewGradientBoosting(nil, GB_LOG_LOSS, 50, 0.5, 0, 0, -0.2);
+   gb.Train(data);
+
+   if (gb.NumRounds() != 50) {
+      t.Errorf("Expected no early stopping. Expected rounds: %d, Got: %d", 50, gb.NumRounds());
+   }
+}
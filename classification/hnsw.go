@@ -0,0 +1,266 @@
+package classification
+
+// Hierarchical Navigable Small World graphs (Malkov and Yashunin 2016).
+// Approximate nearest neighbor search over a layered proximity graph.
+// Each point is inserted into every layer up to a random (exponentially distributed) level.
+// Searches start at the sparse top layer and greedily descend to the dense bottom layer.
+
+import (
+   "container/heap"
+   "math"
+   "math/rand"
+   "sort"
+
+   "github.com/eriq-augustine/goml/base"
+   "github.com/eriq-augustine/goml/util"
+)
+
+const (
+   HNSW_DEFAULT_M = 16
+   HNSW_DEFAULT_EF_CONSTRUCTION = 200
+   HNSW_DEFAULT_EF_SEARCH = 50
+)
+
+type HnswParams struct {
+   // The number of links each point makes on every layer (double this on the bottom layer).
+   // Larger is more accurate, but uses more memory and is slower to build.
+   M int
+   // The size of the candidate list while inserting.
+   // Larger builds a better graph, but is slower to build.
+   EfConstruction int
+   // The size of the candidate list while querying (at least k will be used).
+   // Larger is more accurate, but slower to query.
+   EfSearch int
+}
+
+type hnswIndex struct {
+   distancer base.Distancer
+   m int
+   maxNeighborsBottom int
+   efConstruction int
+   efSearch int
+   // 1 / ln(M).
+   levelMultiplier float64
+   random *rand.Rand
+
+   data []base.NumericTuple
+   // [point][layer][neighbor index]
+   neighbors [][][]int
+   entryPoint int
+   maxLevel int
+}
+
+// Pass a non-positive value for any param to get the default (M must be at least 2).
+func newHnswIndex(distancer base.Distancer, params HnswParams) *hnswIndex {
+   if (params.M <= 1) {
+      params.M = HNSW_DEFAULT_M;
+   }
+
+   if (params.EfConstruction <= 0) {
+      params.EfConstruction = HNSW_DEFAULT_EF_CONSTRUCTION;
+   }
+
+   if (params.EfSearch <= 0) {
+      params.EfSearch = HNSW_DEFAULT_EF_SEARCH;
+   }
+
+   var index hnswIndex = hnswIndex{
+      distancer: distancer,
+      m: params.M,
+      maxNeighborsBottom: 2 * params.M,
+      efConstruction: params.EfConstruction,
+      efSearch: params.EfSearch,
+      levelMultiplier: 1.0 / math.Log(float64(params.M)),
+      random: base.NewRandom(),
+      data: make([]base.NumericTuple, 0),
+      neighbors: make([][][]int, 0),
+      entryPoint: -1,
+      maxLevel: -1,
+   };
+
+   return &index;
+}
+
+func (this *hnswIndex) insert(tuple base.NumericTuple) {
+   var index int = len(this.data);
+   var level int = int(math.Floor(-math.Log(1.0 - this.random.Float64()) * this.levelMultiplier));
+
+   this.data = append(this.data, tuple);
+   this.neighbors = append(this.neighbors, make([][]int, level + 1));
+
+   if (this.entryPoint == -1) {
+      this.entryPoint = index;
+      this.maxLevel = level;
+      return;
+   }
+
+   var entryPoints []DistanceRecord = []DistanceRecord{this.record(this.entryPoint, tuple)};
+
+   // Greedy search down to the new point's top layer.
+   for layer := this.maxLevel; layer > level; layer-- {
+      entryPoints = this.searchLayer(tuple, entryPoints, 1, layer);
+   }
+
+   for layer := util.MinInt(level, this.maxLevel); layer >= 0; layer-- {
+      var candidates []DistanceRecord = this.searchLayer(tuple, entryPoints, this.efConstruction, layer);
+      var selected []DistanceRecord = this.selectNeighbors(candidates, this.m);
+
+      this.neighbors[index][layer] = make([]int, len(selected));
+      for i, neighbor := range(selected) {
+         this.neighbors[index][layer][i] = neighbor.Index;
+         this.link(neighbor.Index, index, layer);
+      }
+
+      entryPoints = candidates;
+   }
+
+   if (level > this.maxLevel) {
+      this.entryPoint = index;
+      this.maxLevel = level;
+   }
+}
+
+func (this *hnswIndex) query(tuple base.NumericTuple, k int) []DistanceRecord {
+   if (this.entryPoint == -1) {
+      return []DistanceRecord{};
+   }
+
+   var entryPoints []DistanceRecord = []DistanceRecord{this.record(this.entryPoint, tuple)};
+   for layer := this.maxLevel; layer > 0; layer-- {
+      entryPoints = this.searchLayer(tuple, entryPoints, 1, layer);
+   }
+
+   var results []DistanceRecord = this.searchLayer(tuple, entryPoints, util.MaxInt(this.efSearch, k), 0);
+   if (len(results) > k) {
+      results = results[:k];
+   }
+
+   return results;
+}
+
+// Add a link from |from| to |to|, shrinking |from|'s links if it has too many.
+func (this *hnswIndex) link(from int, to int, layer int) {
+   var maxNeighbors int = this.m;
+   if (layer == 0) {
+      maxNeighbors = this.maxNeighborsBottom;
+   }
+
+   this.neighbors[from][layer] = append(this.neighbors[from][layer], to);
+   if (len(this.neighbors[from][layer]) <= maxNeighbors) {
+      return;
+   }
+
+   var candidates []DistanceRecord = make([]DistanceRecord, len(this.neighbors[from][layer]));
+   for i, neighbor := range(this.neighbors[from][layer]) {
+      candidates[i] = this.record(neighbor, this.data[from]);
+   }
+
+   sort.Sort(ByDistance(candidates));
+   var selected []DistanceRecord = this.selectNeighbors(candidates, maxNeighbors);
+
+   this.neighbors[from][layer] = this.neighbors[from][layer][:0];
+   for _, neighbor := range(selected) {
+      this.neighbors[from][layer] = append(this.neighbors[from][layer], neighbor.Index);
+   }
+}
+
+// Best first search of a single layer.
+// Returns (up to) |ef| of the closest points found, ordered by distance.
+func (this *hnswIndex) searchLayer(tuple base.NumericTuple, entryPoints []DistanceRecord, ef int, layer int) []DistanceRecord {
+   var visited map[int]bool = make(map[int]bool);
+   var candidates candidateHeap = make(candidateHeap, 0);
+   var results neighborHeap = make(neighborHeap, 0, ef + 1);
+
+   for _, entryPoint := range(entryPoints) {
+      visited[entryPoint.Index] = true;
+      heap.Push(&candidates, entryPoint);
+      results.offer(entryPoint, ef);
+   }
+
+   for (len(candidates) > 0) {
+      var current DistanceRecord = heap.Pop(&candidates).(DistanceRecord);
+      if (current.Distance > results.worstDistance(ef)) {
+         break;
+      }
+
+      for _, neighbor := range(this.neighbors[current.Index][layer]) {
+         if (visited[neighbor]) {
+            continue;
+         }
+         visited[neighbor] = true;
+
+         var record DistanceRecord = this.record(neighbor, tuple);
+         if (results.offer(record, ef)) {
+            heap.Push(&candidates, record);
+         }
+      }
+   }
+
+   return results.sorted();
+}
+
+// The neighbor selection heuristic (Algorithm 4 in the paper).
+// A candidate is only chosen if it is closer to the base point than to any already chosen neighbor.
+// This keeps links spread out in different directions instead of all going to one cluster.
+// Any leftover slots are filled with the closest skipped candidates.
+// |candidates| must be ordered by distance.
+func (this *hnswIndex) selectNeighbors(candidates []DistanceRecord, m int) []DistanceRecord {
+   var selected []DistanceRecord = make([]DistanceRecord, 0, m);
+   var skipped []DistanceRecord = make([]DistanceRecord, 0);
+
+   for _, candidate := range(candidates) {
+      if (len(selected) >= m) {
+         break;
+      }
+
+      var keep bool = true;
+      for _, chosen := range(selected) {
+         if (this.distancer.Distance(this.data[candidate.Index], this.data[chosen.Index]) < candidate.Distance) {
+            keep = false;
+            break;
+         }
+      }
+
+      if (keep) {
+         selected = append(selected, candidate);
+      } else {
+         skipped = append(skipped, candidate);
+      }
+   }
+
+   for i := 0; i < len(skipped) && len(selected) < m; i++ {
+      selected = append(selected, skipped[i]);
+   }
+
+   return selected;
+}
+
+func (this *hnswIndex) record(index int, tuple base.NumericTuple) DistanceRecord {
+   return DistanceRecord{this.distancer.Distance(this.data[index], tuple), index};
+}
+
+// A min heap (the closest on top) of points left to explore.
+type candidateHeap []DistanceRecord;
+
+func (this candidateHeap) Len() int {
+   return len(this);
+}
+
+func (this candidateHeap) Less(i, j int) bool {
+   return ByDistance(this).Less(i, j);
+}
+
+func (this candidateHeap) Swap(i, j int) {
+   this[i], this[j] = this[j], this[i];
+}
+
+func (this *candidateHeap) Push(record interface{}) {
+   *this = append(*this, record.(DistanceRecord));
+}
+
+func (this *candidateHeap) Pop() interface{} {
+   var old candidateHeap = *this;
+   var record DistanceRecord = old[len(old) - 1];
+   *this = old[:len(old) - 1];
+   return record;
+}
@@ -0,0 +1,126 @@
+package classification
+
+import (
+   "math/rand"
+   "testing"
+
+   "github.com/eriq-augustine/goml/base"
+)
+
+type hnswTestCase struct {
+   Name string
+   Distancer base.Distancer
+   Params HnswParams
+   NumPoints int
+   NumDimensions int
+   K int
+   MinRecall float64
+}
+
+// The fraction of the true k nearest neighbors that |index| found.
+func measureRecall(index knnIndex, exact knnIndex, queries []base.NumericTuple, k int) float64 {
+   var found int = 0;
+   var total int = 0;
+
+   for _, query := range(queries) {
+      var expected map[int]bool = make(map[int]bool);
+      for _, record := range(exact.query(query, k)) {
+         expected[record.Index] = true;
+      }
+
+      for _, record := range(index.query(query, k)) {
+         if (expected[record.Index]) {
+            found++;
+         }
+      }
+
+      total += len(expected);
+   }
+
+   return float64(found) / float64(total);
+}
+
+func TestHnswRecall(t *testing.T) {
+   base.Seed(4);
+
+   var testCases []hnswTestCase = []hnswTestCase{
+      hnswTestCase{"Defaults", base.Euclidean{}, HnswParams{}, 1000, 32, 10, 0.95},
+      hnswTestCase{"Cosine", base.Cosine{}, HnswParams{}, 1000, 16, 10, 0.95},
+      hnswTestCase{"Small Graph", base.Euclidean{}, HnswParams{4, 20, 10}, 1000, 8, 5, 0.7},
+      hnswTestCase{"K Larger Than EF", base.Euclidean{}, HnswParams{8, 50, 5}, 1000, 8, 20, 0.9},
+   };
+
+   for _, testCase := range(testCases) {
+      var random *rand.Rand = rand.New(rand.NewSource(4));
+      var data []base.NumericTuple = randomNumericTuples(random, testCase.NumPoints, testCase.NumDimensions, false);
+      var queries []base.NumericTuple = randomNumericTuples(random, 100, testCase.NumDimensions, false);
+
+      var exact knnIndex = newKnnIndex(KNN_INDEX_BRUTE, testCase.Distancer, data, HnswParams{});
+      var index knnIndex = newKnnIndex(KNN_INDEX_HNSW, testCase.Distancer, data, testCase.Params);
+
+      var recall float64 = measureRecall(index, exact, queries, testCase.K);
+      if (recall < testCase.MinRecall) {
+         t.Errorf("(%s) -- Recall too low. Expected at least: %v, Got: %v", testCase.Name, testCase.MinRecall, recall);
+      }
+   }
+}
+
+// Building the graph in two halves should be just as good as all at once.
+func TestHnswIncrementalInsert(t *testing.T) {
+   base.Seed(4);
+
+   var random *rand.Rand = rand.New(rand.NewSource(4));
+   var data []base.NumericTuple = randomNumericTuples(random, 1000, 16, false);
+   var queries []base.NumericTuple = randomNumericTuples(random, 100, 16, false);
+
+   var index insertableKnnIndex = newKnnIndex(KNN_INDEX_HNSW, base.Euclidean{}, data[:500], HnswParams{}).(insertableKnnIndex);
+   for _, tuple := range(data[500:]) {
+      index.insert(tuple);
+   }
+
+   var exact knnIndex = newKnnIndex(KNN_INDEX_BRUTE, base.Euclidean{}, data, HnswParams{});
+   var recall float64 = measureRecall(index, exact, queries, 10);
+   if (recall < 0.95) {
+      t.Errorf("Recall too low. Expected at least: %v, Got: %v", 0.95, recall);
+   }
+}
+
+func TestKnnInsert(t *testing.T) {
+   base.Seed(4);
+
+   var data []base.Tuple = []base.Tuple{
+      base.NewFloatTuple([]float64{10, 10}, "A"),
+      base.NewFloatTuple([]float64{9, 9}, "A"),
+      base.NewFloatTuple([]float64{11, 11}, "A"),
+      base.NewFloatTuple([]float64{-10, -10}, "B"),
+      base.NewFloatTuple([]float64{-9, -9}, "B"),
+      base.NewFloatTuple([]float64{-11, -11}, "B"),
+   };
+
+   var newData []base.Tuple = []base.Tuple{
+      base.NewFloatTuple([]float64{10, -10}, "C"),
+      base.NewFloatTuple([]float64{9, -9}, "C"),
+      base.NewFloatTuple([]float64{11, -11}, "C"),
+   };
+
+   var input []base.Tuple = []base.Tuple{
+      base.NewFloatTuple([]float64{8, 8}, nil),
+      base.NewFloatTuple([]float64{8, -8}, nil),
+   };
+
+   var knns []*Knn = []*Knn{
+      NewKnnWithOptions(3, nil, nil, KnnOptions{Index: KNN_INDEX_BRUTE}),
+      NewKnnWithOptions(3, nil, nil, KnnOptions{Index: KNN_INDEX_KD_TREE}),
+      NewKnnWithOptions(3, nil, nil, KnnOptions{Index: KNN_INDEX_HNSW}),
+   };
+
+   for i, knn := range(knns) {
+      knn.Train(data);
+      knn.Insert(newData);
+
+      classes, _ := knn.Classify(input);
+      if (classes[0] != base.String("A") || classes[1] != base.String("C")) {
+         t.Errorf("[%d] -- Bad classification after insert. Expected: [A C], Got: %v", i, classes);
+      }
+   }
+}
@@ -0,0 +1,122 @@
+package classification
+
+// A KD-tree over the training tuples.
+// Each node keeps the bounding box of its points so that whole subtrees can be skipped
+// when the box is further away than the current k-th neighbor.
+
+import (
+   "math"
+
+   "github.com/eriq-augustine/goml/base"
+   "github.com/eriq-augustine/goml/util"
+)
+
+type kdTree struct {
+   distancer base.Distancer
+   data []base.NumericTuple
+   root *kdNode
+}
+
+type kdNode struct {
+   // Bounding box.
+   lower []float64
+   upper []float64
+
+   // Only set for leaves.
+   indexes []int
+
+   // Only set for internal nodes.
+   splitDimension int
+   splitValue float64
+   left *kdNode
+   right *kdNode
+}
+
+func newKdTree(distancer base.Distancer, data []base.NumericTuple) *kdTree {
+   var points [][]float64 = toFloatSlices(data);
+
+   var tree kdTree = kdTree{
+      distancer: distancer,
+      data: data,
+      root: nil,
+   };
+
+   if (len(data) > 0) {
+      tree.root = buildKdNode(points, util.RangeSlice(len(data)));
+   }
+
+   return &tree;
+}
+
+func buildKdNode(points [][]float64, indexes []int) *kdNode {
+   var node *kdNode = &kdNode{};
+
+   node.lower = append([]float64(nil), points[indexes[0]]...);
+   node.upper = append([]float64(nil), points[indexes[0]]...);
+   for _, index := range(indexes) {
+      for dimension, value := range(points[index]) {
+         node.lower[dimension] = math.Min(node.lower[dimension], value);
+         node.upper[dimension] = math.Max(node.upper[dimension], value);
+      }
+   }
+
+   if (len(indexes) <= KNN_INDEX_LEAF_SIZE) {
+      node.indexes = indexes;
+      return node;
+   }
+
+   left, right, dimension, value, ok := splitAtMedian(points, indexes);
+   if (!ok) {
+      node.indexes = indexes;
+      return node;
+   }
+
+   node.splitDimension = dimension;
+   node.splitValue = value;
+   node.left = buildKdNode(points, left);
+   node.right = buildKdNode(points, right);
+
+   return node;
+}
+
+func (this kdTree) query(tuple base.NumericTuple, k int) []DistanceRecord {
+   var neighbors neighborHeap = make(neighborHeap, 0, k + 1);
+   if (this.root != nil) {
+      this.search(this.root, tuple, tuple.ToFloatSlice(), k, &neighbors);
+   }
+
+   return neighbors.sorted();
+}
+
+func (this kdTree) search(node *kdNode, tuple base.NumericTuple, point []float64, k int, neighbors *neighborHeap) {
+   if (node.indexes != nil) {
+      for _, index := range(node.indexes) {
+         neighbors.offer(DistanceRecord{this.distancer.Distance(this.data[index], tuple), index}, k);
+      }
+      return;
+   }
+
+   // Go down the side the point is on first, it will most likely have the closest neighbors.
+   var near *kdNode = node.left;
+   var far *kdNode = node.right;
+   if (point[node.splitDimension] >= node.splitValue) {
+      near, far = far, near;
+   }
+
+   for _, child := range([]*kdNode{near, far}) {
+      // Don't prune on ties, a tied point with a lower index may still be in there.
+      if (this.boxDistance(child, tuple, point) <= neighbors.worstDistance(k)) {
+         this.search(child, tuple, point, k, neighbors);
+      }
+   }
+}
+
+// The distance from the point to the closest point in the node's bounding box.
+func (this kdTree) boxDistance(node *kdNode, tuple base.NumericTuple, point []float64) float64 {
+   var closest []float64 = make([]float64, len(point));
+   for dimension, value := range(point) {
+      closest[dimension] = math.Min(math.Max(value, node.lower[dimension]), node.upper[dimension]);
+   }
+
+   return this.distancer.Distance(base.NewFloatTuple(closest, nil), tuple);
+}
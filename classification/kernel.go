@@ -0,0 +1,64 @@
+package classification
+
+import (
+   "math"
+)
+
+// Kernels work on raw slices since they get evaluated in the inner loops of training.
+type Kernel interface {
+   Evaluate(a []float64, b []float64) float64
+}
+
+// K(a, b) = a dot b
+type LinearKernel struct{}
+
+func (this LinearKernel) Evaluate(a []float64, b []float64) float64 {
+   return dot(a, b);
+}
+
+// K(a, b) = exp(-gamma * ||a - b||^2)
+// A non-positive gamma defaults to 1 / (number of features).
+type RBFKernel struct {
+   Gamma float64
+}
+
+func NewRBFKernel(gamma float64) RBFKernel {
+   return RBFKernel{gamma};
+}
+
+func (this RBFKernel) Evaluate(a []float64, b []float64) float64 {
+   var sum float64 = 0;
+   for i, _ := range(a) {
+      sum += math.Pow(a[i] - b[i], 2);
+   }
+
+   return math.Exp(-1.0 * kernelGamma(this.Gamma, len(a)) * sum);
+}
+
+// K(a, b) = (gamma * (a dot b) + coef0) ^ degree
+// A non-positive gamma defaults to 1 / (number of features).
+type PolynomialKernel struct {
+   Degree int
+   Gamma float64
+   Coef0 float64
+}
+
+func NewPolynomialKernel(degree int, gamma float64, coef0 float64) PolynomialKernel {
+   if (degree <= 0) {
+      degree = SVM_DEFAULT_POLYNOMIAL_DEGREE;
+   }
+
+   return PolynomialKernel{degree, gamma, coef0};
+}
+
+func (this PolynomialKernel) Evaluate(a []float64, b []float64) float64 {
+   return math.Pow(kernelGamma(this.Gamma, len(a)) * dot(a, b) + this.Coef0, float64(this.Degree));
+}
+
+func kernelGamma(gamma float64, numFeatures int) float64 {
+   if (gamma > 0 || numFeatures == 0) {
+      return gamma;
+   }
+
+   return 1.0 / float64(numFeatures);
+}
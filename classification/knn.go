@@ -3,7 +3,6 @@ package classification
 import (
    "fmt"
    "math"
-   "sort"
 
    "github.com/eriq-augustine/goml/base"
    "github.com/eriq-augustine/goml/features"
@@ -14,14 +13,58 @@ const (
    KNN_MIN_WORK_PER_WORKER = 1000
 )
 
+// How each of the k nearest neighbors votes.
+type KnnVoting int
+
+const (
+   // Every neighbor gets one vote.
+   KNN_VOTE_UNIFORM KnnVoting = iota
+   // Each neighbor votes with 1 / distance.
+   // Exact matches (zero distance) will dominate.
+   KNN_VOTE_INVERSE_DISTANCE
+   // Each neighbor votes with a Gaussian kernel of its distance, exp(-(d / h)^2 / 2).
+   // The bandwidth (h) is the distance to the k-th neighbor, so it adapts to the local density.
+   KNN_VOTE_KERNEL
+)
+
 type Knn struct {
    k int
    reducer features.Reducer
    distancer base.Distancer
-   trainingData []base.NumericTuple
+   voting KnnVoting
+   indexType KnnIndexType
+   // Only used for KNN_INDEX_HNSW.
+   hnswParams HnswParams
+   // Only set if |distancer| is also a base.GeneralDistancer.
+   // Any tuple can then be used, and neighbors are found by brute force (|index| is nil).
+   generalDistancer base.GeneralDistancer
+   index knnIndex
+   trainingData []base.Tuple
+   // The vote of each training tuple.
+   // nil if all votes count equally.
+   trainingWeights []float64
 }
 
+// Settings for a Knn beyond k, the reducer, and the distance.
+// The zero value is uniform voting with KNN_INDEX_AUTO.
+type KnnOptions struct {
+   Voting KnnVoting
+   // See KnnIndexType for the available indexes (KNN_INDEX_AUTO will pick one based on the data).
+   Index KnnIndexType
+   // Only used for KNN_INDEX_HNSW.
+   // Non-positive values get the defaults.
+   Hnsw HnswParams
+}
+
+// Uniform voting with KNN_INDEX_AUTO, see NewKnnWithOptions() for the other settings.
+// If |distancer| is a base.GeneralDistancer (eg base.Gower), then any base.Tuple can be used
+// (not just base.NumericTuple).
 func NewKnn(k int, reducer features.Reducer, distancer base.Distancer) *Knn {
+   return NewKnnWithOptions(k, reducer, distancer, KnnOptions{});
+}
+
+// A base.GeneralDistancer (eg base.Gower) only allows KNN_INDEX_AUTO and KNN_INDEX_BRUTE.
+func NewKnnWithOptions(k int, reducer features.Reducer, distancer base.Distancer, options KnnOptions) *Knn {
    if (k <= 0) {
       panic("k must be >= 1");
    }
@@ -34,10 +77,20 @@ func NewKnn(k int, reducer features.Reducer, distancer base.Distancer) *Knn {
       distancer = base.Euclidean{};
    }
 
+   generalDistancer, _ := distancer.(base.GeneralDistancer);
+   if (generalDistancer != nil && options.Index != KNN_INDEX_AUTO && options.Index != KNN_INDEX_BRUTE) {
+      panic(fmt.Sprintf("The %T distance can only be used with a brute force index.", distancer));
+   }
+
    var knn Knn = Knn{
       k: k,
       reducer: reducer,
       distancer: distancer,
+      voting: options.Voting,
+      indexType: options.Index,
+      hnswParams: options.Hnsw,
+      generalDistancer: generalDistancer,
+      index: nil,
       trainingData: nil,
    };
 
@@ -47,17 +100,91 @@ func NewKnn(k int, reducer features.Reducer, distancer base.Distancer) *Knn {
 // TODO(eriq): Verify dimensions.
 // The Knn now owns |data|.
 func (this *Knn) Train(data []base.Tuple) {
+   this.TrainWeighted(data, nil);
+}
+
+// Each neighbor votes with its weight instead of 1.
+// Passing nil for |weights| weights every tuple equally.
+func (this *Knn) TrainWeighted(data []base.Tuple, weights []float64) {
+   if (weights != nil && len(weights) != len(data)) {
+      panic(fmt.Sprintf("Number of weights (%d) must match the number of tuples (%d).", len(weights), len(data)));
+   }
+
+   // The caller may keep changing |weights| (eg AdaBoost), so keep our own copy.
+   this.trainingWeights = nil;
+   if (weights != nil) {
+      this.trainingWeights = append([]float64(nil), weights...);
+   }
+
    this.reducer.Init(data);
    data = this.reducer.Reduce(data);
 
-   this.trainingData = make([]base.NumericTuple, len(data));
-   for i, tuple := range(data) {
-      numericTuple, ok := tuple.(base.NumericTuple);
-      if (!ok) {
-         panic(fmt.Sprintf("KNN only supports taining on NumericTuple. Found type: %T", tuple));
+   for _, tuple := range(data) {
+      this.checkTuple(tuple);
+   }
+
+   this.trainingData = data;
+   this.buildIndex();
+}
+
+// Add more training tuples to an already trained Knn.
+// The reducer is not re-initialized and new tuples get a weight of 1 (if TrainWeighted() was used).
+// Indexes that support it (KNN_INDEX_HNSW) add the tuples incrementally, the rest are rebuilt.
+func (this *Knn) Insert(data []base.Tuple) {
+   if (this.trainingData == nil) {
+      panic("Knn must be trained before inserting.");
+   }
+
+   data = this.reducer.Reduce(data);
+
+   for _, tuple := range(data) {
+      this.checkTuple(tuple);
+
+      this.trainingData = append(this.trainingData, tuple);
+      if (this.trainingWeights != nil) {
+         this.trainingWeights = append(this.trainingWeights, 1.0);
       }
+   }
+
+   // General distances do not use an index.
+   if (this.generalDistancer != nil) {
+      return;
+   }
+
+   insertableIndex, ok := this.index.(insertableKnnIndex);
+   if (!ok) {
+      this.buildIndex();
+      return;
+   }
 
-      this.trainingData[i] = numericTuple;
+   for _, tuple := range(data) {
+      insertableIndex.insert(tuple.(base.NumericTuple));
+   }
+}
+
+func (this *Knn) buildIndex() {
+   if (this.generalDistancer != nil) {
+      this.index = nil;
+      return;
+   }
+
+   var numericData []base.NumericTuple = make([]base.NumericTuple, len(this.trainingData));
+   for i, tuple := range(this.trainingData) {
+      numericData[i] = tuple.(base.NumericTuple);
+   }
+
+   this.index = newKnnIndex(this.indexType, this.distancer, numericData, this.hnswParams);
+}
+
+// Panic if |tuple| cannot be used with the distance.
+func (this Knn) checkTuple(tuple base.Tuple) {
+   if (this.generalDistancer != nil) {
+      return;
+   }
+
+   _, ok := tuple.(base.NumericTuple);
+   if (!ok) {
+      panic(fmt.Sprintf("KNN only supports NumericTuple (unless a base.GeneralDistancer is used). Found type: %T", tuple));
    }
 }
 
@@ -70,50 +197,103 @@ func (this Knn) Classify(tuples []base.Tuple) ([]base.Feature, []float64) {
    var confidences []float64 = make([]float64, len(tuples));
 
    for i, tuple := range(tuples) {
-      numericTuple, ok := tuple.(base.NumericTuple);
-      if (!ok) {
-         panic("KNN only supports classifying NumericTuple");
-      }
-
-      results[i], confidences[i] = this.classifySingle(numericTuple);
+      this.checkTuple(tuple);
+      results[i], confidences[i] = this.classifySingle(tuple);
    }
 
    return results, confidences;
 }
 
-func (this Knn) classifySingle(classifyTuple base.NumericTuple) (base.Feature, float64) {
-   var distances []DistanceRecord = this.calculateDistances(classifyTuple);
-
-   sort.Sort(ByDistance(distances));
+// For KNN_VOTE_UNIFORM, the confidence is the score from calculateScore().
+// For the other voting methods, the confidence is the fraction of the total vote that the chosen class got.
+// Ties are broken in favor of the class with the nearest neighbor.
+func (this Knn) classifySingle(classifyTuple base.Tuple) (base.Feature, float64) {
+   var neighbors []DistanceRecord = this.nearestNeighbors(classifyTuple);
+   var voteWeights []float64 = this.voteWeights(neighbors);
 
    // {class -> [distance, ...], ...}
    var classes map[base.Feature][]float64 = make(map[base.Feature][]float64);
-   // K Nearest Neighbors
-   for i := 0; i < this.k; i++ {
-      var targetTuple base.Tuple = this.trainingData[distances[i].Index];
-      classDistances, _ := classes[targetTuple.GetClass()];
+   // {class -> total vote weight, ...}
+   var votes map[base.Feature]float64 = make(map[base.Feature]float64);
+   // The classes ordered by their nearest neighbor.
+   var orderedClasses []base.Feature = make([]base.Feature, 0);
+
+   var totalVotes float64 = 0;
+   for i, neighbor := range(neighbors) {
+      var class base.Feature = this.trainingData[neighbor.Index].GetClass();
+
+      classDistances, ok := classes[class];
+      if (!ok) {
+         orderedClasses = append(orderedClasses, class);
+      }
+
       // No need to check for existance, on nil a new slice will be created.
-      classes[targetTuple.GetClass()] = append(classDistances, distances[i].Distance);
+      classes[class] = append(classDistances, neighbor.Distance);
+      votes[class] += voteWeights[i];
+      totalVotes += voteWeights[i];
    }
 
-   var bestClass base.Feature = findBestClass(classes);
+   var bestClass base.Feature = findBestClass(orderedClasses, votes);
 
-   return bestClass, calculateScore(bestClass, classes);
+   if (this.voting == KNN_VOTE_UNIFORM) {
+      return bestClass, calculateScore(bestClass, classes, votes);
+   }
+
+   return bestClass, votes[bestClass] / totalVotes;
+}
+
+// Get the k nearest training tuples ordered by distance (ties broken by index).
+func (this Knn) nearestNeighbors(tuple base.Tuple) []DistanceRecord {
+   if (this.generalDistancer == nil) {
+      return this.index.query(tuple.(base.NumericTuple), this.k);
+   }
+
+   var neighbors neighborHeap = make(neighborHeap, 0, this.k + 1);
+   for i, trainingTuple := range(this.trainingData) {
+      neighbors.offer(DistanceRecord{this.generalDistancer.GeneralDistance(trainingTuple, tuple), i}, this.k);
+   }
+
+   return neighbors.sorted();
 }
 
-func (this Knn) calculateDistances(classifyTuple base.NumericTuple) []DistanceRecord {
-   var distances []DistanceRecord = make([]DistanceRecord, len(this.trainingData));
+// The vote of each neighbor (includes the training weight).
+func (this Knn) voteWeights(neighbors []DistanceRecord) []float64 {
+   var weights []float64 = make([]float64, len(neighbors));
+
+   var bandwidth float64 = math.Max(neighbors[len(neighbors) - 1].Distance, util.EPSILON);
+
+   for i, neighbor := range(neighbors) {
+      switch this.voting {
+      case KNN_VOTE_UNIFORM:
+         weights[i] = 1.0;
+      case KNN_VOTE_INVERSE_DISTANCE:
+         weights[i] = 1.0 / math.Max(neighbor.Distance, util.EPSILON);
+      case KNN_VOTE_KERNEL:
+         weights[i] = math.Exp(-0.5 * math.Pow(neighbor.Distance / bandwidth, 2));
+      default:
+         panic(fmt.Sprintf("Unknown KNN voting: %d", this.voting));
+      }
+
+      weights[i] *= sampleWeight(this.trainingWeights, neighbor.Index);
+   }
+
+   return weights;
+}
+
+// The distance from |classifyTuple| to every tuple in |trainingData| (in no particular order).
+func calculateDistances(distancer base.Distancer, trainingData []base.NumericTuple, classifyTuple base.NumericTuple) []DistanceRecord {
+   var distances []DistanceRecord = make([]DistanceRecord, len(trainingData));
 
-   var numWorkers int = util.MinInt(util.MaxInt(1, len(this.trainingData) / KNN_MIN_WORK_PER_WORKER), base.GetMaxProcs());
-   var tuplesPerWorker int = int(math.Ceil(float64(len(this.trainingData)) / float64(numWorkers)));
+   var numWorkers int = util.MinInt(util.MaxInt(1, len(trainingData) / KNN_MIN_WORK_PER_WORKER), base.GetMaxProcs());
+   var tuplesPerWorker int = int(math.Ceil(float64(len(trainingData)) / float64(numWorkers)));
 
-   var results chan DistanceRecord = make(chan DistanceRecord, len(this.trainingData));
+   var results chan DistanceRecord = make(chan DistanceRecord, len(trainingData));
 
    for worker := 0; worker < numWorkers; worker++ {
-      go classifyWorker(results, this.distancer, this.trainingData, classifyTuple, worker * tuplesPerWorker, tuplesPerWorker);
+      go classifyWorker(results, distancer, trainingData, classifyTuple, worker * tuplesPerWorker, tuplesPerWorker);
    }
 
-   for i := 0; i < len(this.trainingData); i++ {
+   for i := 0; i < len(trainingData); i++ {
       distances[i] = <-results;
    }
    close(results);
@@ -132,9 +312,9 @@ func classifyWorker(results chan<-DistanceRecord, distancer base.Distancer,
 
 // (1 / sum(distances) + sign(sum(distances))) + (2 * k`)
 // distances with a class that does not match the target class are negated.
-// k` = the number of matching classes (len(classes[bestClass])).
+// k` = the votes for the best class (len(classes[bestClass]) when unweighted).
 // sign is +/- 1.
-func calculateScore(bestClass base.Feature, classes map[base.Feature][]float64) float64 {
+func calculateScore(bestClass base.Feature, classes map[base.Feature][]float64, votes map[base.Feature]float64) float64 {
    var sum float64 = 0;
    for class, distances := range(classes) {
       for _, distance := range(distances) {
@@ -146,16 +326,17 @@ func calculateScore(bestClass base.Feature, classes map[base.Feature][]float64)
       }
    }
 
-   return (1.0 / (sum + float64(util.Sign(sum)))) + (2.0 * float64(len(classes[bestClass])));
+   return (1.0 / (sum + float64(util.Sign(sum)))) + (2.0 * votes[bestClass]);
 }
 
-func findBestClass(classes map[base.Feature][]float64) base.Feature {
-   var bestCount int = -1;
+// Ties go to whichever class comes first in |classes|.
+func findBestClass(classes []base.Feature, votes map[base.Feature]float64) base.Feature {
+   var bestVotes float64 = -1;
    var bestValue base.Feature = nil;
 
-   for value, distances := range(classes) {
-      if (bestCount == -1 || len(distances) > bestCount) {
-         bestCount = len(distances);
+   for _, value := range(classes) {
+      if (bestVotes == -1 || votes[value] > bestVotes) {
+         bestVotes = votes[value];
          bestValue = value;
       }
    }
@@ -178,6 +359,11 @@ func (a ByDistance) Swap(i, j int) {
    a[i], a[j] = a[j], a[i];
 }
 
+// Equal distances are ordered by index so that results do not depend on the order the distances were computed in.
 func (a ByDistance) Less(i, j int) bool {
+   if (a[i].Distance == a[j].Distance) {
+      return a[i].Index < a[j].Index;
+   }
+
    return a[i].Distance < a[j].Distance;
 }
@@ -0,0 +1,282 @@
+package classification
+
+// Indexes for finding the k nearest neighbors of a tuple.
+// All indexes (except HNSW) are exact, they return the same neighbors ordered by distance (ties broken by index).
+
+import (
+   "container/heap"
+   "fmt"
+   "math"
+   "sort"
+
+   "github.com/eriq-augustine/goml/base"
+)
+
+const (
+   // The most points a tree leaf will hold.
+   KNN_INDEX_LEAF_SIZE = 40
+   // KNN_INDEX_AUTO will use brute force for less training data than this.
+   KNN_INDEX_AUTO_MIN_TREE_SIZE = 1000
+   // KNN_INDEX_AUTO will only use a KD-tree up to this many dimensions.
+   // KD-trees degrade to brute force (with extra overhead) in high dimensions.
+   KNN_INDEX_AUTO_MAX_KD_DIMENSIONS = 16
+)
+
+type KnnIndexType int
+
+const (
+   // Brute force for small data, otherwise a KD-tree if the distance and dimensionality allow it,
+   // otherwise a ball tree if the distance allows it, otherwise brute force.
+   KNN_INDEX_AUTO KnnIndexType = iota
+   // Compute the distance to every training tuple.
+   KNN_INDEX_BRUTE
+   // Axis aligned splits.
+   // Only works with distances computed from coordinate differences (eg base.Euclidean), see supportsKdTree().
+   KNN_INDEX_KD_TREE
+   // Nested hyperspheres.
+   // Works with any base.Distancer that is a true metric (it must obey the triangle inequality).
+   // So base.Cosine and base.Correlation cannot be used.
+   // KNN_INDEX_AUTO only uses it for the known metrics (see isMetric()),
+   // pass it explicitly to use it with your own metric.
+   KNN_INDEX_BALL_TREE
+   // Hierarchical Navigable Small World graph.
+   // Approximate (some true neighbors may be missed), but fast in high dimensions.
+   // Works with any base.Distancer. Never chosen by KNN_INDEX_AUTO.
+   // Use KnnOptions.Hnsw to tune the graph.
+   KNN_INDEX_HNSW
+)
+
+type knnIndex interface {
+   // Get the (up to) k nearest neighbors ordered by distance (ties broken by index).
+   query(tuple base.NumericTuple, k int) []DistanceRecord
+}
+
+// An index that can add points without being rebuilt.
+type insertableKnnIndex interface {
+   knnIndex
+   // The tuple gets the next index.
+   insert(tuple base.NumericTuple)
+}
+
+// |hnswParams| is only used for KNN_INDEX_HNSW.
+func newKnnIndex(indexType KnnIndexType, distancer base.Distancer, data []base.NumericTuple, hnswParams HnswParams) knnIndex {
+   if (indexType == KNN_INDEX_AUTO) {
+      indexType = chooseKnnIndex(distancer, data);
+   }
+
+   switch indexType {
+   case KNN_INDEX_BRUTE:
+      return bruteForceIndex{distancer, data};
+   case KNN_INDEX_KD_TREE:
+      if (!supportsKdTree(distancer)) {
+         panic(fmt.Sprintf("A KD-tree cannot be used with the %T distance.", distancer));
+      }
+      return newKdTree(distancer, data);
+   case KNN_INDEX_BALL_TREE:
+      if (isNonMetric(distancer)) {
+         panic(fmt.Sprintf("A ball tree cannot be used with the %T distance.", distancer));
+      }
+      return newBallTree(distancer, data);
+   case KNN_INDEX_HNSW:
+      var index *hnswIndex = newHnswIndex(distancer, hnswParams);
+      for _, tuple := range(data) {
+         index.insert(tuple);
+      }
+      return index;
+   default:
+      panic(fmt.Sprintf("Unknown KNN index type: %d", indexType));
+   }
+}
+
+func chooseKnnIndex(distancer base.Distancer, data []base.NumericTuple) KnnIndexType {
+   if (len(data) < KNN_INDEX_AUTO_MIN_TREE_SIZE) {
+      return KNN_INDEX_BRUTE;
+   }
+
+   if (supportsKdTree(distancer) && data[0].DataSize() <= KNN_INDEX_AUTO_MAX_KD_DIMENSIONS) {
+      return KNN_INDEX_KD_TREE;
+   }
+
+   if (!isMetric(distancer)) {
+      return KNN_INDEX_BRUTE;
+   }
+
+   return KNN_INDEX_BALL_TREE;
+}
+
+// A KD-tree needs the distance to a bounding box to be the distance to the closest point in the box.
+// This is true for distances that only grow as the difference in any coordinate grows.
+func supportsKdTree(distancer base.Distancer) bool {
+   switch distancer.(type) {
+   case base.Euclidean, *base.Euclidean,
+         base.WeightedEuclidean, *base.WeightedEuclidean,
+         base.Manhattan, *base.Manhattan,
+         base.Chebyshev, *base.Chebyshev,
+         base.Minkowski, *base.Minkowski:
+      return true;
+   default:
+      return false;
+   }
+}
+
+// Only the distances known to obey the triangle inequality.
+// KNN_INDEX_AUTO will not use a ball tree for any other distance.
+func isMetric(distancer base.Distancer) bool {
+   switch distance := distancer.(type) {
+   case base.Euclidean, *base.Euclidean,
+         base.WeightedEuclidean, *base.WeightedEuclidean,
+         base.Manhattan, *base.Manhattan,
+         base.Chebyshev, *base.Chebyshev,
+         base.Mahalanobis, *base.Mahalanobis,
+         base.Hamming, *base.Hamming,
+         base.Jaccard, *base.Jaccard:
+      return true;
+   case base.Minkowski:
+      return distance.P >= 1;
+   case *base.Minkowski:
+      return distance.P >= 1;
+   default:
+      return false;
+   }
+}
+
+// The distances known to break the triangle inequality.
+// Other unknown distances can still be used with an explicit KNN_INDEX_BALL_TREE.
+func isNonMetric(distancer base.Distancer) bool {
+   switch distance := distancer.(type) {
+   case base.Cosine, *base.Cosine, base.Correlation, *base.Correlation:
+      return true;
+   case base.Minkowski:
+      return distance.P < 1;
+   case *base.Minkowski:
+      return distance.P < 1;
+   default:
+      return false;
+   }
+}
+
+type bruteForceIndex struct {
+   distancer base.Distancer
+   data []base.NumericTuple
+}
+
+func (this bruteForceIndex) query(tuple base.NumericTuple, k int) []DistanceRecord {
+   var neighbors neighborHeap = make(neighborHeap, 0, k + 1);
+   for _, record := range(calculateDistances(this.distancer, this.data, tuple)) {
+      neighbors.offer(record, k);
+   }
+
+   return neighbors.sorted();
+}
+
+// A max heap (the worst neighbor on top) of the best neighbors seen so far.
+type neighborHeap []DistanceRecord;
+
+func (this neighborHeap) Len() int {
+   return len(this);
+}
+
+func (this neighborHeap) Less(i, j int) bool {
+   return ByDistance(this).Less(j, i);
+}
+
+func (this neighborHeap) Swap(i, j int) {
+   this[i], this[j] = this[j], this[i];
+}
+
+func (this *neighborHeap) Push(record interface{}) {
+   *this = append(*this, record.(DistanceRecord));
+}
+
+func (this *neighborHeap) Pop() interface{} {
+   var old neighborHeap = *this;
+   var record DistanceRecord = old[len(old) - 1];
+   *this = old[:len(old) - 1];
+   return record;
+}
+
+// Keep |record| if it is one of the best k.
+// Returns true if the record was kept.
+func (this *neighborHeap) offer(record DistanceRecord, k int) bool {
+   if (len(*this) < k) {
+      heap.Push(this, record);
+      return true;
+   }
+
+   if (ByDistance([]DistanceRecord{record, (*this)[0]}).Less(0, 1)) {
+      (*this)[0] = record;
+      heap.Fix(this, 0);
+      return true;
+   }
+
+   return false;
+}
+
+// The distance that a point must beat to get in.
+// Infinite until there are k neighbors.
+func (this neighborHeap) worstDistance(k int) float64 {
+   if (len(this) < k) {
+      return math.Inf(1);
+   }
+
+   return this[0].Distance;
+}
+
+func (this neighborHeap) sorted() []DistanceRecord {
+   var records []DistanceRecord = append([]DistanceRecord(nil), this...);
+   sort.Sort(ByDistance(records));
+   return records;
+}
+
+// Split |indexes| in half at the median of the dimension with the largest spread.
+// Returns false if all the points are the same.
+func splitAtMedian(points [][]float64, indexes []int) ([]int, []int, int, float64, bool) {
+   var bestDimension int = -1;
+   var bestSpread float64 = 0;
+
+   for dimension, _ := range(points[indexes[0]]) {
+      var min float64 = math.Inf(1);
+      var max float64 = math.Inf(-1);
+      for _, index := range(indexes) {
+         min = math.Min(min, points[index][dimension]);
+         max = math.Max(max, points[index][dimension]);
+      }
+
+      if (max - min > bestSpread) {
+         bestSpread = max - min;
+         bestDimension = dimension;
+      }
+   }
+
+   if (bestDimension == -1) {
+      return nil, nil, -1, 0, false;
+   }
+
+   var records []ValueRecord = make([]ValueRecord, len(indexes));
+   for i, index := range(indexes) {
+      records[i] = ValueRecord{points[index][bestDimension], index};
+   }
+   sort.Sort(ByValue(records));
+
+   var median int = len(records) / 2;
+   var left []int = make([]int, median);
+   var right []int = make([]int, len(records) - median);
+   for i, record := range(records) {
+      if (i < median) {
+         left[i] = record.Index;
+      } else {
+         right[i - median] = record.Index;
+      }
+   }
+
+   return left, right, bestDimension, records[median].Value, true;
+}
+
+func toFloatSlices(data []base.NumericTuple) [][]float64 {
+   var points [][]float64 = make([][]float64, len(data));
+   for i, tuple := range(data) {
+      points[i] = tuple.ToFloatSlice();
+   }
+
+   return points;
+}
@@ -0,0 +1,140 @@
+package classification
+
+import (
+   "math"
+   "math/rand"
+   "testing"
+
+   "github.com/eriq-augustine/goml/base"
+)
+
+type knnIndexTestCase struct {
+   Name string
+   IndexType KnnIndexType
+   Distancer base.Distancer
+   NumPoints int
+   NumDimensions int
+   // Round the coordinates so there are lots of duplicates and tied distances.
+   Rounded bool
+}
+
+// A metric that the indexes do not know about.
+type customEuclidean struct{}
+
+func (this customEuclidean) Distance(x base.NumericTuple, y base.NumericTuple) float64 {
+   return base.Euclidean{}.Distance(x, y);
+}
+
+func randomNumericTuples(random *rand.Rand, count int, dimensions int, rounded bool) []base.NumericTuple {
+   var tuples []base.NumericTuple = make([]base.NumericTuple, count);
+   for i, _ := range(tuples) {
+      var values []float64 = make([]float64, dimensions);
+      for j, _ := range(values) {
+         values[j] = random.NormFloat64();
+         if (rounded) {
+            values[j] = math.Round(values[j]);
+         }
+      }
+      tuples[i] = base.NewFloatTuple(values, nil);
+   }
+
+   return tuples;
+}
+
+// Every index must give exactly the same answers as brute force.
+func TestKnnIndexMatchesBruteForce(t *testing.T) {
+   var testCases []knnIndexTestCase = []knnIndexTestCase{
+      knnIndexTestCase{"KD - Low Dimension", KNN_INDEX_KD_TREE, base.Euclidean{}, 3000, 3, false},
+      knnIndexTestCase{"KD - High Dimension", KNN_INDEX_KD_TREE, base.Euclidean{}, 1000, 20, false},
+      knnIndexTestCase{"KD - Duplicates", KNN_INDEX_KD_TREE, base.Euclidean{}, 2000, 2, true},
+      knnIndexTestCase{"KD - Manhattan", KNN_INDEX_KD_TREE, base.Manhattan{}, 2000, 4, false},
+      knnIndexTestCase{"KD - Chebyshev", KNN_INDEX_KD_TREE, base.Chebyshev{}, 2000, 4, false},
+      knnIndexTestCase{"KD - Minkowski", KNN_INDEX_KD_TREE, base.NewMinkowski(3), 2000, 4, false},
+      knnIndexTestCase{"KD - Weighted", KNN_INDEX_KD_TREE, base.NewWeightedEuclidean([]float64{1, 0.5, 2}), 2000, 3, false},
+      knnIndexTestCase{"Ball - Euclidean", KNN_INDEX_BALL_TREE, base.Euclidean{}, 3000, 5, false},
+      knnIndexTestCase{"Ball - Manhattan", KNN_INDEX_BALL_TREE, base.Manhattan{}, 2000, 8, false},
+      knnIndexTestCase{"Ball - Duplicates", KNN_INDEX_BALL_TREE, base.Manhattan{}, 2000, 2, true},
+      knnIndexTestCase{"Ball - Hamming", KNN_INDEX_BALL_TREE, base.Hamming{}, 2000, 6, true},
+      knnIndexTestCase{"Ball - Custom", KNN_INDEX_BALL_TREE, customEuclidean{}, 2000, 20, false},
+      knnIndexTestCase{"Auto", KNN_INDEX_AUTO, base.Euclidean{}, 1500, 4, false},
+   };
+
+   for _, testCase := range(testCases) {
+      var random *rand.Rand = rand.New(rand.NewSource(4));
+      var data []base.NumericTuple = randomNumericTuples(random, testCase.NumPoints, testCase.NumDimensions, testCase.Rounded);
+      var queries []base.NumericTuple = randomNumericTuples(random, 25, testCase.NumDimensions, testCase.Rounded);
+
+      var brute knnIndex = newKnnIndex(KNN_INDEX_BRUTE, testCase.Distancer, data, HnswParams{});
+      var index knnIndex = newKnnIndex(testCase.IndexType, testCase.Distancer, data, HnswParams{});
+
+      for _, k := range([]int{1, 5, 50}) {
+         for queryIndex, query := range(queries) {
+            var expected []DistanceRecord = brute.query(query, k);
+            var actual []DistanceRecord = index.query(query, k);
+
+            if (len(expected) != len(actual)) {
+               t.Errorf("(%s)[k=%d][%d] -- Bad number of neighbors. Expected: %d, Got: %d", testCase.Name, k, queryIndex, len(expected), len(actual));
+               continue;
+            }
+
+            for i, _ := range(expected) {
+               if (expected[i] != actual[i]) {
+                  t.Errorf("(%s)[k=%d][%d][%d] -- Bad neighbor. Expected: %v, Got: %v", testCase.Name, k, queryIndex, i, expected[i], actual[i]);
+                  break;
+               }
+            }
+         }
+      }
+   }
+}
+
+func TestKnnIndexAuto(t *testing.T) {
+   var random *rand.Rand = rand.New(rand.NewSource(4));
+
+   if (chooseKnnIndex(base.Euclidean{}, randomNumericTuples(random, 10, 2, false)) != KNN_INDEX_BRUTE) {
+      t.Errorf("Expected brute force for small data.");
+   }
+
+   if (chooseKnnIndex(base.Euclidean{}, randomNumericTuples(random, KNN_INDEX_AUTO_MIN_TREE_SIZE, 2, false)) != KNN_INDEX_KD_TREE) {
+      t.Errorf("Expected a KD-tree for low dimensional euclidean data.");
+   }
+
+   if (chooseKnnIndex(base.Euclidean{}, randomNumericTuples(random, KNN_INDEX_AUTO_MIN_TREE_SIZE, KNN_INDEX_AUTO_MAX_KD_DIMENSIONS + 1, false)) != KNN_INDEX_BALL_TREE) {
+      t.Errorf("Expected a ball tree for high dimensional data.");
+   }
+
+   if (chooseKnnIndex(base.Hamming{}, randomNumericTuples(random, KNN_INDEX_AUTO_MIN_TREE_SIZE, 2, false)) != KNN_INDEX_BALL_TREE) {
+      t.Errorf("Expected a ball tree for a non-coordinate distance.");
+   }
+
+   if (chooseKnnIndex(base.Cosine{}, randomNumericTuples(random, KNN_INDEX_AUTO_MIN_TREE_SIZE, 2, false)) != KNN_INDEX_BRUTE) {
+      t.Errorf("Expected brute force for a distance without the triangle inequality.");
+   }
+
+   if (chooseKnnIndex(customEuclidean{}, randomNumericTuples(random, KNN_INDEX_AUTO_MIN_TREE_SIZE, 2, false)) != KNN_INDEX_BRUTE) {
+      t.Errorf("Expected brute force for an unknown distance.");
+   }
+}
+
+func BenchmarkKnnIndexKdTree(b *testing.B) {
+   benchmarkKnnIndex(b, KNN_INDEX_KD_TREE);
+}
+
+func BenchmarkKnnIndexBallTree(b *testing.B) {
+   benchmarkKnnIndex(b, KNN_INDEX_BALL_TREE);
+}
+
+func BenchmarkKnnIndexBrute(b *testing.B) {
+   benchmarkKnnIndex(b, KNN_INDEX_BRUTE);
+}
+
+func benchmarkKnnIndex(b *testing.B, indexType KnnIndexType) {
+   var random *rand.Rand = rand.New(rand.NewSource(4));
+   var index knnIndex = newKnnIndex(indexType, base.Euclidean{}, randomNumericTuples(random, 100000, 3, false), HnswParams{});
+   var queries []base.NumericTuple = randomNumericTuples(random, 100, 3, false);
+
+   b.ResetTimer();
+   for i := 0; i < b.N; i++ {
+      index.query(queries[i % len(queries)], 10);
+   }
+}
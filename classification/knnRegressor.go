@@ -0,0 +1,63 @@
+package classification
+
+// k-nearest neighbors regression.
+// The prediction is the (vote weighted) mean of the numeric classes of the k nearest neighbors.
+// Neighbors are found and weighted exactly the same way as Knn.
+// This satisfies regression.Regressor.
+
+import (
+   "fmt"
+
+   "github.com/eriq-augustine/goml/base"
+   "github.com/eriq-augustine/goml/features"
+)
+
+type KnnRegressor struct {
+   knn *Knn
+   // Parallel to the knn's training data.
+   targets []float64
+}
+
+func NewKnnRegressor(k int, reducer features.Reducer, distancer base.Distancer, options KnnOptions) *KnnRegressor {
+   var regressor KnnRegressor = KnnRegressor{
+      knn: NewKnnWithOptions(k, reducer, distancer, options),
+      targets: nil,
+   };
+
+   return &regressor;
+}
+
+// The KnnRegressor now owns |data|.
+func (this *KnnRegressor) Train(data []base.Tuple) {
+   this.knn.Train(data);
+
+   this.targets = make([]float64, len(this.knn.trainingData));
+   for i, tuple := range(this.knn.trainingData) {
+      numericClass, ok := tuple.GetClass().(base.NumericFeature);
+      if (!ok) {
+         panic(fmt.Sprintf("KnnRegressor requires numeric classes. Tuple[%d] class: %T", i, tuple.GetClass()));
+      }
+      this.targets[i] = numericClass.NumericValue();
+   }
+}
+
+func (this KnnRegressor) Predict(tuples []base.Tuple) []float64 {
+   tuples = this.knn.reducer.Reduce(tuples);
+
+   var predictions []float64 = make([]float64, len(tuples));
+   for i, tuple := range(tuples) {
+      this.knn.checkTuple(tuple);
+
+      var neighbors []DistanceRecord = this.knn.nearestNeighbors(tuple);
+      var voteWeights []float64 = this.knn.voteWeights(neighbors);
+
+      var totalWeight float64 = 0;
+      for j, neighbor := range(neighbors) {
+         predictions[i] += voteWeights[j] * this.targets[neighbor.Index];
+         totalWeight += voteWeights[j];
+      }
+      predictions[i] /= totalWeight;
+   }
+
+   return predictions;
+}
@@ -0,0 +1,72 @@
+package classification
+
+import (
+   "math"
+   "testing"
+
+   "github.com/eriq-augustine/goml/base"
+   "github.com/eriq-augustine/goml/features"
+   "github.com/eriq-augustine/goml/regression"
+)
+
+var _ regression.Regressor = &KnnRegressor{};
+
+type knnRegressorTestCase struct {
+   Name string
+   K int
+   Reducer features.Reducer
+   Voting KnnVoting
+   Input []base.Tuple
+   ExpectedPredictions []float64
+}
+
+func TestKnnRegressorBase(t *testing.T) {
+   // y = x on [0, 10], with an extra noise feature that gets reduced away.
+   var data []base.Tuple = make([]base.Tuple, 0);
+   for x := 0; x <= 10; x++ {
+      data = append(data, base.NewFloatTuple([]float64{float64(x), float64(x * 7 % 3)}, float64(x)));
+   }
+
+   var testCases []knnRegressorTestCase = []knnRegressorTestCase{
+      knnRegressorTestCase{
+         "Uniform",
+         2,
+         features.NewManualReducer([]int{0}),
+         KNN_VOTE_UNIFORM,
+         []base.Tuple{
+            base.NewFloatTuple([]float64{2.4, 100}, nil),
+            base.NewFloatTuple([]float64{-5, 100}, nil),
+         },
+         []float64{
+            2.5,
+            0.5,
+         },
+      },
+      knnRegressorTestCase{
+         "Inverse Distance",
+         2,
+         features.NewManualReducer([]int{0}),
+         KNN_VOTE_INVERSE_DISTANCE,
+         []base.Tuple{
+            base.NewFloatTuple([]float64{2.2, 100}, nil),
+            base.NewFloatTuple([]float64{7, 100}, nil),
+         },
+         []float64{
+            2.2,
+            7.0,
+         },
+      },
+   };
+
+   for _, testCase := range(testCases) {
+      var regressor regression.Regressor = NewKnnRegressor(testCase.K, testCase.Reducer, nil, KnnOptions{Voting: testCase.Voting});
+      regressor.Train(data);
+
+      var predictions []float64 = regressor.Predict(testCase.Input);
+      for i, _ := range(predictions) {
+         if (math.Abs(predictions[i] - testCase.ExpectedPredictions[i]) > 1e-6) {
+            t.Errorf("(%s)[%d] -- Bad prediction. Expected: %v, Got: %v", testCase.Name, i, testCase.ExpectedPredictions[i], predictions[i]);
+         }
+      }
+   }
+}
@@ -275,3 +275,148 @@ func TestKnnBase(t *testing.T) {
       }
    }
 }
+
+// The votes of heavily weighted neighbors should win out over closer ones.
+func TestKnnWeighted(t *testing.T) {
+   var data []base.Tuple = []base.Tuple{
+      base.NewIntTuple([]interface{}{1}, "A"),
+      base.NewIntTuple([]interface{}{2}, "A"),
+      base.NewIntTuple([]interface{}{3}, "B"),
+   };
+
+   var knn *Knn = NewKnn(3, nil, nil);
+   knn.TrainWeighted(data, []float64{1, 1, 5});
+
+   classes, _ := knn.Classify([]base.Tuple{base.NewIntTuple([]interface{}{0}, nil)});
+   if (classes[0] != base.String("B")) {
+      t.Errorf("Bad weighted classification. Expected: %v, Got: %v", base.String("B"), classes[0]);
+   }
+
+   knn.Train(data);
+   classes, _ = knn.Classify([]base.Tuple{base.NewIntTuple([]interface{}{0}, nil)});
+   if (classes[0] != base.String("A")) {
+      t.Errorf("Bad unweighted classification. Expected: %v, Got: %v", base.String("A"), classes[0]);
+   }
+}
+
+type knnVotingTestCase struct {
+   Voting KnnVoting
+   ExpectedClass base.Feature
+   ExpectedConfidence float64
+}
+
+func TestKnnVoting(t *testing.T) {
+   // One close "A", two further "B".
+   var data []base.Tuple = []base.Tuple{
+      base.NewIntTuple([]interface{}{1}, "A"),
+      base.NewIntTuple([]interface{}{5}, "B"),
+      base.NewIntTuple([]interface{}{6}, "B"),
+   };
+   var input []base.Tuple = []base.Tuple{base.NewIntTuple([]interface{}{0}, nil)};
+
+   var testCases []knnVotingTestCase = []knnVotingTestCase{
+      knnVotingTestCase{KNN_VOTE_INVERSE_DISTANCE, base.String("A"), 1.0 / (1.0 + 1.0 / 5.0 + 1.0 / 6.0)},
+      knnVotingTestCase{KNN_VOTE_KERNEL, base.String("B"), 1.0 - math.Exp(-0.5 / 36.0) / (math.Exp(-0.5 / 36.0) + math.Exp(-0.5 * 25.0 / 36.0) + math.Exp(-0.5))},
+   };
+
+   for _, testCase := range(testCases) {
+      var knn *Knn = NewKnnWithOptions(3, nil, nil, KnnOptions{Voting: testCase.Voting});
+      knn.Train(data);
+
+      classes, confidences := knn.Classify(input);
+      if (classes[0] != testCase.ExpectedClass) {
+         t.Errorf("(%d) -- Bad classification. Expected: %v, Got: %v", testCase.Voting, testCase.ExpectedClass, classes[0]);
+      }
+
+      if (!util.FloatEquals(confidences[0], testCase.ExpectedConfidence)) {
+         t.Errorf("(%d) -- Bad confidence. Expected: %v, Got: %v", testCase.Voting, testCase.ExpectedConfidence, confidences[0]);
+      }
+   }
+}
+
+// Ties go to the class with the nearest neighbor, and then the earliest training tuple.
+func TestKnnTieBreaking(t *testing.T) {
+   var data []base.Tuple = []base.Tuple{
+      base.NewIntTuple([]interface{}{1}, "A"),
+      base.NewIntTuple([]interface{}{-1}, "B"),
+      base.NewIntTuple([]interface{}{2}, "C"),
+      base.NewIntTuple([]interface{}{-3}, "D"),
+   };
+
+   var knn *Knn = NewKnn(4, nil, nil);
+   knn.Train(data);
+
+   for i := 0; i < 50; i++ {
+      classes, _ := knn.Classify([]base.Tuple{base.NewIntTuple([]interface{}{0}, nil)});
+      if (classes[0] != base.String("A")) {
+         t.Fatalf("[%d] -- Bad tie break. Expected: %v, Got: %v", i, base.String("A"), classes[0]);
+      }
+   }
+
+   data[0], data[1] = data[1], data[0];
+   knn.Train(data);
+
+   classes, _ := knn.Classify([]base.Tuple{base.NewIntTuple([]interface{}{0}, nil)});
+   if (classes[0] != base.String("B")) {
+      t.Errorf("Bad tie break. Expected: %v, Got: %v", base.String("B"), classes[0]);
+   }
+}
+
+// Mixed string, numeric, bool and missing features through the Gower distance.
+func TestKnnGower(t *testing.T) {
+   var data []base.Tuple = []base.Tuple{
+      base.NewTuple([]interface{}{"red", 1.0, true}, "A"),
+      base.NewTuple([]interface{}{"red", 2.0, nil}, "A"),
+      base.NewTuple([]interface{}{"red", nil, true}, "A"),
+      base.NewTuple([]interface{}{"blue", 9.0, false}, "B"),
+      base.NewTuple([]interface{}{"blue", 10.0, nil}, "B"),
+      base.NewTuple([]interface{}{nil, 8.0, false}, "B"),
+   };
+
+   var input []base.Tuple = []base.Tuple{
+      base.NewTuple([]interface{}{"red", 3.0, true}, nil),
+      base.NewTuple([]interface{}{"blue", nil, false}, nil),
+      base.NewTuple([]interface{}{nil, 9.5, nil}, nil),
+   };
+   var expected []base.Feature = []base.Feature{base.String("A"), base.String("B"), base.String("B")};
+
+   var knn *Knn = NewKnn(3, nil, base.NewGower(data));
+   knn.Train(data);
+
+   classes, _ := knn.Classify(input);
+   for i, _ := range(expected) {
+      if (classes[i] != expected[i]) {
+         t.Errorf("[%d] -- Bad classification. Expected: %v, Got: %v", i, expected[i], classes[i]);
+      }
+   }
+
+   // Inserted tuples are used right away.
+   knn.Insert([]base.Tuple{
+      base.NewTuple([]interface{}{"green", 5.0, true}, "C"),
+      base.NewTuple([]interface{}{"green", 5.0, true}, "C"),
+   });
+
+   classes, _ = knn.Classify([]base.Tuple{base.NewTuple([]interface{}{"green", 5.0, nil}, nil)});
+   if (classes[0] != base.String("C")) {
+      t.Errorf("Bad classification after insert. Expected: %v, Got: %v", base.String("C"), classes[0]);
+   }
+}
+
+// Changing the weights after training must not change a Knn.
+func TestKnnTrainWeightedCopiesWeights(t *testing.T) {
+   var data []base.Tuple = []base.Tuple{
+      base.NewFloatTuple([]float64{0.0}, "A"),
+      base.NewFloatTuple([]float64{1.0}, "B"),
+      base.NewFloatTuple([]float64{2.0}, "B"),
+   };
+   var weights []float64 = []float64{5, 1, 1};
+
+   var knn *Knn = NewKnnWithOptions(3, nil, nil, KnnOptions{Index: KNN_INDEX_BRUTE});
+   knn.TrainWeighted(data, weights);
+   weights[0] = 0;
+
+   results, _ := knn.Classify([]base.Tuple{base.NewFloatTuple([]float64{1.0}, nil)});
+   if (results[0] != base.String("A")) {
+      t.Errorf("Bad classification. Expected: A, Got: %v", results[0]);
+   }
+}
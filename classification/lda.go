@@ -0,0 +1,167 @@
+package classification
+
+// Linear discriminant analysis.
+// Each class is modeled as a Gaussian, and all the classes share the same covariance matrix.
+// So the log posteriors are linear in the features:
+//    log(P(c | x)) = x' S^-1 m_c - m_c' S^-1 m_c / 2 + log(P(c)) - normalization
+// where m_c is the mean of class c and S is the pooled within-class covariance.
+// Only works on NumericTuple.
+// See features.LDAReducer to use the discriminant directions as features instead.
+
+import (
+   "fmt"
+   "math"
+
+   "github.com/eriq-augustine/goml/base"
+   "github.com/eriq-augustine/goml/features"
+   "github.com/eriq-augustine/goml/util"
+
+   "gonum.org/v1/gonum/mat"
+)
+
+const (
+   // Added to the diagonal of the covariance to keep it invertible (eg with constant features).
+   LDA_DEFAULT_REGULARIZATION = 1e-6
+)
+
+type LDA struct {
+   reducer features.Reducer
+   regularization float64
+   labels []base.Feature
+   // S^-1 m_c for each class.
+   coefficients [][]float64
+   // -m_c' S^-1 m_c / 2 + log(P(c)) for each class.
+   intercepts []float64
+}
+
+// Pass a negative value for |regularization| to get the default.
+func NewLDA(reducer features.Reducer, regularization float64) *LDA {
+   if (reducer == nil) {
+      reducer = features.NoReducer{};
+   }
+
+   if (regularization < 0) {
+      regularization = LDA_DEFAULT_REGULARIZATION;
+   }
+
+   var lda LDA = LDA{
+      reducer: reducer,
+      regularization: regularization,
+   };
+
+   return &lda;
+}
+
+func (this *LDA) Train(tuples []base.Tuple) {
+   this.reducer.Init(tuples);
+   tuples = this.reducer.Reduce(tuples);
+
+   var dataLabels []int;
+   this.labels, dataLabels = mapLabels(tuples);
+
+   var data [][]float64 = numericTupleData(tuples, "LDA");
+   var numFeatures int = len(data[0]);
+
+   if (len(data) <= len(this.labels)) {
+      panic(fmt.Sprintf("Need more tuples (%d) than classes (%d) to estimate a covariance.", len(data), len(this.labels)));
+   }
+
+   var classCounts []float64 = make([]float64, len(this.labels));
+   var means [][]float64 = make2DFloat(len(this.labels), numFeatures);
+   for i, point := range(data) {
+      classCounts[dataLabels[i]]++;
+      for j, value := range(point) {
+         means[dataLabels[i]][j] += value;
+      }
+   }
+
+   for label, mean := range(means) {
+      for j, _ := range(mean) {
+         mean[j] /= classCounts[label];
+      }
+   }
+
+   // Pooled within-class covariance.
+   var covariance *mat.SymDense = mat.NewSymDense(numFeatures, nil);
+   for i, point := range(data) {
+      var mean []float64 = means[dataLabels[i]];
+      for j := 0; j < numFeatures; j++ {
+         for k := j; k < numFeatures; k++ {
+            covariance.SetSym(j, k, covariance.At(j, k) + (point[j] - mean[j]) * (point[k] - mean[k]));
+         }
+      }
+   }
+
+   for j := 0; j < numFeatures; j++ {
+      for k := j; k < numFeatures; k++ {
+         var value float64 = covariance.At(j, k) / float64(len(data) - len(this.labels));
+         if (j == k) {
+            value += this.regularization;
+         }
+         covariance.SetSym(j, k, value);
+      }
+   }
+
+   var cholesky mat.Cholesky;
+   if (!cholesky.Factorize(covariance)) {
+      panic("Covariance is not positive definite, try more regularization.");
+   }
+
+   this.coefficients = make([][]float64, len(this.labels));
+   this.intercepts = make([]float64, len(this.labels));
+
+   for label, mean := range(means) {
+      var coefficients mat.VecDense;
+      err := cholesky.SolveVecTo(&coefficients, mat.NewVecDense(numFeatures, mean));
+      if (err != nil) {
+         panic(fmt.Sprintf("Failed to solve for the discriminant: %v", err));
+      }
+
+      this.coefficients[label] = mat.Col(nil, 0, &coefficients);
+      this.intercepts[label] = -0.5 * dot(mean, this.coefficients[label]) + math.Log(classCounts[label] / float64(len(data)));
+   }
+}
+
+// The confidence is the posterior probability of the chosen class.
+func (this LDA) Classify(tuples []base.Tuple) ([]base.Feature, []float64) {
+   tuples = this.reducer.Reduce(tuples);
+
+   var results []base.Feature = make([]base.Feature, len(tuples));
+   var confidences []float64 = make([]float64, len(tuples));
+
+   for i, tuple := range(tuples) {
+      var posteriors []float64 = this.LogPosteriors(tuple);
+      bestIndex, bestLogPosterior := util.Max(posteriors);
+
+      results[i] = this.labels[bestIndex];
+      confidences[i] = math.Exp(bestLogPosterior);
+   }
+
+   return results, confidences;
+}
+
+// Get the labels in the order used by LogPosteriors().
+func (this LDA) GetLabels() []base.Feature {
+   return append([]base.Feature(nil), this.labels...);
+}
+
+// Get the normalized log posterior for each class (in the same order as GetLabels()).
+// |tuple| should already be reduced.
+func (this LDA) LogPosteriors(tuple base.Tuple) []float64 {
+   var point []float64 = numericTupleData([]base.Tuple{tuple}, "LDA")[0];
+   if (len(point) != len(this.coefficients[0])) {
+      panic(fmt.Sprintf("Expected %d features, got %d.", len(this.coefficients[0]), len(point)));
+   }
+
+   var scores []float64 = make([]float64, len(this.labels));
+   for label, coefficients := range(this.coefficients) {
+      scores[label] = dot(point, coefficients) + this.intercepts[label];
+   }
+
+   var normalization float64 = util.LogSumExp(scores);
+   for i, _ := range(scores) {
+      scores[i] -= normalization;
+   }
+
+   return scores;
+}
@@ -0,0 +1,116 @@
+package classification
+
+import (
+   "math"
+   "testing"
+
+   "github.com/eriq-augustine/goml/base"
+   "github.com/eriq-augustine/goml/features"
+   "github.com/eriq-augustine/goml/util"
+)
+
+type ldaTestCase struct {
+   Name string
+   Reducer features.Reducer
+   Regularization float64
+   TestData []base.Tuple
+   Input []base.Tuple
+   ExpectedClasses []base.Feature
+   ExpectedConfidences []float64
+}
+
+func TestLDABase(t *testing.T) {
+   // Means of -2 and 2 with a pooled variance of 1, so log(P(B | x) / P(A | x)) = 4x.
+   var oneDimension []base.Tuple = []base.Tuple{
+      base.NewNumericTuple([]interface{}{-3.0}, "A"),
+      base.NewNumericTuple([]interface{}{-2.0}, "A"),
+      base.NewNumericTuple([]interface{}{-1.0}, "A"),
+      base.NewNumericTuple([]interface{}{1.0}, "B"),
+      base.NewNumericTuple([]interface{}{2.0}, "B"),
+      base.NewNumericTuple([]interface{}{3.0}, "B"),
+   };
+
+   var testCases []ldaTestCase = []ldaTestCase{
+      ldaTestCase{
+         "One Dimension",
+         nil,
+         0,
+         oneDimension,
+         []base.Tuple{
+            base.NewNumericTuple([]interface{}{0.5}, nil),
+            base.NewNumericTuple([]interface{}{-0.25}, nil),
+            base.NewNumericTuple([]interface{}{10.0}, nil),
+         },
+         []base.Feature{base.String("B"), base.String("A"), base.String("B")},
+         []float64{1.0 / (1.0 + math.Exp(-2.0)), 1.0 / (1.0 + math.Exp(-1.0)), 1.0 / (1.0 + math.Exp(-40.0))},
+      },
+      ldaTestCase{
+         // The second feature is the same for both classes (and uncorrelated with the first),
+         // the third is constant (so it needs some regularization).
+         "Shared Feature",
+         features.NewManualReducer([]int{0, 1, 2}),
+         -1,
+         []base.Tuple{
+            base.NewNumericTuple([]interface{}{-3.0, 1.0, 5.0}, "A"),
+            base.NewNumericTuple([]interface{}{-2.0, -2.0, 5.0}, "A"),
+            base.NewNumericTuple([]interface{}{-1.0, 1.0, 5.0}, "A"),
+            base.NewNumericTuple([]interface{}{1.0, 1.0, 5.0}, "B"),
+            base.NewNumericTuple([]interface{}{2.0, -2.0, 5.0}, "B"),
+            base.NewNumericTuple([]interface{}{3.0, 1.0, 5.0}, "B"),
+         },
+         []base.Tuple{
+            base.NewNumericTuple([]interface{}{0.5, 100.0, 5.0}, nil),
+         },
+         []base.Feature{base.String("B")},
+         []float64{1.0 / (1.0 + math.Exp(-2.0))},
+      },
+   };
+
+   for _, testCase := range(testCases) {
+      var lda *LDA = NewLDA(testCase.Reducer, testCase.Regularization);
+      lda.Train(testCase.TestData);
+
+      actualClasses, actualConfidences := lda.Classify(testCase.Input);
+      for i, _ := range(testCase.ExpectedClasses) {
+         if (actualClasses[i] != testCase.ExpectedClasses[i]) {
+            t.Errorf("(%s)[%d] -- Bad classification. Expected: %v, Got: %v", testCase.Name, i, testCase.ExpectedClasses[i], actualClasses[i]);
+         }
+
+         if (math.Abs(actualConfidences[i] - testCase.ExpectedConfidences[i]) > 1e-5) {
+            t.Errorf("(%s)[%d] -- Bad confidence. Expected: %v, Got: %v", testCase.Name, i, testCase.ExpectedConfidences[i], actualConfidences[i]);
+         }
+      }
+   }
+}
+
+// Priors shift the decision boundary towards the rarer class.
+func TestLDAPriors(t *testing.T) {
+   var data []base.Tuple = []base.Tuple{
+      base.NewNumericTuple([]interface{}{-3.0}, "A"),
+      base.NewNumericTuple([]interface{}{-2.0}, "A"),
+      base.NewNumericTuple([]interface{}{-1.0}, "A"),
+      base.NewNumericTuple([]interface{}{-3.0}, "A"),
+      base.NewNumericTuple([]interface{}{-2.0}, "A"),
+      base.NewNumericTuple([]interface{}{-1.0}, "A"),
+      base.NewNumericTuple([]interface{}{1.0}, "B"),
+      base.NewNumericTuple([]interface{}{2.0}, "B"),
+      base.NewNumericTuple([]interface{}{3.0}, "B"),
+   };
+
+   var lda *LDA = NewLDA(nil, 0);
+   lda.Train(data);
+
+   var posteriors []float64 = lda.LogPosteriors(base.NewNumericTuple([]interface{}{0.1}, nil));
+   if (!util.FloatEquals(math.Exp(posteriors[0]) + math.Exp(posteriors[1]), 1.0)) {
+      t.Errorf("Posteriors do not sum to one: %v", posteriors);
+   }
+
+   classes, _ := lda.Classify([]base.Tuple{base.NewNumericTuple([]interface{}{0.1}, nil)});
+   if (classes[0] != base.String("A")) {
+      t.Errorf("Bad classification. Expected: %v, Got: %v", base.String("A"), classes[0]);
+   }
+
+   if (lda.GetLabels()[0] != base.String("A") || lda.GetLabels()[1] != base.String("B")) {
+      t.Errorf("Bad labels: %v", lda.GetLabels());
+   }
+}
@@ -51,10 +51,20 @@ func NewLogisticRegression(reducer features.Reducer, optimizer optimize.Optimize
 }
 
 func (this *LogisticRegression) Train(tuples []base.Tuple) {
+   this.TrainWeighted(tuples, nil);
+}
+
+// Each tuple's term in the negative log likelihood is scaled by its weight.
+// Passing nil for |weights| weights every tuple equally.
+func (this *LogisticRegression) TrainWeighted(tuples []base.Tuple, weights []float64) {
    if (tuples == nil || len(tuples) == 0) {
       panic("Must provide tuples for training.")
    }
 
+   if (weights != nil && len(weights) != len(tuples)) {
+      panic(fmt.Sprintf("Number of weights (%d) must match the number of tuples (%d).", len(weights), len(tuples)));
+   }
+
    this.reducer.Init(tuples);
    tuples = this.reducer.Reduce(tuples);
 
@@ -91,7 +101,7 @@ func (this *LogisticRegression) Train(tuples []base.Tuple) {
       }
    }
 
-   this.train(numericData, dataLabels);
+   this.train(numericData, dataLabels, weights);
 }
 
 func (this LogisticRegression) Classify(tuples []base.Tuple) ([]base.Feature, []float64) {
@@ -121,7 +131,8 @@ func (this LogisticRegression) Classify(tuples []base.Tuple) ([]base.Feature, []
 // we don't deal with actual base.Tuple's.
 // Just raw slices of doubles and ints (which are the mapped class labels).
 
-func (this *LogisticRegression) train(data [][]float64, dataLabels []int) {
+// |sampleWeights| may be nil (all points are weighted equally).
+func (this *LogisticRegression) train(data [][]float64, dataLabels []int, sampleWeights []float64) {
    // Params = Weights                 + Intercepts
    //          (|labels| x |features|) + (|labels|)
    var initialParams []float64 = make([]float64, len(this.labels) * (1 + len(data[0])));
@@ -131,20 +142,20 @@ func (this *LogisticRegression) train(data [][]float64, dataLabels []int) {
          initialParams,
          util.RangeSlice(len(data)),
          func(params []float64) float64 {
-            return this.negativeLogLikelihoodOptimize(data, dataLabels, params);
+            return this.negativeLogLikelihoodOptimize(data, dataLabels, sampleWeights, params);
          },
          func(params []float64, points []int) []float64 {
-            return this.negativeLogLikelihoodGradientBatchOptimize(data, dataLabels, params, points);
+            return this.negativeLogLikelihoodGradientBatchOptimize(data, dataLabels, sampleWeights, params, points);
          },
       ));
    } else {
       this.weights, this.intercepts = this.unpackOptimizerParams(this.optimizer.Optimize(
          initialParams,
          func(params []float64) float64 {
-            return this.negativeLogLikelihoodOptimize(data, dataLabels, params);
+            return this.negativeLogLikelihoodOptimize(data, dataLabels, sampleWeights, params);
          },
          func(params []float64) []float64 {
-            return this.negativeLogLikelihoodGradientOptimize(data, dataLabels, params);
+            return this.negativeLogLikelihoodGradientOptimize(data, dataLabels, sampleWeights, params);
          },
       ));
    }
@@ -195,15 +206,16 @@ func probabilities(weights [][]float64, intercepts[]float64, data [][]float64) [
 // Math comes out to:
 // NLL = -[ sum(n over data){ sum(k over classes){ oneHotLabel(n, k) * (Wk dot x - logSumExp(Wj dot x)) } } ]
 // NLL = -[ sum(n over data){ sum(k over classes){ oneHotLabel(n, k) * log(prob(Xn, k)) } } ]
+// If |sampleWeights| is not nil, then each data point's term is scaled by its weight.
 func negativeLogLikelihood(
       weights [][]float64, intercepts []float64, l2Penalty float64,
-      data [][]float64, dataLabels []int) float64 {
+      data [][]float64, dataLabels []int, sampleWeights []float64) float64 {
    var probabilities [][]float64 = probabilities(weights, intercepts, data);
 
    var sum float64 = 0;
    for dataPointIndex, _ := range(data) {
       // One hot multiplication, the value is only active if the class is one that we are examining.
-      sum += math.Log(probabilities[dataPointIndex][dataLabels[dataPointIndex]]);
+      sum += sampleWeight(sampleWeights, dataPointIndex) * math.Log(probabilities[dataPointIndex][dataLabels[dataPointIndex]]);
    }
 
    // Add an l2 regularizer
@@ -224,7 +236,7 @@ func negativeLogLikelihood(
 // So, we will return a vector of gradients.
 func negativeLogLikelihoodGradient(
       weights [][]float64, intercepts []float64, l2Penalty float64,
-      data [][]float64, dataLabels []int) ([][]float64, []float64) {
+      data [][]float64, dataLabels []int, sampleWeights []float64) ([][]float64, []float64) {
    var probabilities [][]float64 = probabilities(weights, intercepts, data);
 
    // TODO(eriq): Allocate once and keep in struct?
@@ -248,6 +260,8 @@ func negativeLogLikelihoodGradient(
             val -= 1.0;
          }
 
+         val *= sampleWeight(sampleWeights, dataPointIndex);
+
          interceptGradients[classIndex] += val;
 
          for featureIndex := 0; featureIndex < len(data[0]); featureIndex++ {
@@ -296,23 +310,25 @@ func (this LogisticRegression) unpackOptimizerParams(params []float64) ([][]floa
 func (this LogisticRegression) negativeLogLikelihoodOptimize(
       data [][]float64,
       dataLabels []int,
+      sampleWeights []float64,
       params []float64) float64 {
    weights, intercepts := this.unpackOptimizerParams(params);
 
-   return negativeLogLikelihood(weights, intercepts, this.l2Penalty, data, dataLabels);
+   return negativeLogLikelihood(weights, intercepts, this.l2Penalty, data, dataLabels, sampleWeights);
 }
 
 // A wrapper for an optimizer function for NLL.
-// The first two params will be curried.
+// The first three params will be curried.
 func (this LogisticRegression) negativeLogLikelihoodGradientOptimize(
       data [][]float64,
       dataLabels []int,
+      sampleWeights []float64,
       params []float64) []float64 {
    weights, intercepts := this.unpackOptimizerParams(params);
 
    weightGradients, interceptGradients := negativeLogLikelihoodGradient(
          weights, intercepts, this.l2Penalty,
-         data, dataLabels);
+         data, dataLabels, sampleWeights);
 
    // Packup the gradients.
    var gradients []float64 = make([]float64, len(interceptGradients) + len(weightGradients) * len(weightGradients[0]));
@@ -330,14 +346,29 @@ func (this LogisticRegression) negativeLogLikelihoodGradientOptimize(
 }
 
 // A wrapper for a batch optimizer function for NLL.
-// The first param will be curried.
+// The first three params will be curried.
 func (this LogisticRegression) negativeLogLikelihoodGradientBatchOptimize(
       data [][]float64,
       dataLabels []int,
+      sampleWeights []float64,
       params []float64,
       points []int) []float64 {
+   var batchWeights []float64 = nil;
+   if (sampleWeights != nil) {
+      batchWeights = util.SelectIndexesFloat(sampleWeights, points);
+   }
+
    return this.negativeLogLikelihoodGradientOptimize(
-      util.SelectIndexesFloat2D(data, points), util.SelectIndexesInt(dataLabels, points), params);
+      util.SelectIndexesFloat2D(data, points), util.SelectIndexesInt(dataLabels, points), batchWeights, params);
+}
+
+// nil weights means everything is weighted 1.
+func sampleWeight(sampleWeights []float64, index int) float64 {
+   if (sampleWeights == nil) {
+      return 1.0;
+   }
+
+   return sampleWeights[index];
 }
 
 func dot(a []float64, b []float64) float64 {
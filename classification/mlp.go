@@ -0,0 +1,339 @@
+package classification
+
+// A multilayer perceptron (feed forward neural network) with a softmax output.
+// Trained by minimizing the (L2 regularized) negative log likelihood using any optimize.Optimizer.
+// Gradients are computed with backpropagation.
+
+import (
+   "fmt"
+   "math"
+   "math/rand"
+
+   "github.com/eriq-augustine/goml/base"
+   "github.com/eriq-augustine/goml/features"
+   "github.com/eriq-augustine/goml/optimize"
+   "github.com/eriq-augustine/goml/util"
+)
+
+const (
+   MLP_DEFAULT_HIDDEN_SIZE = 100
+   MLP_DEFAULT_L2_PENALTY = 1e-4
+)
+
+type MLPActivation int
+
+const (
+   MLP_RELU MLPActivation = iota
+   MLP_TANH
+   MLP_SIGMOID
+)
+
+type MLP struct {
+   reducer features.Reducer
+   optimizer optimize.Optimizer
+   // The size of each hidden layer.
+   hiddenLayers []int
+   activation MLPActivation
+   l2Penalty float64
+
+   labels []base.Feature
+   // The size of every layer (input, hidden, ..., output).
+   layerSizes []int
+   layers []mlpLayer
+}
+
+// The weights going into a layer.
+type mlpLayer struct {
+   // [output][input]
+   weights [][]float64
+   biases []float64
+}
+
+// A nil (or empty) |hiddenLayers| will use a single hidden layer of MLP_DEFAULT_HIDDEN_SIZE.
+// Note that 0 is a valid value for |l2Penalty|, pass -1 for default.
+func NewMLP(reducer features.Reducer, optimizer optimize.Optimizer, hiddenLayers []int, activation MLPActivation, l2Penalty float64) *MLP {
+   if (reducer == nil) {
+      reducer = features.NoReducer{};
+   }
+
+   if (optimizer == nil) {
+      optimizer = optimize.NewSGD(0, 0, 0, 0);
+   }
+
+   if (hiddenLayers == nil || len(hiddenLayers) == 0) {
+      hiddenLayers = []int{MLP_DEFAULT_HIDDEN_SIZE};
+   }
+
+   for i, size := range(hiddenLayers) {
+      if (size <= 0) {
+         panic(fmt.Sprintf("Hidden layers must have a positive size. Layer %d: %d", i, size));
+      }
+   }
+
+   if (l2Penalty < 0) {
+      l2Penalty = MLP_DEFAULT_L2_PENALTY;
+   }
+
+   var mlp MLP = MLP{
+      reducer: reducer,
+      optimizer: optimizer,
+      hiddenLayers: append([]int(nil), hiddenLayers...),
+      activation: activation,
+      l2Penalty: l2Penalty,
+   };
+
+   return &mlp;
+}
+
+func (this *MLP) Train(tuples []base.Tuple) {
+   if (tuples == nil || len(tuples) == 0) {
+      panic("Must provide tuples for training.")
+   }
+
+   this.reducer.Init(tuples);
+   tuples = this.reducer.Reduce(tuples);
+
+   var dataLabels []int;
+   this.labels, dataLabels = mapLabels(tuples);
+   var data [][]float64 = numericTupleData(tuples, "MLP");
+
+   this.layerSizes = append(append([]int{len(data[0])}, this.hiddenLayers...), len(this.labels));
+
+   var initialParams []float64 = this.initialParams(base.NewRandom());
+
+   var params []float64;
+   if (this.optimizer.SupportsBatch()) {
+      params = this.optimizer.OptimizeBatch(
+         initialParams,
+         util.RangeSlice(len(data)),
+         func(params []float64) float64 {
+            return this.negativeLogLikelihood(params, data, dataLabels);
+         },
+         func(params []float64, points []int) []float64 {
+            return this.negativeLogLikelihoodGradient(params, data, dataLabels, points);
+         },
+      );
+   } else {
+      params = this.optimizer.Optimize(
+         initialParams,
+         func(params []float64) float64 {
+            return this.negativeLogLikelihood(params, data, dataLabels);
+         },
+         func(params []float64) []float64 {
+            return this.negativeLogLikelihoodGradient(params, data, dataLabels, util.RangeSlice(len(data)));
+         },
+      );
+   }
+
+   this.layers = this.unpackParams(params);
+}
+
+// The confidence is the (softmax) probability of the chosen class.
+func (this MLP) Classify(tuples []base.Tuple) ([]base.Feature, []float64) {
+   tuples = this.reducer.Reduce(tuples);
+   var data [][]float64 = numericTupleData(tuples, "MLP");
+
+   var results []base.Feature = make([]base.Feature, len(tuples));
+   var confidences []float64 = make([]float64, len(tuples));
+
+   for i, dataPoint := range(data) {
+      var activations [][]float64 = this.forward(this.layers, dataPoint);
+      bestIndex, bestProbability := util.Max(activations[len(activations) - 1]);
+
+      results[i] = this.labels[bestIndex];
+      confidences[i] = bestProbability;
+   }
+
+   return results, confidences;
+}
+
+// Glorot (Xavier) uniform initialization for the weights, zero for the biases.
+// The weights cannot all start the same or every hidden unit would learn the same thing.
+func (this MLP) initialParams(random *rand.Rand) []float64 {
+   var params []float64 = make([]float64, this.numParams());
+
+   for _, layer := range(this.unpackParams(params)) {
+      var limit float64 = math.Sqrt(6.0 / float64(len(layer.weights) + len(layer.weights[0])));
+      for i, _ := range(layer.weights) {
+         for j, _ := range(layer.weights[i]) {
+            layer.weights[i][j] = (random.Float64() * 2.0 - 1.0) * limit;
+         }
+      }
+   }
+
+   return params;
+}
+
+func (this MLP) numParams() int {
+   var count int = 0;
+   for i := 1; i < len(this.layerSizes); i++ {
+      count += this.layerSizes[i] * (this.layerSizes[i - 1] + 1);
+   }
+   return count;
+}
+
+// Unpack the flat params from the optimizer into layers.
+// The layers share memory with |params|.
+// Params are packed layer by layer: [
+//    bias[0], ..., bias[O - 1],
+//    weight[0][0], ..., weight[0][I - 1],
+//    ...
+//    weight[O - 1][0], ..., weight[O - 1][I - 1],
+//    (next layer)
+// ]
+// I - Size of the layer's input
+// O - Size of the layer's output
+func (this MLP) unpackParams(params []float64) []mlpLayer {
+   var layers []mlpLayer = make([]mlpLayer, len(this.layerSizes) - 1);
+
+   var offset int = 0;
+   for i, _ := range(layers) {
+      var inputSize int = this.layerSizes[i];
+      var outputSize int = this.layerSizes[i + 1];
+
+      layers[i].biases = params[offset : offset + outputSize];
+      offset += outputSize;
+
+      layers[i].weights = make([][]float64, outputSize);
+      for j, _ := range(layers[i].weights) {
+         layers[i].weights[j] = params[offset : offset + inputSize];
+         offset += inputSize;
+      }
+   }
+
+   return layers;
+}
+
+// Get the output of every layer (the first is the input itself, the last is the softmax output).
+func (this MLP) forward(layers []mlpLayer, dataPoint []float64) [][]float64 {
+   var activations [][]float64 = make([][]float64, len(layers) + 1);
+   activations[0] = dataPoint;
+
+   for i, layer := range(layers) {
+      var output []float64 = make([]float64, len(layer.biases));
+      for j, _ := range(output) {
+         output[j] = layer.biases[j] + dot(layer.weights[j], activations[i]);
+      }
+
+      if (i == len(layers) - 1) {
+         output = softmax(output);
+      } else {
+         for j, _ := range(output) {
+            output[j] = this.activate(output[j]);
+         }
+      }
+
+      activations[i + 1] = output;
+   }
+
+   return activations;
+}
+
+// NLL = -sum(n over data){ log(prob(Xn, label(n))) } + l2Penalty / 2 * sum(weights^2)
+// Biases are not regularized.
+func (this MLP) negativeLogLikelihood(params []float64, data [][]float64, dataLabels []int) float64 {
+   var layers []mlpLayer = this.unpackParams(params);
+
+   var sum float64 = 0;
+   for i, dataPoint := range(data) {
+      var activations [][]float64 = this.forward(layers, dataPoint);
+      sum -= math.Log(math.Max(activations[len(activations) - 1][dataLabels[i]], util.EPSILON));
+   }
+
+   var regularizer float64 = 0;
+   for _, layer := range(layers) {
+      for _, weights := range(layer.weights) {
+         regularizer += dot(weights, weights);
+      }
+   }
+
+   return sum + this.l2Penalty / 2.0 * regularizer;
+}
+
+// Backpropagation over the points in |points|.
+// Returns the gradients packed the same way as the params.
+func (this MLP) negativeLogLikelihoodGradient(params []float64, data [][]float64, dataLabels []int, points []int) []float64 {
+   var layers []mlpLayer = this.unpackParams(params);
+
+   var gradients []float64 = make([]float64, len(params));
+   var gradientLayers []mlpLayer = this.unpackParams(gradients);
+
+   for _, point := range(points) {
+      var activations [][]float64 = this.forward(layers, data[point]);
+
+      // The error at the output of the current layer (w.r.t. the pre-activation values).
+      // For softmax + NLL, this is just (prob - oneHotLabel).
+      var delta []float64 = append([]float64(nil), activations[len(activations) - 1]...);
+      delta[dataLabels[point]] -= 1.0;
+
+      for layerIndex := len(layers) - 1; layerIndex >= 0; layerIndex-- {
+         var input []float64 = activations[layerIndex];
+
+         for j, _ := range(delta) {
+            gradientLayers[layerIndex].biases[j] += delta[j];
+            for k, _ := range(input) {
+               gradientLayers[layerIndex].weights[j][k] += delta[j] * input[k];
+            }
+         }
+
+         if (layerIndex == 0) {
+            break;
+         }
+
+         // Push the error back through the weights and then the activation.
+         var previousDelta []float64 = make([]float64, len(input));
+         for j, _ := range(delta) {
+            for k, _ := range(input) {
+               previousDelta[k] += delta[j] * layers[layerIndex].weights[j][k];
+            }
+         }
+
+         for k, _ := range(previousDelta) {
+            previousDelta[k] *= this.activationDerivative(input[k]);
+         }
+
+         delta = previousDelta;
+      }
+   }
+
+   // Add an l2 regularizer.
+   for layerIndex, layer := range(layers) {
+      for j, _ := range(layer.weights) {
+         for k, weight := range(layer.weights[j]) {
+            gradientLayers[layerIndex].weights[j][k] += this.l2Penalty * weight;
+         }
+      }
+   }
+
+   return gradients;
+}
+
+func (this MLP) activate(value float64) float64 {
+   switch this.activation {
+   case MLP_RELU:
+      return math.Max(0, value);
+   case MLP_TANH:
+      return math.Tanh(value);
+   case MLP_SIGMOID:
+      return 1.0 / (1.0 + math.Exp(-value));
+   default:
+      panic(fmt.Sprintf("Unknown MLP activation: %d", this.activation));
+   }
+}
+
+// The derivative of the activation expressed in terms of the activation's output.
+func (this MLP) activationDerivative(output float64) float64 {
+   switch this.activation {
+   case MLP_RELU:
+      if (output > 0) {
+         return 1.0;
+      }
+      return 0.0;
+   case MLP_TANH:
+      return 1.0 - output * output;
+   case MLP_SIGMOID:
+      return output * (1.0 - output);
+   default:
+      panic(fmt.Sprintf("Unknown MLP activation: %d", this.activation));
+   }
+}
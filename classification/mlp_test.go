@@ -0,0 +1,178 @@
+package classification
+
+import (
+   "math"
+   "testing"
+
+   "github.com/eriq-augustine/goml/base"
+   "github.com/eriq-augustine/goml/features"
+   "github.com/eriq-augustine/goml/optimize"
+)
+
+type mlpTestCase struct {
+   Name string
+   Reducer features.Reducer
+   Optimizer optimize.Optimizer
+   HiddenLayers []int
+   Activation MLPActivation
+   TestData []base.Tuple
+   Input []base.Tuple
+   ExpectedClasses []base.Feature
+   MinExpectedConfidences []float64
+}
+
+func TestMLPBase(t *testing.T) {
+   base.Seed(4);
+
+   var xorData []base.Tuple = []base.Tuple{
+      base.NewFloatTuple([]float64{0, 0}, 0),
+      base.NewFloatTuple([]float64{0, 1}, 1),
+      base.NewFloatTuple([]float64{1, 0}, 1),
+      base.NewFloatTuple([]float64{1, 1}, 0),
+   };
+
+   var testCases []mlpTestCase = []mlpTestCase{
+      mlpTestCase{
+         "Base - SGD",
+         features.NoReducer{},
+         optimize.NewSGD(500, 0.01, 1e-6, 0),
+         []int{4},
+         MLP_RELU,
+         []base.Tuple{
+            base.NewIntTuple([]interface{}{10, 10}, 1),
+            base.NewIntTuple([]interface{}{9, 9}, 1),
+            base.NewIntTuple([]interface{}{11, 11}, 1),
+            base.NewIntTuple([]interface{}{-10, -10}, 0),
+            base.NewIntTuple([]interface{}{-9, -9}, 0),
+            base.NewIntTuple([]interface{}{-11, -11}, 0),
+         },
+         []base.Tuple{
+            base.NewIntTuple([]interface{}{8, 8}, nil),
+            base.NewIntTuple([]interface{}{-8, -8}, nil),
+         },
+         []base.Feature{
+            base.Int(1),
+            base.Int(0),
+         },
+         []float64{
+            0.9,
+            0.9,
+         },
+      },
+      mlpTestCase{
+         "Reduced - GD",
+         features.NewManualReducer([]int{1, 3}),
+         optimize.NewGradientDescent(500, 0.01, 1e-6),
+         []int{4},
+         MLP_SIGMOID,
+         []base.Tuple{
+            base.NewIntTuple([]interface{}{1,  10, 0,  10, 6}, 1),
+            base.NewIntTuple([]interface{}{2,  9,  0,  9,  5}, 1),
+            base.NewIntTuple([]interface{}{3,  11, 0,  11, 4}, 1),
+            base.NewIntTuple([]interface{}{4, -10, 0, -10, 3}, 0),
+            base.NewIntTuple([]interface{}{5, -9,  0, -9,  2}, 0),
+            base.NewIntTuple([]interface{}{6, -11, 0, -11, 1}, 0),
+         },
+         []base.Tuple{
+            base.NewIntTuple([]interface{}{1,  8, 0,  8, 2}, nil),
+            base.NewIntTuple([]interface{}{2, -8, 0, -8, 1}, nil),
+         },
+         []base.Feature{
+            base.Int(1),
+            base.Int(0),
+         },
+         []float64{
+            0.9,
+            0.9,
+         },
+      },
+      mlpTestCase{
+         "XOR - Tanh",
+         nil,
+         optimize.NewSGD(5000, 0.1, 1e-9, 4),
+         []int{8},
+         MLP_TANH,
+         xorData,
+         xorData,
+         []base.Feature{
+            base.Int(0),
+            base.Int(1),
+            base.Int(1),
+            base.Int(0),
+         },
+         []float64{
+            0.9,
+            0.9,
+            0.9,
+            0.9,
+         },
+      },
+   };
+
+   for _, testCase := range(testCases) {
+      var mlp Classifier = NewMLP(testCase.Reducer, testCase.Optimizer, testCase.HiddenLayers, testCase.Activation, 0);
+      mlp.Train(testCase.TestData);
+      var actualClasses []base.Feature;
+      var actualConfidences []float64;
+
+      actualClasses, actualConfidences = mlp.Classify(testCase.Input);
+
+      if (len(actualClasses) != len(testCase.ExpectedClasses)) {
+         t.Errorf("(%s) -- Length of expected (%d) and actual classes (%d) do not match", testCase.Name, len(testCase.ExpectedClasses), len(actualClasses));
+         continue;
+      }
+
+      if (len(actualConfidences) != len(testCase.MinExpectedConfidences)) {
+         t.Errorf("(%s) -- Length of expected (%d) and actual confidences (%d) do not match", testCase.Name, len(testCase.MinExpectedConfidences), len(actualConfidences));
+         continue;
+      }
+
+      // Go over each value explicitly to make output more readable.
+      for i, _ := range(actualClasses) {
+         if (actualClasses[i] != testCase.ExpectedClasses[i]) {
+            t.Errorf("(%s)[%d] -- Bad classification. Expected classes: %v, Got: %v", testCase.Name, i, testCase.ExpectedClasses[i], actualClasses[i]);
+         }
+
+         if (actualConfidences[i] < testCase.MinExpectedConfidences[i] || actualConfidences[i] > 1.0) {
+            t.Errorf("(%s)[%d] -- Bad confidence. Expected min confidence: %v, Got: %v", testCase.Name, i, testCase.MinExpectedConfidences[i], actualConfidences[i]);
+         }
+      }
+   }
+}
+
+// Check the backprop gradient against a central finite difference.
+func TestMLPGradient(t *testing.T) {
+   base.Seed(4);
+
+   var data [][]float64 = [][]float64{
+      []float64{0.5, -1.0, 2.0},
+      []float64{-0.3, 0.8, -1.5},
+      []float64{1.2, 0.1, 0.4},
+   };
+   var dataLabels []int = []int{0, 2, 1};
+   var points []int = []int{0, 1, 2};
+
+   for _, activation := range([]MLPActivation{MLP_RELU, MLP_TANH, MLP_SIGMOID}) {
+      var mlp *MLP = NewMLP(nil, nil, []int{4, 3}, activation, 0.1);
+      mlp.layerSizes = []int{3, 4, 3, 3};
+
+      var params []float64 = mlp.initialParams(base.NewRandom());
+      var gradients []float64 = mlp.negativeLogLikelihoodGradient(params, data, dataLabels, points);
+
+      const step = 1e-6;
+      for i, _ := range(params) {
+         var original float64 = params[i];
+
+         params[i] = original + step;
+         var upper float64 = mlp.negativeLogLikelihood(params, data, dataLabels);
+         params[i] = original - step;
+         var lower float64 = mlp.negativeLogLikelihood(params, data, dataLabels);
+         params[i] = original;
+
+         var numeric float64 = (upper - lower) / (2.0 * step);
+         if (math.Abs(numeric - gradients[i]) > 1e-4) {
+            t.Errorf("(%d)[%d] -- Bad gradient. Numeric: %v, Backprop: %v", activation, i, numeric, gradients[i]);
+         }
+      }
+   }
+}
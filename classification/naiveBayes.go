@@ -0,0 +1,427 @@
+package classification
+
+// Naive Bayes with the likelihood for each feature chosen from the training data:
+//  - If every training tuple is an IntTuple, all features are treated as counts (multinomial).
+//  - Non-numeric (eg StringFeature) and BoolFeature features are categorical.
+//  - All other numeric features are Gaussian.
+// NilFeature values are skipped in both training and classification.
+// All posteriors are computed in log space.
+
+import (
+   "fmt"
+   "math"
+
+   "github.com/eriq-augustine/goml/base"
+   "github.com/eriq-augustine/goml/features"
+   "github.com/eriq-augustine/goml/util"
+)
+
+const (
+   NB_DEFAULT_SMOOTHING = 1.0
+   // Fraction of the largest feature variance added to all variances (for stability).
+   NB_VARIANCE_SMOOTHING = 1e-9
+)
+
+type nbFeatureType int
+
+const (
+   NB_FEATURE_IGNORED nbFeatureType = iota
+   NB_FEATURE_GAUSSIAN
+   NB_FEATURE_MULTINOMIAL
+   NB_FEATURE_CATEGORICAL
+)
+
+type NaiveBayes struct {
+   reducer features.Reducer
+   // Laplace (additive) smoothing for multinomial and categorical features.
+   smoothing float64
+
+   labels []base.Feature
+   logPriors []float64
+   featureTypes []nbFeatureType
+
+   // All the following are [class][feature].
+   // Gaussian
+   means [][]float64
+   variances [][]float64
+   // Multinomial
+   logProbabilities [][]float64
+   // Categorical
+   categoricalLogProbabilities [][]map[base.Feature]float64
+   // Used for categorical values never seen with a class.
+   unseenLogProbabilities [][]float64
+}
+
+// Note that 0 is a valid value for |smoothing|, pass -1 for default.
+func NewNaiveBayes(reducer features.Reducer, smoothing float64) *NaiveBayes {
+   if (reducer == nil) {
+      reducer = features.NoReducer{};
+   }
+
+   if (smoothing < 0) {
+      smoothing = NB_DEFAULT_SMOOTHING;
+   }
+
+   var nb NaiveBayes = NaiveBayes{
+      reducer: reducer,
+      smoothing: smoothing,
+   };
+
+   return &nb;
+}
+
+func (this *NaiveBayes) Train(tuples []base.Tuple) {
+   if (tuples == nil || len(tuples) == 0) {
+      panic("Must provide tuples for training.")
+   }
+
+   this.reducer.Init(tuples);
+   tuples = this.reducer.Reduce(tuples);
+
+   var numFeatures int = tuples[0].DataSize();
+   var dataLabels []int = make([]int, len(tuples));
+
+   // Note all the labels we have seen and assign them an arbitrary identifier (index into this.labels).
+   this.labels = make([]base.Feature, 0);
+   var labelMap map[base.Feature]int = make(map[base.Feature]int);
+
+   for i, tuple := range(tuples) {
+      if (numFeatures != tuple.DataSize()) {
+         panic(fmt.Sprintf("Inconsistent number of features. Tuple[0]: %d, Tuple[%d]: %d",
+               numFeatures, i, tuple.DataSize()));
+      }
+
+      _, ok := labelMap[tuple.GetClass()];
+      if (!ok) {
+         labelMap[tuple.GetClass()] = len(this.labels);
+         this.labels = append(this.labels, tuple.GetClass());
+      }
+
+      dataLabels[i] = labelMap[tuple.GetClass()];
+   }
+
+   var classCounts []float64 = make([]float64, len(this.labels));
+   for _, label := range(dataLabels) {
+      classCounts[label]++;
+   }
+
+   this.logPriors = make([]float64, len(this.labels));
+   for i, count := range(classCounts) {
+      this.logPriors[i] = math.Log(count / float64(len(tuples)));
+   }
+
+   this.featureTypes = inferNaiveBayesFeatureTypes(tuples);
+
+   this.trainGaussian(tuples, dataLabels);
+   this.trainMultinomial(tuples, dataLabels);
+   this.trainCategorical(tuples, dataLabels);
+}
+
+// The confidence is the posterior probability of the chosen class.
+func (this NaiveBayes) Classify(tuples []base.Tuple) ([]base.Feature, []float64) {
+   tuples = this.reducer.Reduce(tuples);
+
+   var results []base.Feature = make([]base.Feature, len(tuples));
+   var confidences []float64 = make([]float64, len(tuples));
+
+   for i, tuple := range(tuples) {
+      var posteriors []float64 = this.LogPosteriors(tuple);
+      bestIndex, bestLogPosterior := util.Max(posteriors);
+
+      results[i] = this.labels[bestIndex];
+      confidences[i] = math.Exp(bestLogPosterior);
+   }
+
+   return results, confidences;
+}
+
+// Get the labels in the order used by LogPosteriors().
+func (this NaiveBayes) GetLabels() []base.Feature {
+   return append([]base.Feature(nil), this.labels...);
+}
+
+// Get the normalized log posterior for each class (in the same order as GetLabels()).
+// |tuple| should already be reduced.
+func (this NaiveBayes) LogPosteriors(tuple base.Tuple) []float64 {
+   var joint []float64 = make([]float64, len(this.labels));
+
+   for classIndex, _ := range(this.labels) {
+      joint[classIndex] = this.logPriors[classIndex];
+
+      for featureIndex, featureType := range(this.featureTypes) {
+         var feature base.Feature = tuple.GetData(featureIndex);
+         if (isNilFeature(feature)) {
+            continue;
+         }
+
+         switch featureType {
+         case NB_FEATURE_GAUSSIAN:
+            joint[classIndex] += gaussianLogPdf(numericFeatureValue(feature),
+                  this.means[classIndex][featureIndex], this.variances[classIndex][featureIndex]);
+         case NB_FEATURE_MULTINOMIAL:
+            // A zero count contributes nothing, even if the probability is zero (no smoothing).
+            var count float64 = numericFeatureValue(feature);
+            if (count != 0) {
+               joint[classIndex] += count * this.logProbabilities[classIndex][featureIndex];
+            }
+         case NB_FEATURE_CATEGORICAL:
+            logProbability, ok := this.categoricalLogProbabilities[classIndex][featureIndex][feature];
+            if (!ok) {
+               logProbability = this.unseenLogProbabilities[classIndex][featureIndex];
+            }
+            joint[classIndex] += logProbability;
+         }
+      }
+   }
+
+   // Without smoothing, every class can be impossible. Then no class is preferred.
+   _, best := util.Max(joint);
+   if (math.IsInf(best, -1)) {
+      for i, _ := range(joint) {
+         joint[i] = -math.Log(float64(len(joint)));
+      }
+      return joint;
+   }
+
+   var normalization float64 = util.LogSumExp(joint);
+
+   for i, _ := range(joint) {
+      joint[i] -= normalization;
+   }
+
+   return joint;
+}
+
+func (this *NaiveBayes) trainGaussian(tuples []base.Tuple, dataLabels []int) {
+   var numFeatures int = len(this.featureTypes);
+
+   this.means = make2DFloat(len(this.labels), numFeatures);
+   this.variances = make2DFloat(len(this.labels), numFeatures);
+   var counts [][]float64 = make2DFloat(len(this.labels), numFeatures);
+
+   for i, tuple := range(tuples) {
+      for featureIndex, featureType := range(this.featureTypes) {
+         if (featureType != NB_FEATURE_GAUSSIAN || isNilFeature(tuple.GetData(featureIndex))) {
+            continue;
+         }
+
+         this.means[dataLabels[i]][featureIndex] += numericFeatureValue(tuple.GetData(featureIndex));
+         counts[dataLabels[i]][featureIndex]++;
+      }
+   }
+
+   for classIndex, _ := range(this.means) {
+      for featureIndex, _ := range(this.means[classIndex]) {
+         if (counts[classIndex][featureIndex] > 0) {
+            this.means[classIndex][featureIndex] /= counts[classIndex][featureIndex];
+         }
+      }
+   }
+
+   var maxVariance float64 = 0;
+   for i, tuple := range(tuples) {
+      for featureIndex, featureType := range(this.featureTypes) {
+         if (featureType != NB_FEATURE_GAUSSIAN || isNilFeature(tuple.GetData(featureIndex))) {
+            continue;
+         }
+
+         this.variances[dataLabels[i]][featureIndex] += math.Pow(numericFeatureValue(tuple.GetData(featureIndex)) - this.means[dataLabels[i]][featureIndex], 2);
+      }
+   }
+
+   for classIndex, _ := range(this.variances) {
+      for featureIndex, _ := range(this.variances[classIndex]) {
+         if (counts[classIndex][featureIndex] > 0) {
+            this.variances[classIndex][featureIndex] /= counts[classIndex][featureIndex];
+         }
+         maxVariance = math.Max(maxVariance, this.variances[classIndex][featureIndex]);
+      }
+   }
+
+   // Make sure no variance is zero (a constant feature would give an infinite density).
+   var epsilon float64 = math.Max(NB_VARIANCE_SMOOTHING * maxVariance, util.EPSILON);
+   for classIndex, _ := range(this.variances) {
+      for featureIndex, _ := range(this.variances[classIndex]) {
+         this.variances[classIndex][featureIndex] += epsilon;
+      }
+   }
+}
+
+// log(theta[class][feature]) = log((count[class][feature] + alpha) / (sum(count[class]) + alpha * |features|))
+func (this *NaiveBayes) trainMultinomial(tuples []base.Tuple, dataLabels []int) {
+   var numFeatures int = len(this.featureTypes);
+
+   var counts [][]float64 = make2DFloat(len(this.labels), numFeatures);
+   var totals []float64 = make([]float64, len(this.labels));
+   var numMultinomial int = 0;
+
+   for _, featureType := range(this.featureTypes) {
+      if (featureType == NB_FEATURE_MULTINOMIAL) {
+         numMultinomial++;
+      }
+   }
+
+   for i, tuple := range(tuples) {
+      for featureIndex, featureType := range(this.featureTypes) {
+         if (featureType != NB_FEATURE_MULTINOMIAL || isNilFeature(tuple.GetData(featureIndex))) {
+            continue;
+         }
+
+         var count float64 = numericFeatureValue(tuple.GetData(featureIndex));
+         if (count < 0) {
+            panic(fmt.Sprintf("Multinomial Naive Bayes requires non-negative counts. Tuple[%d][%d]: %v", i, featureIndex, count));
+         }
+
+         counts[dataLabels[i]][featureIndex] += count;
+         totals[dataLabels[i]] += count;
+      }
+   }
+
+   this.logProbabilities = make2DFloat(len(this.labels), numFeatures);
+   for classIndex, _ := range(counts) {
+      var denominator float64 = totals[classIndex] + this.smoothing * float64(numMultinomial);
+
+      for featureIndex, featureType := range(this.featureTypes) {
+         if (featureType != NB_FEATURE_MULTINOMIAL) {
+            continue;
+         }
+
+         // A class with no counts (and no smoothing) gets the limit of smoothing -> 0: a uniform distribution.
+         if (denominator == 0) {
+            this.logProbabilities[classIndex][featureIndex] = -math.Log(float64(numMultinomial));
+            continue;
+         }
+
+         this.logProbabilities[classIndex][featureIndex] = math.Log(counts[classIndex][featureIndex] + this.smoothing) - math.Log(denominator);
+      }
+   }
+}
+
+// log(P(value | class)) = log((count[class][feature][value] + alpha) / (count[class][feature] + alpha * |values[feature]|))
+func (this *NaiveBayes) trainCategorical(tuples []base.Tuple, dataLabels []int) {
+   var numFeatures int = len(this.featureTypes);
+
+   // [class][feature]{value -> count}
+   var counts [][]map[base.Feature]float64 = make([][]map[base.Feature]float64, len(this.labels));
+   var totals [][]float64 = make2DFloat(len(this.labels), numFeatures);
+   // [feature]{value}
+   var vocabularies []map[base.Feature]bool = make([]map[base.Feature]bool, numFeatures);
+
+   for classIndex, _ := range(counts) {
+      counts[classIndex] = make([]map[base.Feature]float64, numFeatures);
+      for featureIndex, _ := range(counts[classIndex]) {
+         counts[classIndex][featureIndex] = make(map[base.Feature]float64);
+      }
+   }
+
+   for featureIndex, _ := range(vocabularies) {
+      vocabularies[featureIndex] = make(map[base.Feature]bool);
+   }
+
+   for i, tuple := range(tuples) {
+      for featureIndex, featureType := range(this.featureTypes) {
+         var feature base.Feature = tuple.GetData(featureIndex);
+         if (featureType != NB_FEATURE_CATEGORICAL || isNilFeature(feature)) {
+            continue;
+         }
+
+         counts[dataLabels[i]][featureIndex][feature]++;
+         totals[dataLabels[i]][featureIndex]++;
+         vocabularies[featureIndex][feature] = true;
+      }
+   }
+
+   this.categoricalLogProbabilities = make([][]map[base.Feature]float64, len(this.labels));
+   this.unseenLogProbabilities = make2DFloat(len(this.labels), numFeatures);
+
+   for classIndex, _ := range(counts) {
+      this.categoricalLogProbabilities[classIndex] = make([]map[base.Feature]float64, numFeatures);
+
+      for featureIndex, featureType := range(this.featureTypes) {
+         this.categoricalLogProbabilities[classIndex][featureIndex] = make(map[base.Feature]float64);
+         if (featureType != NB_FEATURE_CATEGORICAL) {
+            continue;
+         }
+
+         // A class that never saw this feature (and no smoothing) gets the limit of smoothing -> 0: a uniform distribution.
+         if (totals[classIndex][featureIndex] == 0 && this.smoothing == 0) {
+            if (len(vocabularies[featureIndex]) > 0) {
+               this.unseenLogProbabilities[classIndex][featureIndex] = -math.Log(float64(len(vocabularies[featureIndex])));
+            }
+            continue;
+         }
+
+         var logDenominator float64 = math.Log(totals[classIndex][featureIndex] + this.smoothing * float64(len(vocabularies[featureIndex])));
+         for value, count := range(counts[classIndex][featureIndex]) {
+            this.categoricalLogProbabilities[classIndex][featureIndex][value] = math.Log(count + this.smoothing) - logDenominator;
+         }
+
+         this.unseenLogProbabilities[classIndex][featureIndex] = math.Log(this.smoothing) - logDenominator;
+      }
+   }
+}
+
+func inferNaiveBayesFeatureTypes(tuples []base.Tuple) []nbFeatureType {
+   var allInts bool = true;
+   for _, tuple := range(tuples) {
+      _, ok := tuple.(base.IntTuple);
+      if (!ok) {
+         allInts = false;
+         break;
+      }
+   }
+
+   var featureTypes []nbFeatureType = make([]nbFeatureType, tuples[0].DataSize());
+   for featureIndex, _ := range(featureTypes) {
+      // Use the first non-nil value to decide.
+      for _, tuple := range(tuples) {
+         var feature base.Feature = tuple.GetData(featureIndex);
+         if (isNilFeature(feature)) {
+            continue;
+         }
+
+         _, isBool := feature.(base.BoolFeature);
+         if (!feature.IsNumeric() || isBool) {
+            featureTypes[featureIndex] = NB_FEATURE_CATEGORICAL;
+         } else if (allInts) {
+            featureTypes[featureIndex] = NB_FEATURE_MULTINOMIAL;
+         } else {
+            featureTypes[featureIndex] = NB_FEATURE_GAUSSIAN;
+         }
+
+         break;
+      }
+   }
+
+   return featureTypes;
+}
+
+func gaussianLogPdf(value float64, mean float64, variance float64) float64 {
+   return -0.5 * (math.Log(2.0 * math.Pi * variance) + math.Pow(value - mean, 2) / variance);
+}
+
+func isNilFeature(feature base.Feature) bool {
+   if (feature == nil) {
+      return true;
+   }
+
+   _, ok := feature.(base.NilFeature);
+   return ok;
+}
+
+func numericFeatureValue(feature base.Feature) float64 {
+   numericFeature, ok := feature.(base.NumericFeature);
+   if (!ok) {
+      panic(fmt.Sprintf("Expected a numeric feature, found: %T", feature));
+   }
+
+   return numericFeature.NumericValue();
+}
+
+func make2DFloat(rows int, cols int) [][]float64 {
+   var rtn [][]float64 = make([][]float64, rows);
+   for i, _ := range(rtn) {
+      rtn[i] = make([]float64, cols);
+   }
+   return rtn;
+}
@@ -0,0 +1,238 @@
+package classification
+
+import (
+   "math"
+   "testing"
+
+   "github.com/eriq-augustine/goml/base"
+   "github.com/eriq-augustine/goml/features"
+)
+
+type nbTestCase struct {
+   Name string
+   Reducer features.Reducer
+   Smoothing float64
+   TestData []base.Tuple
+   Input []base.Tuple
+   ExpectedClasses []base.Feature
+   MinExpectedConfidences []float64
+}
+
+func TestNaiveBayesBase(t *testing.T) {
+   var testCases []nbTestCase = []nbTestCase{
+      nbTestCase{
+         "Gaussian",
+         nil,
+         -1,
+         []base.Tuple{
+            base.NewNumericTuple([]interface{}{10.0, 10.5}, "A"),
+            base.NewNumericTuple([]interface{}{9.0, 9.5}, "A"),
+            base.NewNumericTuple([]interface{}{11.0, 11.5}, "A"),
+            base.NewNumericTuple([]interface{}{-10.0, -10.5}, "B"),
+            base.NewNumericTuple([]interface{}{-9.0, -9.5}, "B"),
+            base.NewNumericTuple([]interface{}{-11.0, -11.5}, "B"),
+         },
+         []base.Tuple{
+            base.NewNumericTuple([]interface{}{8.0, 8.0}, nil),
+            base.NewNumericTuple([]interface{}{-8.0, -8.0}, nil),
+         },
+         []base.Feature{
+            base.String("A"),
+            base.String("B"),
+         },
+         []float64{
+            0.9,
+            0.9,
+         },
+      },
+      nbTestCase{
+         "Multinomial",
+         nil,
+         -1,
+         []base.Tuple{
+            base.NewIntTuple([]interface{}{5, 0, 1}, "A"),
+            base.NewIntTuple([]interface{}{4, 1, 0}, "A"),
+            base.NewIntTuple([]interface{}{6, 0, 0}, "A"),
+            base.NewIntTuple([]interface{}{0, 5, 1}, "B"),
+            base.NewIntTuple([]interface{}{1, 4, 0}, "B"),
+            base.NewIntTuple([]interface{}{0, 6, 1}, "B"),
+         },
+         []base.Tuple{
+            base.NewIntTuple([]interface{}{3, 0, 0}, nil),
+            base.NewIntTuple([]interface{}{0, 3, 1}, nil),
+         },
+         []base.Feature{
+            base.String("A"),
+            base.String("B"),
+         },
+         []float64{
+            0.9,
+            0.9,
+         },
+      },
+      nbTestCase{
+         "Categorical",
+         nil,
+         -1,
+         []base.Tuple{
+            base.NewTuple([]interface{}{"red", true}, "A"),
+            base.NewTuple([]interface{}{"red", true}, "A"),
+            base.NewTuple([]interface{}{"red", false}, "A"),
+            base.NewTuple([]interface{}{"blue", false}, "B"),
+            base.NewTuple([]interface{}{"blue", false}, "B"),
+            base.NewTuple([]interface{}{"green", true}, "B"),
+         },
+         []base.Tuple{
+            base.NewTuple([]interface{}{"red", true}, nil),
+            base.NewTuple([]interface{}{"blue", false}, nil),
+            // Unseen value.
+            base.NewTuple([]interface{}{"purple", false}, nil),
+         },
+         []base.Feature{
+            base.String("A"),
+            base.String("B"),
+            base.String("B"),
+         },
+         []float64{
+            0.8,
+            0.8,
+            0.55,
+         },
+      },
+      nbTestCase{
+         "Mixed with Nil",
+         nil,
+         -1,
+         []base.Tuple{
+            base.NewTuple([]interface{}{"red", 10.0, nil}, 1),
+            base.NewTuple([]interface{}{"red", 9.0, nil}, 1),
+            base.NewTuple([]interface{}{nil, 11.0, nil}, 1),
+            base.NewTuple([]interface{}{"blue", -10.0, nil}, 0),
+            base.NewTuple([]interface{}{"blue", nil, nil}, 0),
+            base.NewTuple([]interface{}{"blue", -11.0, nil}, 0),
+         },
+         []base.Tuple{
+            base.NewTuple([]interface{}{"red", 8.0, nil}, nil),
+            base.NewTuple([]interface{}{nil, -8.0, nil}, nil),
+         },
+         []base.Feature{
+            base.Int(1),
+            base.Int(0),
+         },
+         []float64{
+            0.9,
+            0.9,
+         },
+      },
+      nbTestCase{
+         "Reduced",
+         features.NewManualReducer([]int{1, 3}),
+         0,
+         []base.Tuple{
+            base.NewNumericTuple([]interface{}{1,  10, 0,  10, 6}, "A"),
+            base.NewNumericTuple([]interface{}{2,  9,  0,  9,  5}, "A"),
+            base.NewNumericTuple([]interface{}{3,  11, 0,  11, 4}, "A"),
+            base.NewNumericTuple([]interface{}{4, -10, 0, -10, 3}, "B"),
+            base.NewNumericTuple([]interface{}{5, -9,  0, -9,  2}, "B"),
+            base.NewNumericTuple([]interface{}{6, -11, 0, -11, 1}, "B"),
+         },
+         []base.Tuple{
+            base.NewNumericTuple([]interface{}{1,  8, 0,  8, 2}, nil),
+            base.NewNumericTuple([]interface{}{2, -8, 0, -8, 1}, nil),
+         },
+         []base.Feature{
+            base.String("A"),
+            base.String("B"),
+         },
+         []float64{
+            0.9,
+            0.9,
+         },
+      },
+      nbTestCase{
+         // Class A never sees the last feature, so a zero count there must not make the posterior NaN.
+         "Multinomial - No Smoothing",
+         nil,
+         0,
+         []base.Tuple{
+            base.NewIntTuple([]interface{}{5, 0, 0}, "A"),
+            base.NewIntTuple([]interface{}{4, 1, 0}, "A"),
+            base.NewIntTuple([]interface{}{6, 0, 0}, "A"),
+            base.NewIntTuple([]interface{}{0, 5, 1}, "B"),
+            base.NewIntTuple([]interface{}{1, 4, 0}, "B"),
+            base.NewIntTuple([]interface{}{0, 6, 2}, "B"),
+         },
+         []base.Tuple{
+            base.NewIntTuple([]interface{}{0, 3, 0}, nil),
+            base.NewIntTuple([]interface{}{2, 0, 1}, nil),
+            base.NewIntTuple([]interface{}{3, 0, 0}, nil),
+         },
+         []base.Feature{
+            base.String("B"),
+            base.String("B"),
+            base.String("A"),
+         },
+         []float64{
+            0.9,
+            0.99,
+            0.9,
+         },
+      },
+      nbTestCase{
+         // Purple was never seen, so both classes are impossible and neither is preferred.
+         "Categorical - No Smoothing",
+         nil,
+         0,
+         []base.Tuple{
+            base.NewTuple([]interface{}{"red"}, "A"),
+            base.NewTuple([]interface{}{"red"}, "A"),
+            base.NewTuple([]interface{}{"blue"}, "A"),
+            base.NewTuple([]interface{}{"green"}, "B"),
+            base.NewTuple([]interface{}{"green"}, "B"),
+            base.NewTuple([]interface{}{"blue"}, "B"),
+         },
+         []base.Tuple{
+            base.NewTuple([]interface{}{"green"}, nil),
+            base.NewTuple([]interface{}{"purple"}, nil),
+         },
+         []base.Feature{
+            base.String("B"),
+            base.String("A"),
+         },
+         []float64{
+            0.99,
+            0.5,
+         },
+      },
+   };
+
+   for _, testCase := range(testCases) {
+      var nb Classifier = NewNaiveBayes(testCase.Reducer, testCase.Smoothing);
+      nb.Train(testCase.TestData);
+      var actualClasses []base.Feature;
+      var actualConfidences []float64;
+
+      actualClasses, actualConfidences = nb.Classify(testCase.Input);
+
+      if (len(actualClasses) != len(testCase.ExpectedClasses)) {
+         t.Errorf("(%s) -- Length of expected (%d) and actual classes (%d) do not match", testCase.Name, len(testCase.ExpectedClasses), len(actualClasses));
+         continue;
+      }
+
+      if (len(actualConfidences) != len(testCase.MinExpectedConfidences)) {
+         t.Errorf("(%s) -- Length of expected (%d) and actual confidences (%d) do not match", testCase.Name, len(testCase.MinExpectedConfidences), len(actualConfidences));
+         continue;
+      }
+
+      // Go over each value explicitly to make output more readable.
+      for i, _ := range(actualClasses) {
+         if (actualClasses[i] != testCase.ExpectedClasses[i]) {
+            t.Errorf("(%s)[%d] -- Bad classification. Expected classes: %v, Got: %v", testCase.Name, i, testCase.ExpectedClasses[i], actualClasses[i]);
+         }
+
+         if (math.IsNaN(actualConfidences[i]) || actualConfidences[i] < testCase.MinExpectedConfidences[i] || actualConfidences[i] > 1.0) {
+            t.Errorf("(%s)[%d] -- Bad confidence. Expected min confidence: %v, Got: %v", testCase.Name, i, testCase.MinExpectedConfidences[i], actualConfidences[i]);
+         }
+      }
+   }
+}
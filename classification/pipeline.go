@@ -0,0 +1,98 @@
+package classification
+
+// A chain of transformers (eg scaling -> imputation -> selection) that ends in a classifier.
+// Training fits each transformer on the output of the one before it and then trains the classifier,
+// classifying applies the same fitted transformations before handing off to the classifier.
+// A Pipeline is itself a Classifier, so it can be used anywhere one is (even inside another Pipeline).
+
+import (
+   "github.com/eriq-augustine/goml/base"
+   "github.com/eriq-augustine/goml/features"
+)
+
+type Pipeline struct {
+   transformers []features.Transformer
+   classifier Classifier
+   // If nil, it will be inferred at training time.
+   inputSchema features.Schema
+   outputSchema features.Schema
+}
+
+// The transformers are applied in the order given.
+func NewPipeline(transformers []features.Transformer, classifier Classifier) *Pipeline {
+   if (classifier == nil) {
+      panic("A Pipeline needs a classifier.");
+   }
+
+   for _, transformer := range(transformers) {
+      if (transformer == nil) {
+         panic("Pipeline transformers cannot be nil.");
+      }
+   }
+
+   var pipeline Pipeline = Pipeline{
+      transformers: append([]features.Transformer(nil), transformers...),
+      classifier: classifier,
+      inputSchema: nil,
+      outputSchema: nil,
+   };
+
+   return &pipeline;
+}
+
+// Name the input columns (otherwise features.InferSchema() is used when training).
+// Must be called before Train().
+func (this *Pipeline) SetInputSchema(schema features.Schema) {
+   this.inputSchema = append(features.Schema(nil), schema...);
+}
+
+func (this *Pipeline) Train(tuples []base.Tuple) {
+   if (len(tuples) == 0) {
+      panic("Need at least one tuple to train a Pipeline.");
+   }
+
+   var schema features.Schema = this.inputSchema;
+   if (schema == nil) {
+      schema = features.InferSchema(tuples);
+   }
+
+   for _, transformer := range(this.transformers) {
+      transformer.Fit(tuples);
+      tuples = transformer.Transform(tuples);
+      schema = transformer.OutputSchema(schema);
+   }
+
+   this.outputSchema = schema;
+   this.classifier.Train(tuples);
+}
+
+func (this Pipeline) Classify(tuples []base.Tuple) ([]base.Feature, []float64) {
+   return this.classifier.Classify(this.Transform(tuples));
+}
+
+// Apply all the (already fit) transformers, but do not classify.
+func (this Pipeline) Transform(tuples []base.Tuple) []base.Tuple {
+   for _, transformer := range(this.transformers) {
+      tuples = transformer.Transform(tuples);
+   }
+
+   return tuples;
+}
+
+// The columns that are handed to the classifier.
+// Only valid after Train().
+func (this Pipeline) OutputSchema() features.Schema {
+   if (this.outputSchema == nil) {
+      panic("Pipeline must be trained before getting the output schema.");
+   }
+
+   return append(features.Schema(nil), this.outputSchema...);
+}
+
+func (this Pipeline) GetTransformers() []features.Transformer {
+   return append([]features.Transformer(nil), this.transformers...);
+}
+
+func (this Pipeline) GetClassifier() Classifier {
+   return this.classifier;
+}
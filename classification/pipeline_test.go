@@ -0,0 +1,195 @@
+package classification
+
+import (
+   "reflect"
+   "testing"
+
+   "github.com/eriq-augustine/goml/base"
+   "github.com/eriq-augustine/goml/features"
+)
+
+type pipelineTestCase struct {
+   Name string
+   Transformers []features.Transformer
+   ExpectedSchema []string
+   // The expected classes for the input from pipelineData().
+   ExpectedClasses []base.Feature
+}
+
+// Subtracts the training mean of every column.
+// Remembers how many times it was fit.
+type centerTransformer struct {
+   means []float64
+   numFits int
+}
+
+func (this *centerTransformer) Fit(tuples []base.Tuple) {
+   this.numFits++;
+   this.means = make([]float64, tuples[0].DataSize());
+   for _, tuple := range(tuples) {
+      for i, _ := range(this.means) {
+         this.means[i] += tuple.(base.NumericTuple).GetNumericData(i) / float64(len(tuples));
+      }
+   }
+}
+
+func (this centerTransformer) Transform(tuples []base.Tuple) []base.Tuple {
+   var rtn []base.Tuple = make([]base.Tuple, len(tuples));
+   for i, tuple := range(tuples) {
+      var data []float64 = tuple.(base.NumericTuple).ToFloatSlice();
+      for j, _ := range(data) {
+         data[j] -= this.means[j];
+      }
+
+      rtn[i] = base.NewFloatTuple(data, tuple.GetClass());
+   }
+
+   return rtn;
+}
+
+func (this centerTransformer) OutputSchema(input features.Schema) features.Schema {
+   return input;
+}
+
+// The first two features separate the classes, the third is large noise that will confuse a Knn.
+func pipelineData() ([]base.Tuple, []base.Tuple) {
+   var training []base.Tuple = []base.Tuple{
+      base.NewNumericTuple([]interface{}{10.0, 10.0, 0.0}, "A"),
+      base.NewNumericTuple([]interface{}{11.0, 10.0, 10.0}, "A"),
+      base.NewNumericTuple([]interface{}{10.0, 11.0, 20.0}, "A"),
+      base.NewNumericTuple([]interface{}{0.0, 0.0, 200.0}, "B"),
+      base.NewNumericTuple([]interface{}{1.0, 0.0, 210.0}, "B"),
+      base.NewNumericTuple([]interface{}{0.0, 1.0, 220.0}, "B"),
+   };
+
+   var input []base.Tuple = []base.Tuple{
+      base.NewNumericTuple([]interface{}{9.0, 9.0, 215.0}, nil),
+      base.NewNumericTuple([]interface{}{1.0, 1.0, 5.0}, nil),
+   };
+
+   return training, input;
+}
+
+func TestPipelineBase(t *testing.T) {
+   var testCases []pipelineTestCase = []pipelineTestCase{
+      pipelineTestCase{
+         "No Transformers",
+         []features.Transformer{},
+         []string{"x0", "x1", "x2"},
+         []base.Feature{base.String("B"), base.String("A")},
+      },
+      pipelineTestCase{
+         "Select",
+         []features.Transformer{features.NewReducerTransformer(features.NewManualReducer([]int{0, 1}))},
+         []string{"x0", "x1"},
+         []base.Feature{base.String("A"), base.String("B")},
+      },
+      pipelineTestCase{
+         "Center and Select",
+         []features.Transformer{
+            &centerTransformer{},
+            features.NewReducerTransformer(features.NewManualReducer([]int{1, 0})),
+            features.NewReducerTransformer(nil),
+         },
+         []string{"x1", "x0"},
+         []base.Feature{base.String("A"), base.String("B")},
+      },
+      pipelineTestCase{
+         "Project",
+         []features.Transformer{
+            features.NewReducerTransformer(features.NewManualReducer([]int{0, 1})),
+            features.NewReducerTransformer(features.NewPCAReducer(1, 0, false)),
+         },
+         []string{"component0"},
+         []base.Feature{base.String("A"), base.String("B")},
+      },
+   };
+
+   var training, input = pipelineData();
+
+   for _, testCase := range(testCases) {
+      var pipeline *Pipeline = NewPipeline(testCase.Transformers, NewKnn(1, nil, nil));
+      pipeline.Train(training);
+
+      var schema []string = pipeline.OutputSchema().Names();
+      if (!reflect.DeepEqual(schema, testCase.ExpectedSchema)) {
+         t.Errorf("(%s) -- Bad schema. Expected: %v, Got: %v", testCase.Name, testCase.ExpectedSchema, schema);
+      }
+
+      results, _ := pipeline.Classify(input);
+      for i, result := range(results) {
+         if (result != testCase.ExpectedClasses[i]) {
+            t.Errorf("(%s)[%d] -- Misclassification. Expected: %v, Got: %v", testCase.Name, i, testCase.ExpectedClasses[i], result);
+         }
+      }
+
+      for _, transformer := range(pipeline.GetTransformers()) {
+         center, ok := transformer.(*centerTransformer);
+         if (ok && center.numFits != 1) {
+            t.Errorf("(%s) -- Transformer fit %d times, expected once.", testCase.Name, center.numFits);
+         }
+      }
+   }
+}
+
+// Pipelines are classifiers, so they can be nested and keep the user's column names.
+func TestPipelineNested(t *testing.T) {
+   var training, input = pipelineData();
+
+   var inner *Pipeline = NewPipeline(
+         []features.Transformer{features.NewReducerTransformer(features.NewManualReducer([]int{1}))},
+         NewKnn(1, nil, nil));
+   var outer *Pipeline = NewPipeline(
+         []features.Transformer{&centerTransformer{}, features.NewReducerTransformer(features.NewManualReducer([]int{0, 1}))},
+         inner);
+   outer.SetInputSchema(features.Schema{{Name: "height", Numeric: true}, {Name: "width", Numeric: true}, {Name: "noise", Numeric: true}});
+
+   outer.Train(training);
+
+   if (!reflect.DeepEqual(outer.OutputSchema().Names(), []string{"height", "width"})) {
+      t.Errorf("Bad outer schema: %v", outer.OutputSchema().Names());
+   }
+
+   if (!reflect.DeepEqual(inner.OutputSchema().Names(), []string{"x1"})) {
+      t.Errorf("Bad inner schema: %v", inner.OutputSchema().Names());
+   }
+
+   results, _ := outer.Classify(input);
+   var expected []base.Feature = []base.Feature{base.String("A"), base.String("B")};
+   if (!reflect.DeepEqual(results, expected)) {
+      t.Errorf("Misclassification. Expected: %v, Got: %v", expected, results);
+   }
+}
+
+// Categorical data can be classified once it is encoded.
+func TestPipelineOneHot(t *testing.T) {
+   var training []base.Tuple = []base.Tuple{
+      base.NewTuple([]interface{}{"sunny", 30.0}, "beach"),
+      base.NewTuple([]interface{}{"sunny", 28.0}, "beach"),
+      base.NewTuple([]interface{}{"rainy", 15.0}, "museum"),
+      base.NewTuple([]interface{}{"rainy", 27.0}, "museum"),
+      base.NewTuple([]interface{}{"snowy", 0.0}, "skiing"),
+      base.NewTuple([]interface{}{"snowy", -5.0}, "skiing"),
+   };
+
+   var pipeline *Pipeline = NewPipeline(
+         []features.Transformer{features.NewOneHotEncoder(nil, features.ENCODER_UNKNOWN_IGNORE, 0), features.NewScaler(features.SCALER_MIN_MAX)},
+         NewKnn(1, nil, nil));
+   pipeline.Train(training);
+
+   var expectedSchema []string = []string{"x0=rainy", "x0=snowy", "x0=sunny", "x1"};
+   if (!reflect.DeepEqual(pipeline.OutputSchema().Names(), expectedSchema)) {
+      t.Errorf("Bad schema. Expected: %v, Got: %v", expectedSchema, pipeline.OutputSchema().Names());
+   }
+
+   results, _ := pipeline.Classify([]base.Tuple{
+      base.NewTuple([]interface{}{"rainy", 29.0}, nil),
+      base.NewTuple([]interface{}{"snowy", 2.0}, nil),
+      base.NewTuple([]interface{}{"foggy", 31.0}, nil),
+   });
+
+   var expected []base.Feature = []base.Feature{base.String("museum"), base.String("skiing"), base.String("beach")};
+   if (!reflect.DeepEqual(results, expected)) {
+      t.Errorf("Misclassification. Expected: %v, Got: %v", expected, results);
+   }
+}
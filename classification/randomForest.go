@@ -0,0 +1,226 @@
+package classification
+
+// A random forest of CART trees.
+// Each tree is trained on a bootstrap sample of the data and only considers
+// a random subset of the features at each split.
+// Trees are trained concurrently (bounded by base.GetMaxProcs()).
+
+import (
+   "math"
+   "math/rand"
+   "sync"
+
+   "github.com/eriq-augustine/goml/base"
+   "github.com/eriq-augustine/goml/features"
+   "github.com/eriq-augustine/goml/util"
+)
+
+const (
+   RF_DEFAULT_NUM_TREES = 100
+)
+
+type RandomForest struct {
+   reducer features.Reducer
+   numTrees int
+   // Non-positive means sqrt(number of features).
+   maxFeatures int
+   criterion TreeCriterion
+   maxDepth int
+   minSamplesLeaf int
+
+   labels []base.Feature
+   numFeatures int
+   trees []*treeNode
+   oobError float64
+}
+
+// Pass a non-positive value for any numeric param to get the default.
+// By default, trees have no max depth and consider sqrt(number of features) features at each split.
+func NewRandomForest(reducer features.Reducer, numTrees int, maxFeatures int,
+                     criterion TreeCriterion, maxDepth int, minSamplesLeaf int) *RandomForest {
+   if (reducer == nil) {
+      reducer = features.NoReducer{};
+   }
+
+   if (numTrees <= 0) {
+      numTrees = RF_DEFAULT_NUM_TREES;
+   }
+
+   if (minSamplesLeaf <= 0) {
+      minSamplesLeaf = TREE_DEFAULT_MIN_SAMPLES_LEAF;
+   }
+
+   var forest RandomForest = RandomForest{
+      reducer: reducer,
+      numTrees: numTrees,
+      maxFeatures: maxFeatures,
+      criterion: criterion,
+      maxDepth: maxDepth,
+      minSamplesLeaf: minSamplesLeaf,
+      labels: nil,
+      numFeatures: 0,
+      trees: nil,
+      oobError: math.NaN(),
+   };
+
+   return &forest;
+}
+
+func (this *RandomForest) Train(tuples []base.Tuple) {
+   if (tuples == nil || len(tuples) == 0) {
+      panic("Must provide tuples for training.")
+   }
+
+   this.reducer.Init(tuples);
+   tuples = this.reducer.Reduce(tuples);
+
+   var dataLabels []int;
+   this.labels, dataLabels = mapLabels(tuples);
+   this.numFeatures = tuples[0].DataSize();
+
+   var maxFeatures int = this.maxFeatures;
+   if (maxFeatures <= 0) {
+      maxFeatures = util.MaxInt(1, int(math.Sqrt(float64(this.numFeatures))));
+   }
+
+   var numericFeatures []bool = inferNumericFeatures(tuples);
+
+   // Each tree gets its own random source (chosen up front so training is reproducible with base.Seed()).
+   var randoms []*rand.Rand = make([]*rand.Rand, this.numTrees);
+   for i, _ := range(randoms) {
+      randoms[i] = base.NewRandom();
+   }
+
+   // The bootstrap counts for each tree: [tree][tuple].
+   var bootstrapWeights [][]float64 = make([][]float64, this.numTrees);
+   this.trees = make([]*treeNode, this.numTrees);
+
+   var treeIndexes chan int = make(chan int, this.numTrees);
+   for i := 0; i < this.numTrees; i++ {
+      treeIndexes <- i;
+   }
+   close(treeIndexes);
+
+   var numWorkers int = util.MinInt(util.MaxInt(1, base.GetMaxProcs()), this.numTrees);
+   var waitGroup sync.WaitGroup;
+
+   for worker := 0; worker < numWorkers; worker++ {
+      waitGroup.Add(1);
+      go func() {
+         defer waitGroup.Done();
+
+         for treeIndex := range(treeIndexes) {
+            bootstrapWeights[treeIndex] = bootstrapSample(len(tuples), randoms[treeIndex]);
+
+            var builder treeBuilder = treeBuilder{
+               data: tuples,
+               labels: dataLabels,
+               weights: bootstrapWeights[treeIndex],
+               numLabels: len(this.labels),
+               numericFeatures: numericFeatures,
+               criterion: this.criterion,
+               maxDepth: this.maxDepth,
+               minSamplesLeaf: this.minSamplesLeaf,
+               maxFeatures: maxFeatures,
+               random: randoms[treeIndex],
+            };
+
+            this.trees[treeIndex] = builder.build();
+         }
+      }();
+   }
+
+   waitGroup.Wait();
+
+   this.oobError = this.calcOOBError(tuples, dataLabels, bootstrapWeights);
+}
+
+// The confidence is the fraction of trees that voted for the chosen class.
+func (this RandomForest) Classify(tuples []base.Tuple) ([]base.Feature, []float64) {
+   tuples = this.reducer.Reduce(tuples);
+
+   var results []base.Feature = make([]base.Feature, len(tuples));
+   var confidences []float64 = make([]float64, len(tuples));
+
+   for i, tuple := range(tuples) {
+      var votes []float64 = make([]float64, len(this.labels));
+      for _, tree := range(this.trees) {
+         votes[tree.findLeaf(tuple).prediction]++;
+      }
+
+      bestIndex, bestVotes := util.Max(votes);
+      results[i] = this.labels[bestIndex];
+      confidences[i] = bestVotes / float64(len(this.trees));
+   }
+
+   return results, confidences;
+}
+
+// The fraction of training tuples misclassified by the trees that did not see them (out-of-bag).
+// Tuples that were in every bootstrap sample are not counted.
+// NaN if the forest has not been trained or no tuples were ever out-of-bag.
+func (this RandomForest) OOBError() float64 {
+   return this.oobError;
+}
+
+// The impurity-based importance of each feature averaged over all trees, normalized to sum to 1.
+// Indexes are into the reduced features (see features.Reducer.GetFeatures()).
+func (this RandomForest) FeatureImportances() []float64 {
+   var importances []float64 = make([]float64, this.numFeatures);
+
+   for _, tree := range(this.trees) {
+      var treeImportances []float64 = make([]float64, this.numFeatures);
+      tree.addImportances(treeImportances);
+      normalizeImportances(treeImportances);
+
+      for i, importance := range(treeImportances) {
+         importances[i] += importance;
+      }
+   }
+
+   return normalizeImportances(importances);
+}
+
+func (this RandomForest) calcOOBError(tuples []base.Tuple, dataLabels []int, bootstrapWeights [][]float64) float64 {
+   var numCounted int = 0;
+   var numWrong int = 0;
+
+   for tupleIndex, tuple := range(tuples) {
+      var votes []float64 = make([]float64, len(this.labels));
+      var numVotes int = 0;
+
+      for treeIndex, tree := range(this.trees) {
+         if (bootstrapWeights[treeIndex][tupleIndex] > 0) {
+            continue;
+         }
+
+         votes[tree.findLeaf(tuple).prediction]++;
+         numVotes++;
+      }
+
+      if (numVotes == 0) {
+         continue;
+      }
+
+      numCounted++;
+      bestIndex, _ := util.Max(votes);
+      if (bestIndex != dataLabels[tupleIndex]) {
+         numWrong++;
+      }
+   }
+
+   if (numCounted == 0) {
+      return math.NaN();
+   }
+
+   return float64(numWrong) / float64(numCounted);
+}
+
+// Draw |size| samples with replacement and return how many times each index was drawn.
+func bootstrapSample(size int, random *rand.Rand) []float64 {
+   var counts []float64 = make([]float64, size);
+   for i := 0; i < size; i++ {
+      counts[random.Intn(size)]++;
+   }
+   return counts;
+}
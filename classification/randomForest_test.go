@@ -0,0 +1,173 @@
+package classification
+
+import (
+   "math/rand"
+   "testing"
+
+   "github.com/eriq-augustine/goml/base"
+   "github.com/eriq-augustine/goml/features"
+)
+
+type rfTestCase struct {
+   Name string
+   Reducer features.Reducer
+   NumTrees int
+   TestData []base.Tuple
+   Input []base.Tuple
+   ExpectedClasses []base.Feature
+   MinExpectedConfidences []float64
+}
+
+func TestRandomForestBase(t *testing.T) {
+   base.Seed(4);
+
+   var testCases []rfTestCase = []rfTestCase{
+      rfTestCase{
+         "Base",
+         features.NoReducer{},
+         25,
+         []base.Tuple{
+            base.NewIntTuple([]interface{}{10, 10}, "A"),
+            base.NewIntTuple([]interface{}{9, 9}, "A"),
+            base.NewIntTuple([]interface{}{11, 11}, "A"),
+            base.NewIntTuple([]interface{}{-10, -10}, "B"),
+            base.NewIntTuple([]interface{}{-9, -9}, "B"),
+            base.NewIntTuple([]interface{}{-11, -11}, "B"),
+         },
+         []base.Tuple{
+            base.NewIntTuple([]interface{}{8, 8}, nil),
+            base.NewIntTuple([]interface{}{-8, -8}, nil),
+         },
+         []base.Feature{
+            base.String("A"),
+            base.String("B"),
+         },
+         []float64{
+            0.75,
+            0.75,
+         },
+      },
+      rfTestCase{
+         "Reduced - Defaults",
+         features.NewManualReducer([]int{1, 3}),
+         0,
+         []base.Tuple{
+            base.NewIntTuple([]interface{}{1,  10, 0,  10, 6}, "A"),
+            base.NewIntTuple([]interface{}{2,  9,  0,  9,  5}, "A"),
+            base.NewIntTuple([]interface{}{3,  11, 0,  11, 4}, "A"),
+            base.NewIntTuple([]interface{}{4, -10, 0, -10, 3}, "B"),
+            base.NewIntTuple([]interface{}{5, -9,  0, -9,  2}, "B"),
+            base.NewIntTuple([]interface{}{6, -11, 0, -11, 1}, "B"),
+         },
+         []base.Tuple{
+            base.NewIntTuple([]interface{}{1,  8, 0,  8, 2}, nil),
+            base.NewIntTuple([]interface{}{2, -8, 0, -8, 1}, nil),
+         },
+         []base.Feature{
+            base.String("A"),
+            base.String("B"),
+         },
+         []float64{
+            0.75,
+            0.75,
+         },
+      },
+      rfTestCase{
+         "Mixed Features",
+         nil,
+         25,
+         []base.Tuple{
+            base.NewTuple([]interface{}{"red", 1.0}, "A"),
+            base.NewTuple([]interface{}{"red", 2.0}, "A"),
+            base.NewTuple([]interface{}{"red", 3.0}, "A"),
+            base.NewTuple([]interface{}{"blue", 10.0}, "B"),
+            base.NewTuple([]interface{}{"blue", 11.0}, "B"),
+            base.NewTuple([]interface{}{"blue", 12.0}, "B"),
+         },
+         []base.Tuple{
+            base.NewTuple([]interface{}{"red", 0.0}, nil),
+            base.NewTuple([]interface{}{"blue", 15.0}, nil),
+         },
+         []base.Feature{
+            base.String("A"),
+            base.String("B"),
+         },
+         []float64{
+            0.75,
+            0.75,
+         },
+      },
+   };
+
+   for _, testCase := range(testCases) {
+      var forest Classifier = NewRandomForest(testCase.Reducer, testCase.NumTrees, 0, TREE_GINI, 0, 0);
+      forest.Train(testCase.TestData);
+      var actualClasses []base.Feature;
+      var actualConfidences []float64;
+
+      actualClasses, actualConfidences = forest.Classify(testCase.Input);
+
+      if (len(actualClasses) != len(testCase.ExpectedClasses)) {
+         t.Errorf("(%s) -- Length of expected (%d) and actual classes (%d) do not match", testCase.Name, len(testCase.ExpectedClasses), len(actualClasses));
+         continue;
+      }
+
+      if (len(actualConfidences) != len(testCase.MinExpectedConfidences)) {
+         t.Errorf("(%s) -- Length of expected (%d) and actual confidences (%d) do not match", testCase.Name, len(testCase.MinExpectedConfidences), len(actualConfidences));
+         continue;
+      }
+
+      // Go over each value explicitly to make output more readable.
+      for i, _ := range(actualClasses) {
+         if (actualClasses[i] != testCase.ExpectedClasses[i]) {
+            t.Errorf("(%s)[%d] -- Bad classification. Expected classes: %v, Got: %v", testCase.Name, i, testCase.ExpectedClasses[i], actualClasses[i]);
+         }
+
+         if (actualConfidences[i] < testCase.MinExpectedConfidences[i] || actualConfidences[i] > 1.0) {
+            t.Errorf("(%s)[%d] -- Bad confidence. Expected min confidence: %v, Got: %v", testCase.Name, i, testCase.MinExpectedConfidences[i], actualConfidences[i]);
+         }
+      }
+   }
+}
+
+// Only the first feature is informative, the rest are noise.
+func TestRandomForestOOBAndImportances(t *testing.T) {
+   base.Seed(4);
+   var random *rand.Rand = rand.New(rand.NewSource(4));
+
+   var data []base.Tuple = make([]base.Tuple, 300);
+   for i, _ := range(data) {
+      var signal float64 = random.Float64() * 2.0 - 1.0;
+      var class int = 0;
+      if (signal > 0) {
+         class = 1;
+      }
+
+      data[i] = base.NewFloatTuple([]float64{signal, random.Float64(), random.Float64(), random.Float64()}, class);
+   }
+
+   var forest *RandomForest = NewRandomForest(nil, 50, 2, TREE_GINI, 0, 0);
+   forest.Train(data);
+
+   if (forest.OOBError() > 0.1) {
+      t.Errorf("OOB error too high. Expected at most: %v, Got: %v", 0.1, forest.OOBError());
+   }
+
+   var importances []float64 = forest.FeatureImportances();
+   if (len(importances) != 4) {
+      t.Fatalf("Bad number of importances. Expected: %d, Got: %d", 4, len(importances));
+   }
+
+   var sum float64 = 0;
+   for i, importance := range(importances) {
+      sum += importance;
+
+      if (i != 0 && importance >= importances[0]) {
+         t.Errorf("Noise feature (%d) is more important than the signal. Signal: %v, Noise: %v", i, importances[0], importance);
+      }
+   }
+
+   if (sum < 0.999 || sum > 1.001) {
+      t.Errorf("Importances do not sum to 1. Got: %v", sum);
+   }
+}
@@ -0,0 +1,220 @@
+package classification
+
+// A CART tree over numeric targets (split by reduction in squared error).
+// Splits follow the same rules as DecisionTree (see splitGoesLeft()).
+// This is the weak learner used for boosting.
+
+import (
+   "sort"
+
+   "github.com/eriq-augustine/goml/base"
+)
+
+type regressionNode struct {
+   value float64
+
+   // Only set for internal nodes.
+   left *regressionNode
+   right *regressionNode
+   featureIndex int
+   numericSplit bool
+   threshold float64
+   category base.Feature
+}
+
+func (this regressionNode) isLeaf() bool {
+   return this.left == nil;
+}
+
+func (this *regressionNode) predict(tuple base.Tuple) float64 {
+   var node *regressionNode = this;
+   for (!node.isLeaf()) {
+      if (splitGoesLeft(tuple.GetData(node.featureIndex), node.numericSplit, node.threshold, node.category)) {
+         node = node.left;
+      } else {
+         node = node.right;
+      }
+   }
+
+   return node.value;
+}
+
+type regressionTreeBuilder struct {
+   data []base.Tuple
+   targets []float64
+   numericFeatures []bool
+   maxDepth int
+   minSamplesLeaf int
+   // Compute the value of a leaf from the indexes of the samples that reached it.
+   // If nil, the mean target is used.
+   leafValue func(targets []float64, indexes []int) float64
+}
+
+func (this regressionTreeBuilder) build(indexes []int) *regressionNode {
+   return this.buildNode(indexes, 0);
+}
+
+func (this regressionTreeBuilder) buildNode(indexes []int, depth int) *regressionNode {
+   var node *regressionNode = &regressionNode{};
+
+   var split treeSplit = treeSplit{featureIndex: -1};
+   if ((this.maxDepth <= 0 || depth < this.maxDepth) && len(indexes) >= 2 * this.minSamplesLeaf) {
+      split = this.findBestSplit(indexes);
+   }
+
+   if (split.featureIndex == -1) {
+      node.value = this.calcLeafValue(indexes);
+      return node;
+   }
+
+   node.featureIndex = split.featureIndex;
+   node.numericSplit = split.numeric;
+   node.threshold = split.threshold;
+   node.category = split.category;
+
+   var leftIndexes []int = make([]int, 0);
+   var rightIndexes []int = make([]int, 0);
+   for _, index := range(indexes) {
+      if (splitGoesLeft(this.data[index].GetData(node.featureIndex), node.numericSplit, node.threshold, node.category)) {
+         leftIndexes = append(leftIndexes, index);
+      } else {
+         rightIndexes = append(rightIndexes, index);
+      }
+   }
+
+   node.left = this.buildNode(leftIndexes, depth + 1);
+   node.right = this.buildNode(rightIndexes, depth + 1);
+
+   return node;
+}
+
+func (this regressionTreeBuilder) calcLeafValue(indexes []int) float64 {
+   if (this.leafValue != nil) {
+      return this.leafValue(this.targets, indexes);
+   }
+
+   if (len(indexes) == 0) {
+      return 0;
+   }
+
+   var sum float64 = 0;
+   for _, index := range(indexes) {
+      sum += this.targets[index];
+   }
+   return sum / float64(len(indexes));
+}
+
+// The gain of a split is the reduction in the sum of squared errors.
+// Since SSE = sum(y^2) - sum(y)^2 / n, we only need to maximize sum(y_left)^2 / n_left + sum(y_right)^2 / n_right.
+func (this regressionTreeBuilder) findBestSplit(indexes []int) treeSplit {
+   var totalSum float64 = 0;
+   for _, index := range(indexes) {
+      totalSum += this.targets[index];
+   }
+   var parentScore float64 = totalSum * totalSum / float64(len(indexes));
+
+   var bestSplit treeSplit = treeSplit{featureIndex: -1, gain: TREE_MIN_IMPURITY_DECREASE};
+
+   for featureIndex, numeric := range(this.numericFeatures) {
+      var split treeSplit;
+      if (numeric) {
+         split = this.findBestNumericSplit(indexes, featureIndex, totalSum, parentScore);
+      } else {
+         split = this.findBestCategoricalSplit(indexes, featureIndex, totalSum, parentScore);
+      }
+
+      if (split.featureIndex != -1 && split.gain > bestSplit.gain) {
+         bestSplit = split;
+      }
+   }
+
+   return bestSplit;
+}
+
+func (this regressionTreeBuilder) findBestNumericSplit(indexes []int, featureIndex int, totalSum float64, parentScore float64) treeSplit {
+   var bestSplit treeSplit = treeSplit{featureIndex: -1};
+
+   var records []ValueRecord = make([]ValueRecord, 0, len(indexes));
+   for _, index := range(indexes) {
+      var feature base.Feature = this.data[index].GetData(featureIndex);
+      if (!isNilFeature(feature)) {
+         records = append(records, ValueRecord{numericFeatureValue(feature), index});
+      }
+   }
+   sort.Sort(ByValue(records));
+
+   var leftSum float64 = 0;
+   for i := 0; i < len(records) - 1; i++ {
+      leftSum += this.targets[records[i].Index];
+
+      if (records[i].Value == records[i + 1].Value) {
+         continue;
+      }
+
+      var leftCount int = i + 1;
+      var rightCount int = len(indexes) - leftCount;
+      if (leftCount < this.minSamplesLeaf || rightCount < this.minSamplesLeaf) {
+         continue;
+      }
+
+      var gain float64 = splitScore(leftSum, leftCount, totalSum - leftSum, rightCount) - parentScore;
+      if (bestSplit.featureIndex == -1 || gain > bestSplit.gain) {
+         bestSplit = treeSplit{
+            featureIndex: featureIndex,
+            numeric: true,
+            threshold: (records[i].Value + records[i + 1].Value) / 2.0,
+            gain: gain,
+         };
+      }
+   }
+
+   return bestSplit;
+}
+
+func (this regressionTreeBuilder) findBestCategoricalSplit(indexes []int, featureIndex int, totalSum float64, parentScore float64) treeSplit {
+   var bestSplit treeSplit = treeSplit{featureIndex: -1};
+
+   var sums map[base.Feature]float64 = make(map[base.Feature]float64);
+   var counts map[base.Feature]int = make(map[base.Feature]int);
+   // Keep the categories in the order seen so ties are broken consistently.
+   var categories []base.Feature = make([]base.Feature, 0);
+
+   for _, index := range(indexes) {
+      var feature base.Feature = this.data[index].GetData(featureIndex);
+      if (isNilFeature(feature)) {
+         continue;
+      }
+
+      _, ok := counts[feature];
+      if (!ok) {
+         categories = append(categories, feature);
+      }
+
+      sums[feature] += this.targets[index];
+      counts[feature]++;
+   }
+
+   for _, category := range(categories) {
+      var leftCount int = counts[category];
+      var rightCount int = len(indexes) - leftCount;
+      if (leftCount < this.minSamplesLeaf || rightCount < this.minSamplesLeaf) {
+         continue;
+      }
+
+      var gain float64 = splitScore(sums[category], leftCount, totalSum - sums[category], rightCount) - parentScore;
+      if (bestSplit.featureIndex == -1 || gain > bestSplit.gain) {
+         bestSplit = treeSplit{
+            featureIndex: featureIndex,
+            numeric: false,
+            category: category,
+            gain: gain,
+         };
+      }
+   }
+
+   return bestSplit;
+}
+
+func splitScore(leftSum float64, leftCount int, rightSum float64, rightCount int) float64 {
+   return (leftSum * leftSum / float64(leftCount)) + (rightSum * rightSum / float64(rightCount));
+}
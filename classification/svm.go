@@ -1,84 +1,147 @@
 package classification
 
-// For now, we will use a port of libsvm.
-// Eventually, we would like to use our own implementation optimized for go.
-// Currently, this only supports Numeric tuples with binary classes.
+// A pure go SVM trained in memory with SMO.
+// The working set selection and update rules follow libsvm
+// (maximal violating pair, Fan, Chen, and Lin 2005).
+// Multiclass problems are broken up into binary problems with either one-vs-rest or one-vs-one.
 
 import (
-   "bytes"
    "fmt"
-   "os"
-
-   "github.com/ewalker544/libsvm-go"
+   "math"
 
    "github.com/eriq-augustine/goml/base"
    "github.com/eriq-augustine/goml/features"
    "github.com/eriq-augustine/goml/util"
 )
 
+const (
+   SVM_DEFAULT_C = 1.0
+   SVM_DEFAULT_TOLERENCE = 1e-3
+   SVM_DEFAULT_MAX_ITERATIONS = 1e5
+   SVM_DEFAULT_POLYNOMIAL_DEGREE = 3
+   SVM_KERNEL_CACHE_ROWS = 1000
+   // Used in place of a non-positive curvature (non-PSD kernels).
+   SVM_TAU = 1e-12
+)
+
+type SvmMulticlass int
+
+const (
+   SVM_ONE_VS_REST SvmMulticlass = iota
+   SVM_ONE_VS_ONE
+)
+
 type Svm struct {
    reducer features.Reducer
-   params *libSvm.Parameter
-   model *libSvm.Model
-   // Keep track of what kind of classes we are using so we can cast predictions accordingly.
-   classFeatureType base.Feature
+   kernel Kernel
+   c float64
+   tolerence float64
+   maxIterations int
+   multiclass SvmMulticlass
+
+   labels []base.Feature
+   // Two labels: a single machine where labels[0] is the positive class.
+   // One-vs-rest: one machine per label where that label is the positive class.
+   // One-vs-one: one machine per pair of labels (i < j) where labels[i] is the positive class.
+   machines []*binarySvm
 }
 
+// Settings for an Svm beyond the reducer.
+// The zero value is the RBF kernel (with the default gamma), SVM_DEFAULT_C, and SVM_ONE_VS_REST.
+type SvmOptions struct {
+   // Nil means the RBF kernel with the default gamma.
+   Kernel Kernel
+   // Non-positive means SVM_DEFAULT_C.
+   C float64
+   Multiclass SvmMulticlass
+}
+
+// The RBF kernel with SVM_DEFAULT_C and SVM_ONE_VS_REST, see NewSvmWithOptions() for the other settings.
 func NewSvm(reducer features.Reducer) *Svm {
+   return NewSvmWithOptions(reducer, SvmOptions{});
+}
+
+func NewSvmWithOptions(reducer features.Reducer, options SvmOptions) *Svm {
    if (reducer == nil) {
       reducer = features.NoReducer{};
    }
 
+   var kernel Kernel = options.Kernel;
+   if (kernel == nil) {
+      kernel = NewRBFKernel(0);
+   }
+
+   var c float64 = options.C;
+   if (c <= 0) {
+      c = SVM_DEFAULT_C;
+   }
+
+   if (options.Multiclass < SVM_ONE_VS_REST || options.Multiclass > SVM_ONE_VS_ONE) {
+      panic(fmt.Sprintf("Unknown SVM multiclass strategy: %d", options.Multiclass));
+   }
+
    var svm Svm = Svm{
       reducer: reducer,
-      params: libSvm.NewParameter(),
-      model: nil,
-      classFeatureType: nil,
+      kernel: kernel,
+      c: c,
+      tolerence: SVM_DEFAULT_TOLERENCE,
+      maxIterations: SVM_DEFAULT_MAX_ITERATIONS,
+      multiclass: options.Multiclass,
+      labels: nil,
+      machines: nil,
    };
 
    return &svm;
 }
 
-func (this *Svm) Train(rawData []base.Tuple) {
-   this.reducer.Init(rawData);
-   rawData = this.reducer.Reduce(rawData);
+func (this *Svm) Train(tuples []base.Tuple) {
+   if (tuples == nil || len(tuples) == 0) {
+      panic("Must provide tuples for training.")
+   }
 
-   var data []base.NumericTuple = make([]base.NumericTuple, len(rawData));
-   for i, tuple := range(rawData) {
+   this.reducer.Init(tuples);
+   tuples = this.reducer.Reduce(tuples);
+
+   var numFeatures int = -1;
+   var numericData [][]float64 = make([][]float64, len(tuples));
+   var dataLabels []int = make([]int, len(tuples));
+
+   // Note all the labels we have seen and assign them an arbitrary identifier (index into this.labels).
+   this.labels = make([]base.Feature, 0);
+   var labelMap map[base.Feature]int = make(map[base.Feature]int);
+
+   for i, tuple := range(tuples) {
       numericTuple, ok := tuple.(base.NumericTuple);
       if (!ok) {
-         panic("SVM only supports taining on NumericTuple");
+         panic(fmt.Sprintf("SVM only supports training on NumericTuple. Found type: %T", tuple));
       }
 
-      if (!numericTuple.GetClass().IsNumeric()) {
-         panic("SVM only supports training on numeric classes");
+      _, ok = labelMap[numericTuple.GetClass()];
+      if (!ok) {
+         labelMap[numericTuple.GetClass()] = len(this.labels);
+         this.labels = append(this.labels, numericTuple.GetClass());
       }
 
-      data[i] = numericTuple;
+      numericData[i] = numericTuple.ToFloatSlice();
+      dataLabels[i] = labelMap[numericTuple.GetClass()];
 
-      if (this.classFeatureType == nil) {
-         this.classFeatureType = numericTuple.GetClass();
+      // Ensure all vectors are the same size.
+      if (numFeatures == -1) {
+         numFeatures = numericTuple.DataSize();
+      } else if (numFeatures != numericTuple.DataSize()) {
+         panic(fmt.Sprintf("Inconsistent number of features. Tuple[0]: %d, Tuple[%d]: %d",
+               numFeatures, i, numericTuple.DataSize()));
       }
    }
 
-   this.model = libSvm.NewModel(this.params);
-
-   // TODO(eriq): Training data not from file?
-   var trainingFile string = writeTrainingFile(data);
-
-   problem, err := libSvm.NewProblem(writeTrainingFile(data), this.params);
-   if (err != nil) {
-      panic(fmt.Sprintf("Failed to generate libsvm problem: %s", err));
-   }
-
-   this.model.Train(problem);
-   os.Remove(trainingFile);
+   this.train(numericData, dataLabels);
 }
 
 func (this Svm) Classify(tuples []base.Tuple) ([]base.Feature, []float64) {
    tuples = this.reducer.Reduce(tuples);
 
    var results []base.Feature = make([]base.Feature, len(tuples));
+   var confidences []float64 = make([]float64, len(tuples));
 
    for i, tuple := range(tuples) {
       numericTuple, ok := tuple.(base.NumericTuple);
@@ -86,69 +149,364 @@ func (this Svm) Classify(tuples []base.Tuple) ([]base.Feature, []float64) {
          panic("SVM only supports classifying NumericTuple");
       }
 
-      results[i] = this.classifySingle(numericTuple);
+      var labelIndex int;
+      labelIndex, confidences[i] = this.classifySingle(numericTuple.ToFloatSlice());
+      results[i] = this.labels[labelIndex];
+   }
+
+   return results, confidences;
+}
+
+func (this *Svm) train(data [][]float64, dataLabels []int) {
+   this.machines = make([]*binarySvm, 0);
+
+   if (len(this.labels) == 1) {
+      return;
+   }
+
+   if (len(this.labels) == 2) {
+      this.machines = append(this.machines, this.trainMachine(data, dataLabels, 0, -1));
+      return;
+   }
+
+   if (this.multiclass == SVM_ONE_VS_ONE) {
+      for positive := 0; positive < len(this.labels); positive++ {
+         for negative := positive + 1; negative < len(this.labels); negative++ {
+            this.machines = append(this.machines, this.trainMachine(data, dataLabels, positive, negative));
+         }
+      }
+   } else {
+      for positive := 0; positive < len(this.labels); positive++ {
+         this.machines = append(this.machines, this.trainMachine(data, dataLabels, positive, -1));
+      }
+   }
+}
+
+// Train a single binary machine where |positive| is the positive label.
+// If |negative| is -1, then all other labels are negative.
+// Otherwise, only points with the |negative| label are used as negatives (and all other labels are ignored).
+func (this Svm) trainMachine(data [][]float64, dataLabels []int, positive int, negative int) *binarySvm {
+   var points [][]float64 = make([][]float64, 0, len(data));
+   var signs []float64 = make([]float64, 0, len(data));
+
+   for i, point := range(data) {
+      if (dataLabels[i] == positive) {
+         points = append(points, point);
+         signs = append(signs, 1);
+      } else if (negative == -1 || dataLabels[i] == negative) {
+         points = append(points, point);
+         signs = append(signs, -1);
+      }
+   }
+
+   return trainBinarySvm(this.kernel, points, signs, this.c, this.tolerence, this.maxIterations);
+}
+
+// Returns the index of the chosen label and the margin for that choice.
+func (this Svm) classifySingle(point []float64) (int, float64) {
+   if (len(this.labels) == 1) {
+      return 0, 0;
+   }
+
+   if (len(this.labels) == 2) {
+      var margin float64 = this.machines[0].decision(this.kernel, point);
+      if (margin >= 0) {
+         return 0, margin;
+      }
+      return 1, -1.0 * margin;
+   }
+
+   if (this.multiclass == SVM_ONE_VS_ONE) {
+      return this.classifyOneVsOne(point);
    }
 
-   return results, nil;
+   var margins []float64 = make([]float64, len(this.machines));
+   for i, machine := range(this.machines) {
+      margins[i] = machine.decision(this.kernel, point);
+   }
+
+   return util.Max(margins);
 }
 
-func (this Svm) classifySingle(numericTuple base.NumericTuple) base.Feature {
-   var svmTuple map[int]float64 = tupleToSVMTuple(numericTuple);
-   return this.castClassFeature(this.model.Predict(svmTuple));
+// Each pairwise machine votes.
+// The label with the most votes wins (ties are broken by total margin, and then by label index).
+// The confidence is the mean margin in favor of the winner over all the winner's machines.
+func (this Svm) classifyOneVsOne(point []float64) (int, float64) {
+   var votes []int = make([]int, len(this.labels));
+   var margins []float64 = make([]float64, len(this.labels));
+
+   var machineIndex int = 0;
+   for positive := 0; positive < len(this.labels); positive++ {
+      for negative := positive + 1; negative < len(this.labels); negative++ {
+         var margin float64 = this.machines[machineIndex].decision(this.kernel, point);
+         machineIndex++;
+
+         if (margin >= 0) {
+            votes[positive]++;
+         } else {
+            votes[negative]++;
+         }
+
+         margins[positive] += margin;
+         margins[negative] -= margin;
+      }
+   }
+
+   var best int = 0;
+   for i := 1; i < len(this.labels); i++ {
+      if (votes[i] > votes[best] || (votes[i] == votes[best] && margins[i] > margins[best])) {
+         best = i;
+      }
+   }
+
+   return best, margins[best] / float64(len(this.labels) - 1);
+}
+
+type binarySvm struct {
+   supportVectors [][]float64
+   // alpha[i] * y[i] for each support vector.
+   coefficients []float64
+   rho float64
 }
 
-func (this Svm) castClassFeature(val float64) base.Feature {
-   switch featureType := this.classFeatureType.(type) {
-   case base.IntFeature:
-      return base.Int(int(val));
-   case base.FloatFeature:
-      return base.Float(val);
-   case base.BoolFeature:
-      return base.Bool(util.FloatToBool(val));
-   default:
-      panic(fmt.Sprintf("SVM only takes numeric class features, found: %T", featureType));
+// Positive values indicate the positive class.
+func (this binarySvm) decision(kernel Kernel, point []float64) float64 {
+   var sum float64 = 0;
+   for i, supportVector := range(this.supportVectors) {
+      sum += this.coefficients[i] * kernel.Evaluate(supportVector, point);
    }
+
+   return sum - this.rho;
 }
 
-func tupleToSVMTuple (numericTuple base.NumericTuple) map[int]float64 {
-   var svmTuple map[int]float64 = make(map[int]float64);
+// Solve the dual:
+//    min 0.5 * a^T Q a - e^T a
+//    s.t. y^T a = 0, 0 <= a[i] <= C
+// Where Q[i][j] = y[i] * y[j] * K(x[i], x[j]).
+// |labels| must be +/- 1.
+func trainBinarySvm(kernel Kernel, data [][]float64, labels []float64, c float64, tolerence float64, maxIterations int) *binarySvm {
+   var alphas []float64 = make([]float64, len(data));
+   // The gradient of the objective: Q a - e.
+   var gradients []float64 = make([]float64, len(data));
+   var diagonal []float64 = make([]float64, len(data));
+
+   for i, _ := range(data) {
+      gradients[i] = -1;
+      diagonal[i] = kernel.Evaluate(data[i], data[i]);
+   }
+
+   var cache *kernelCache = newKernelCache(kernel, data, SVM_KERNEL_CACHE_ROWS);
+
+   for iteration := 0; iteration < maxIterations; iteration++ {
+      i, j := selectWorkingSet(alphas, gradients, labels, c, tolerence);
+      if (i == -1) {
+         break;
+      }
+
+      var rowI []float64 = cache.row(i);
+      var rowJ []float64 = cache.row(j);
+      var qij float64 = labels[i] * labels[j] * rowI[j];
+
+      var oldAlphaI float64 = alphas[i];
+      var oldAlphaJ float64 = alphas[j];
+
+      if (labels[i] != labels[j]) {
+         var curvature float64 = diagonal[i] + diagonal[j] + 2.0 * qij;
+         if (curvature <= 0) {
+            curvature = SVM_TAU;
+         }
+
+         var delta float64 = (-1.0 * gradients[i] - gradients[j]) / curvature;
+         var diff float64 = alphas[i] - alphas[j];
+         alphas[i] += delta;
+         alphas[j] += delta;
+
+         if (diff > 0) {
+            if (alphas[j] < 0) {
+               alphas[j] = 0;
+               alphas[i] = diff;
+            }
+         } else {
+            if (alphas[i] < 0) {
+               alphas[i] = 0;
+               alphas[j] = -1.0 * diff;
+            }
+         }
+
+         if (diff > 0) {
+            if (alphas[i] > c) {
+               alphas[i] = c;
+               alphas[j] = c - diff;
+            }
+         } else {
+            if (alphas[j] > c) {
+               alphas[j] = c;
+               alphas[i] = c + diff;
+            }
+         }
+      } else {
+         var curvature float64 = diagonal[i] + diagonal[j] - 2.0 * qij;
+         if (curvature <= 0) {
+            curvature = SVM_TAU;
+         }
+
+         var delta float64 = (gradients[i] - gradients[j]) / curvature;
+         var sum float64 = alphas[i] + alphas[j];
+         alphas[i] -= delta;
+         alphas[j] += delta;
+
+         if (sum > c) {
+            if (alphas[i] > c) {
+               alphas[i] = c;
+               alphas[j] = sum - c;
+            }
+         } else {
+            if (alphas[j] < 0) {
+               alphas[j] = 0;
+               alphas[i] = sum;
+            }
+         }
+
+         if (sum > c) {
+            if (alphas[j] > c) {
+               alphas[j] = c;
+               alphas[i] = sum - c;
+            }
+         } else {
+            if (alphas[i] < 0) {
+               alphas[i] = 0;
+               alphas[j] = sum;
+            }
+         }
+      }
 
-   for i := 0; i < numericTuple.DataSize(); i++ {
-      // libsvm 1-indexes features.
-      svmTuple[i + 1] = numericTuple.GetNumericData(i);
+      var deltaAlphaI float64 = alphas[i] - oldAlphaI;
+      var deltaAlphaJ float64 = alphas[j] - oldAlphaJ;
+      for k, _ := range(gradients) {
+         gradients[k] += labels[k] * (labels[i] * rowI[k] * deltaAlphaI + labels[j] * rowJ[k] * deltaAlphaJ);
+      }
+   }
+
+   var machine binarySvm = binarySvm{
+      supportVectors: make([][]float64, 0),
+      coefficients: make([]float64, 0),
+      rho: calculateRho(alphas, gradients, labels, c),
+   };
+
+   for i, alpha := range(alphas) {
+      if (alpha > 0) {
+         machine.supportVectors = append(machine.supportVectors, data[i]);
+         machine.coefficients = append(machine.coefficients, alpha * labels[i]);
+      }
    }
 
-   return svmTuple;
+   return &machine;
 }
 
-// Write the training data to a file and return the path.
-func writeTrainingFile(data []base.NumericTuple) string {
-   var tempPath string = util.TempFilePath("train", "svm_", "");
+// Choose the maximal violating pair.
+// Returns (-1, -1) if the solution is optimal (within |tolerence|).
+func selectWorkingSet(alphas []float64, gradients []float64, labels []float64, c float64, tolerence float64) (int, int) {
+   var maxViolation float64 = math.Inf(-1);
+   var minViolation float64 = math.Inf(1);
+   var maxIndex int = -1;
+   var minIndex int = -1;
+
+   for i, _ := range(alphas) {
+      var violation float64 = -1.0 * labels[i] * gradients[i];
+
+      // I_up
+      if ((labels[i] > 0 && alphas[i] < c) || (labels[i] < 0 && alphas[i] > 0)) {
+         if (violation > maxViolation) {
+            maxViolation = violation;
+            maxIndex = i;
+         }
+      }
+
+      // I_low
+      if ((labels[i] > 0 && alphas[i] > 0) || (labels[i] < 0 && alphas[i] < c)) {
+         if (violation < minViolation) {
+            minViolation = violation;
+            minIndex = i;
+         }
+      }
+   }
+
+   if (maxIndex == -1 || minIndex == -1 || maxViolation - minViolation < tolerence) {
+      return -1, -1;
+   }
+
+   return maxIndex, minIndex;
+}
 
-   file, err := os.Create(tempPath);
-   if (err != nil) {
-      panic(fmt.Sprintf("Unable to create training file: %s", err));
+// The bias is the average over the free support vectors.
+// If there are none, then it is the middle of the feasible range.
+func calculateRho(alphas []float64, gradients []float64, labels []float64, c float64) float64 {
+   var upperBound float64 = math.Inf(1);
+   var lowerBound float64 = math.Inf(-1);
+   var freeSum float64 = 0;
+   var freeCount int = 0;
+
+   for i, alpha := range(alphas) {
+      var yGradient float64 = labels[i] * gradients[i];
+
+      if (alpha >= c) {
+         if (labels[i] < 0) {
+            upperBound = math.Min(upperBound, yGradient);
+         } else {
+            lowerBound = math.Max(lowerBound, yGradient);
+         }
+      } else if (alpha <= 0) {
+         if (labels[i] > 0) {
+            upperBound = math.Min(upperBound, yGradient);
+         } else {
+            lowerBound = math.Max(lowerBound, yGradient);
+         }
+      } else {
+         freeSum += yGradient;
+         freeCount++;
+      }
    }
-   defer file.Close();
 
-   for _, tuple := range(data) {
-      fmt.Fprintf(file, "%s\n", tupleToTrainingString(tuple));
+   if (freeCount > 0) {
+      return freeSum / float64(freeCount);
    }
 
-   return tempPath;
+   return (upperBound + lowerBound) / 2.0;
 }
 
-func tupleToTrainingString(tuple base.NumericTuple) string {
-   var buf *bytes.Buffer = new(bytes.Buffer);
+// Keep around rows of the kernel matrix so we don't have to recompute them every iteration.
+// When the cache fills up, it is just dropped.
+type kernelCache struct {
+   kernel Kernel
+   data [][]float64
+   maxRows int
+   rows map[int][]float64
+}
 
-   // Class first.
-   fmt.Fprintf(buf, "%v", util.NumericValue(tuple.GetClass().Value()));
+func newKernelCache(kernel Kernel, data [][]float64, maxRows int) *kernelCache {
+   return &kernelCache{
+      kernel: kernel,
+      data: data,
+      maxRows: maxRows,
+      rows: make(map[int][]float64),
+   };
+}
+
+func (this *kernelCache) row(index int) []float64 {
+   row, ok := this.rows[index];
+   if (ok) {
+      return row;
+   }
+
+   if (len(this.rows) >= this.maxRows) {
+      this.rows = make(map[int][]float64);
+   }
 
-   // Each feature.
-   for i := 0; i < tuple.DataSize(); i++ {
-      // libsvm 1-indexes features.
-      fmt.Fprintf(buf, " %d:%v", i + 1, tuple.GetNumericData(i));
+   row = make([]float64, len(this.data));
+   for i, point := range(this.data) {
+      row[i] = this.kernel.Evaluate(this.data[index], point);
    }
 
-   return buf.String();
+   this.rows[index] = row;
+   return row;
 }
@@ -15,6 +15,14 @@ type svmTestCase struct {
    Expected []base.Feature
 }
 
+type svmOptionsTestCase struct {
+   Name string
+   Options SvmOptions
+   TestData []base.Tuple
+   Input []base.Tuple
+   Expected []base.Feature
+}
+
 func TestSvmBase(t *testing.T) {
    var testCases []svmTestCase = []svmTestCase{
       svmTestCase{
@@ -77,24 +85,136 @@ func TestSvmBase(t *testing.T) {
             base.Bool(false),
          },
       },
+      svmTestCase{
+         "Multiclass - One vs Rest",
+         nil,
+         []base.Tuple{
+            base.NewIntTuple([]interface{}{10, 10}, "A"),
+            base.NewIntTuple([]interface{}{9, 9}, "A"),
+            base.NewIntTuple([]interface{}{11, 11}, "A"),
+            base.NewIntTuple([]interface{}{-10, -10}, "B"),
+            base.NewIntTuple([]interface{}{-9, -9}, "B"),
+            base.NewIntTuple([]interface{}{-11, -11}, "B"),
+            base.NewIntTuple([]interface{}{10, -10}, "C"),
+            base.NewIntTuple([]interface{}{9, -9}, "C"),
+            base.NewIntTuple([]interface{}{11, -11}, "C"),
+         },
+         []base.Tuple{
+            base.NewIntTuple([]interface{}{8, 8}, nil),
+            base.NewIntTuple([]interface{}{-8, -8}, nil),
+            base.NewIntTuple([]interface{}{8, -8}, nil),
+         },
+         []base.Feature{
+            base.String("A"),
+            base.String("B"),
+            base.String("C"),
+         },
+      },
    };
 
    for _, testCase := range(testCases) {
-      var svm Classifier = NewSvm(testCase.Reducer);
-      svm.Train(testCase.TestData);
-      var actual []base.Feature;
-      actual, _ = svm.Classify(testCase.Input);
+      checkSvm(t, testCase.Name, NewSvm(testCase.Reducer), testCase.TestData, testCase.Input, testCase.Expected);
+   }
+}
+
+func TestSvmOptions(t *testing.T) {
+   var testCases []svmOptionsTestCase = []svmOptionsTestCase{
+      svmOptionsTestCase{
+         "Linear - 3",
+         SvmOptions{Kernel: LinearKernel{}, C: 10.0},
+         []base.Tuple{
+            base.NewIntTuple([]interface{}{10, 10}, 1),
+            base.NewIntTuple([]interface{}{9, 9}, 1),
+            base.NewIntTuple([]interface{}{11, 11}, 1),
+            base.NewIntTuple([]interface{}{-10, -10}, 0),
+            base.NewIntTuple([]interface{}{-9, -9}, 0),
+            base.NewIntTuple([]interface{}{-11, -11}, 0),
+         },
+         []base.Tuple{
+            base.NewIntTuple([]interface{}{8, 8}, nil),
+            base.NewIntTuple([]interface{}{-8, -8}, nil),
+         },
+         []base.Feature{
+            base.Int(1),
+            base.Int(0),
+         },
+      },
+      svmOptionsTestCase{
+         "Polynomial - 3",
+         SvmOptions{Kernel: NewPolynomialKernel(2, 0, 1)},
+         []base.Tuple{
+            base.NewIntTuple([]interface{}{10, 10}, "A"),
+            base.NewIntTuple([]interface{}{9, 9}, "A"),
+            base.NewIntTuple([]interface{}{11, 11}, "A"),
+            base.NewIntTuple([]interface{}{-10, -10}, "B"),
+            base.NewIntTuple([]interface{}{-9, -9}, "B"),
+            base.NewIntTuple([]interface{}{-11, -11}, "B"),
+         },
+         []base.Tuple{
+            base.NewIntTuple([]interface{}{8, 8}, nil),
+            base.NewIntTuple([]interface{}{-8, -8}, nil),
+         },
+         []base.Feature{
+            base.String("A"),
+            base.String("B"),
+         },
+      },
+      svmOptionsTestCase{
+         "Multiclass - One vs One",
+         SvmOptions{Kernel: LinearKernel{}, Multiclass: SVM_ONE_VS_ONE},
+         []base.Tuple{
+            base.NewIntTuple([]interface{}{10, 10}, "A"),
+            base.NewIntTuple([]interface{}{9, 9}, "A"),
+            base.NewIntTuple([]interface{}{11, 11}, "A"),
+            base.NewIntTuple([]interface{}{-10, -10}, "B"),
+            base.NewIntTuple([]interface{}{-9, -9}, "B"),
+            base.NewIntTuple([]interface{}{-11, -11}, "B"),
+            base.NewIntTuple([]interface{}{10, -10}, "C"),
+            base.NewIntTuple([]interface{}{9, -9}, "C"),
+            base.NewIntTuple([]interface{}{11, -11}, "C"),
+         },
+         []base.Tuple{
+            base.NewIntTuple([]interface{}{8, 8}, nil),
+            base.NewIntTuple([]interface{}{-8, -8}, nil),
+            base.NewIntTuple([]interface{}{8, -8}, nil),
+         },
+         []base.Feature{
+            base.String("A"),
+            base.String("B"),
+            base.String("C"),
+         },
+      },
+   };
+
+   for _, testCase := range(testCases) {
+      checkSvm(t, testCase.Name, NewSvmWithOptions(nil, testCase.Options), testCase.TestData, testCase.Input, testCase.Expected);
+   }
+}
+
+func checkSvm(t *testing.T, name string, svm Classifier, testData []base.Tuple, input []base.Tuple, expected []base.Feature) {
+   svm.Train(testData);
+   var actual []base.Feature;
+   var confidences []float64;
+   actual, confidences = svm.Classify(input);
+
+   if (len(actual) != len(expected)) {
+      t.Errorf("(%s) -- Length of expected (%d) and actual (%d) do not match", name, len(expected), len(actual));
+      return;
+   }
+
+   if (len(confidences) != len(expected)) {
+      t.Errorf("(%s) -- Length of expected (%d) and actual confidences (%d) do not match", name, len(expected), len(confidences));
+      return;
+   }
 
-      if (len(actual) != len(testCase.Expected)) {
-         t.Errorf("(%s) -- Length of expected (%d) and actual (%d) do not match", testCase.Name, len(testCase.Expected), len(actual));
-         continue;
+   // Go over each value explicitly to make output more readable.
+   for i, _ := range(actual) {
+      if (actual[i] != expected[i]) {
+         t.Errorf("(%s)[%d] -- Bad classification. Expected: %v, Got: %v", name, i, expected[i], actual[i]);
       }
 
-      // Go over each value explicitly to make output more readable.
-      for i, _ := range(actual) {
-         if (actual[i] != testCase.Expected[i]) {
-            t.Errorf("(%s)[%d] -- Bad classification. Expected: %v, Got: %v", testCase.Name, i, testCase.Expected[i], actual[i]);
-         }
+      if (confidences[i] <= 0) {
+         t.Errorf("(%s)[%d] -- Expected a positive margin, Got: %v", name, i, confidences[i]);
       }
    }
 }
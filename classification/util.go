@@ -0,0 +1,52 @@
+package classification
+
+import (
+   "fmt"
+
+   "github.com/eriq-augustine/goml/base"
+)
+
+// Assign each label an arbitrary identifier (index into the returned labels).
+// Returns the labels and the label identifier for each tuple.
+func mapLabels(tuples []base.Tuple) ([]base.Feature, []int) {
+   var labels []base.Feature = make([]base.Feature, 0);
+   var labelMap map[base.Feature]int = make(map[base.Feature]int);
+   var dataLabels []int = make([]int, len(tuples));
+
+   for i, tuple := range(tuples) {
+      _, ok := labelMap[tuple.GetClass()];
+      if (!ok) {
+         labelMap[tuple.GetClass()] = len(labels);
+         labels = append(labels, tuple.GetClass());
+      }
+
+      dataLabels[i] = labelMap[tuple.GetClass()];
+   }
+
+   return labels, dataLabels;
+}
+
+// The data of every tuple, which must all be base.NumericTuple with the same number of features.
+// |classifierName| is only used in the panic message.
+func numericTupleData(tuples []base.Tuple, classifierName string) [][]float64 {
+   var data [][]float64 = make([][]float64, len(tuples));
+   var numFeatures int = -1;
+
+   for i, tuple := range(tuples) {
+      numericTuple, ok := tuple.(base.NumericTuple);
+      if (!ok) {
+         panic(fmt.Sprintf("%s only supports NumericTuple. Found type: %T", classifierName, tuple));
+      }
+
+      data[i] = numericTuple.ToFloatSlice();
+
+      if (numFeatures == -1) {
+         numFeatures = numericTuple.DataSize();
+      } else if (numFeatures != numericTuple.DataSize()) {
+         panic(fmt.Sprintf("Inconsistent number of features. Tuple[0]: %d, Tuple[%d]: %d",
+               numFeatures, i, numericTuple.DataSize()));
+      }
+   }
+
+   return data;
+}
@@ -0,0 +1,180 @@
+package clustering
+
+// Agglomerative (bottom up) hierarchical clustering.
+// Every tuple starts in its own cluster and the two closest clusters are merged until there is only one.
+// How close two clusters are depends on the linkage.
+// Uses the nearest neighbor chain algorithm (Murtagh 1983) with Lance-Williams distance updates,
+// so it takes quadratic time and memory (the full pairwise distance matrix).
+
+import (
+   "fmt"
+   "math"
+
+   "github.com/eriq-augustine/goml/base"
+   "github.com/eriq-augustine/goml/util"
+)
+
+type Linkage int
+
+const (
+   // The distance between the closest pair of tuples (one in each cluster).
+   LINKAGE_SINGLE Linkage = iota
+   // The distance between the furthest pair of tuples.
+   LINKAGE_COMPLETE
+   // The mean distance over all pairs of tuples.
+   LINKAGE_AVERAGE
+   // Merge the clusters that least increase the total within cluster variance (Ward 1963).
+   // Only meaningful for base.Euclidean.
+   LINKAGE_WARD
+)
+
+type Agglomerative struct {
+   linkage Linkage
+   distancer base.Distancer
+   // Cluster() cuts the tree at this many clusters (if positive), otherwise at the distance threshold.
+   numClusters int
+   distanceThreshold float64
+   tree *Dendrogram
+}
+
+// Cluster() will cut the tree into |numClusters| clusters if it is positive,
+// otherwise it will cut the tree at |distanceThreshold| (which must then be non-negative).
+// The full tree is always available from Tree().
+// Pass nil for |distancer| to get base.Euclidean.
+func NewAgglomerative(linkage Linkage, distancer base.Distancer, numClusters int, distanceThreshold float64) *Agglomerative {
+   if (linkage < LINKAGE_SINGLE || linkage > LINKAGE_WARD) {
+      panic(fmt.Sprintf("Unknown linkage: %d", linkage));
+   }
+
+   if (numClusters <= 0 && distanceThreshold < 0) {
+      panic("Either the number of clusters or the distance threshold must be given.");
+   }
+
+   if (distancer == nil) {
+      distancer = base.Euclidean{};
+   }
+
+   var agglomerative Agglomerative = Agglomerative{
+      linkage: linkage,
+      distancer: distancer,
+      numClusters: numClusters,
+      distanceThreshold: distanceThreshold,
+      tree: nil,
+   };
+
+   return &agglomerative;
+}
+
+// Labels are base.IntFeature, numbered from 0 in order of each cluster's first tuple.
+func (this *Agglomerative) Cluster(data []base.NumericTuple) []base.Feature {
+   this.tree = this.BuildTree(data);
+
+   if (this.numClusters > 0) {
+      return this.tree.CutByCount(this.numClusters);
+   }
+
+   return this.tree.CutByDistance(this.distanceThreshold);
+}
+
+// The tree from the last call to Cluster().
+func (this Agglomerative) Tree() *Dendrogram {
+   return this.tree;
+}
+
+// Build the full merge tree without cutting it.
+func (this Agglomerative) BuildTree(data []base.NumericTuple) *Dendrogram {
+   if (len(data) == 0) {
+      panic("Need at least one tuple to cluster.");
+   }
+
+   // Each cluster is kept in the slot of one of its tuples, the other slot is deactivated.
+   var distances [][]float64 = pairwiseDistances(this.distancer, data);
+   var sizes []int = make([]int, len(data));
+   var active []bool = make([]bool, len(data));
+   for i, _ := range(data) {
+      sizes[i] = 1;
+      active[i] = true;
+   }
+
+   var edges []linkageMerge = make([]linkageMerge, 0, len(data) - 1);
+   var chain []int = make([]int, 0);
+
+   for len(edges) < len(data) - 1 {
+      if (len(chain) == 0) {
+         for i, isActive := range(active) {
+            if (isActive) {
+               chain = append(chain, i);
+               break;
+            }
+         }
+      }
+
+      var current int = chain[len(chain) - 1];
+
+      // Ties must go to the previous cluster in the chain, otherwise the chain may never end.
+      var nearest int = -1;
+      if (len(chain) > 1) {
+         nearest = chain[len(chain) - 2];
+      }
+
+      for i, isActive := range(active) {
+         if (!isActive || i == current) {
+            continue;
+         }
+
+         if (nearest == -1 || distances[current][i] < distances[current][nearest]) {
+            nearest = i;
+         }
+      }
+
+      if (len(chain) < 2 || nearest != chain[len(chain) - 2]) {
+         chain = append(chain, nearest);
+         continue;
+      }
+
+      // |current| and |nearest| are each other's nearest neighbors, merge them.
+      chain = chain[:len(chain) - 2];
+      edges = append(edges, linkageMerge{left: current, right: nearest, distance: distances[current][nearest]});
+
+      // Keep the merged cluster in the lower slot.
+      var kept int = util.MinInt(current, nearest);
+      var removed int = util.MaxInt(current, nearest);
+
+      for i, isActive := range(active) {
+         if (!isActive || i == current || i == nearest) {
+            continue;
+         }
+
+         var distance float64 = this.updateDistance(distances[i][kept], distances[i][removed], distances[kept][removed],
+               sizes[i], sizes[kept], sizes[removed]);
+         distances[i][kept] = distance;
+         distances[kept][i] = distance;
+      }
+
+      sizes[kept] += sizes[removed];
+      active[removed] = false;
+   }
+
+   return newDendrogram(len(data), linkageTree(len(data), edges));
+}
+
+// Lance-Williams: the distance from cluster k to the merge of clusters i and j.
+func (this Agglomerative) updateDistance(distanceKI float64, distanceKJ float64, distanceIJ float64, sizeK int, sizeI int, sizeJ int) float64 {
+   switch this.linkage {
+   case LINKAGE_SINGLE:
+      return math.Min(distanceKI, distanceKJ);
+   case LINKAGE_COMPLETE:
+      return math.Max(distanceKI, distanceKJ);
+   case LINKAGE_AVERAGE:
+      return (float64(sizeI) * distanceKI + float64(sizeJ) * distanceKJ) / float64(sizeI + sizeJ);
+   case LINKAGE_WARD:
+      var total float64 = float64(sizeI + sizeJ + sizeK);
+      var squared float64 = (float64(sizeI + sizeK) * distanceKI * distanceKI +
+            float64(sizeJ + sizeK) * distanceKJ * distanceKJ -
+            float64(sizeK) * distanceIJ * distanceIJ) / total;
+      // Rounding can make this very slightly negative.
+      return math.Sqrt(math.Max(0, squared));
+   default:
+      panic(fmt.Sprintf("Unknown linkage: %d", this.linkage));
+   }
+}
@@ -0,0 +1,103 @@
+package clustering
+
+import (
+   "encoding/json"
+   "math"
+   "testing"
+
+   "github.com/eriq-augustine/goml/base"
+)
+
+type agglomerativeTestCase struct {
+   Linkage Linkage
+   ExpectedDistances []float64
+}
+
+func TestAgglomerativeLinkage(t *testing.T) {
+   var data []base.NumericTuple = []base.NumericTuple{
+      base.NewFloatTuple([]float64{7}, nil),
+      base.NewFloatTuple([]float64{0}, nil),
+      base.NewFloatTuple([]float64{3}, nil),
+      base.NewFloatTuple([]float64{1}, nil),
+   };
+
+   var testCases []agglomerativeTestCase = []agglomerativeTestCase{
+      agglomerativeTestCase{LINKAGE_SINGLE, []float64{1, 2, 4}},
+      agglomerativeTestCase{LINKAGE_COMPLETE, []float64{1, 3, 7}},
+      agglomerativeTestCase{LINKAGE_AVERAGE, []float64{1, 2.5, 17.0 / 3.0}},
+      // sqrt(2 * |A| * |B| / (|A| + |B|)) * distance(mean(A), mean(B))
+      agglomerativeTestCase{LINKAGE_WARD, []float64{1, math.Sqrt(4.0 / 3.0) * 2.5, math.Sqrt(1.5) * 17.0 / 3.0}},
+   };
+
+   for _, testCase := range(testCases) {
+      var tree *Dendrogram = NewAgglomerative(testCase.Linkage, nil, 1, -1).BuildTree(data);
+      var merges []DendrogramMerge = tree.Merges();
+
+      if (len(merges) != len(testCase.ExpectedDistances)) {
+         t.Errorf("(%d) -- Wrong number of merges. Expected: %d, Got: %d", testCase.Linkage, len(testCase.ExpectedDistances), len(merges));
+         continue;
+      }
+
+      for i, merge := range(merges) {
+         if (math.Abs(merge.Distance - testCase.ExpectedDistances[i]) > 1e-9) {
+            t.Errorf("(%d)[%d] -- Bad merge distance. Expected: %v, Got: %v", testCase.Linkage, i, testCase.ExpectedDistances[i], merge.Distance);
+         }
+
+         if (merge.Size != i + 2) {
+            t.Errorf("(%d)[%d] -- Bad merge size. Expected: %d, Got: %d", testCase.Linkage, i, i + 2, merge.Size);
+         }
+      }
+   }
+}
+
+func TestAgglomerativeCluster(t *testing.T) {
+   data, truth := blobs([][]float64{{0, 0}, {10, 0}, {0, 10}}, 30, 1.0, 12);
+
+   for _, linkage := range([]Linkage{LINKAGE_SINGLE, LINKAGE_COMPLETE, LINKAGE_AVERAGE, LINKAGE_WARD}) {
+      var agglomerative *Agglomerative = NewAgglomerative(linkage, nil, 3, -1);
+      if (!sameClusters(truth, agglomerative.Cluster(data))) {
+         t.Errorf("(%d) -- Wrong clusters when cutting by count.", linkage);
+      }
+
+      // Everything within a blob merges before anything across blobs.
+      var merges []DendrogramMerge = agglomerative.Tree().Merges();
+      var threshold float64 = (merges[len(merges) - 3].Distance + merges[len(merges) - 2].Distance) / 2.0;
+
+      var byDistance *Agglomerative = NewAgglomerative(linkage, base.Euclidean{}, 0, threshold);
+      if (!sameClusters(truth, byDistance.Cluster(data))) {
+         t.Errorf("(%d) -- Wrong clusters when cutting by distance.", linkage);
+      }
+   }
+}
+
+func TestDendrogramExport(t *testing.T) {
+   var data []base.NumericTuple = []base.NumericTuple{
+      base.NewFloatTuple([]float64{0}, nil),
+      base.NewFloatTuple([]float64{1}, nil),
+      base.NewFloatTuple([]float64{3}, nil),
+   };
+
+   var tree *Dendrogram = NewAgglomerative(LINKAGE_SINGLE, nil, 1, -1).BuildTree(data);
+
+   var expected string = "(2:2,(0:1,1:1):1);";
+   var actual string = tree.Newick(nil);
+   if (actual != expected) {
+      t.Errorf("Bad Newick. Expected: %s, Got: %s", expected, actual);
+   }
+
+   expected = "(c:2,(a:1,b:1):1);";
+   actual = tree.Newick([]string{"a", "b", "c"});
+   if (actual != expected) {
+      t.Errorf("Bad named Newick. Expected: %s, Got: %s", expected, actual);
+   }
+
+   bytes, err := json.Marshal(tree);
+   if (err != nil) {
+      t.Fatalf("Failed to marshal: %v", err);
+   }
+
+   expected = `{"node":4,"distance":2,"size":3,"children":[{"node":2,"distance":0,"size":1},{"node":3,"distance":1,"size":2,"children":[{"node":0,"distance":0,"size":1},{"node":1,"distance":0,"size":1}]}]}`;
+   if (string(bytes) != expected) {
+      t.Errorf("Bad JSON. Expected: %s, Got: %s", expected, string(bytes));
+   }
+}
@@ -0,0 +1,17 @@
+package clustering
+
+import (
+   "github.com/eriq-augustine/goml/base"
+)
+
+const (
+   // Clusterers that can leave tuples out of every cluster label them with base.Int(NOISE_LABEL).
+   NOISE_LABEL = -1
+)
+
+// Clusterers group tuples without looking at their classes.
+type Clusterer interface {
+   // Find the clusters in |data| and return the cluster label for each tuple.
+   // Labels can be put right back into the tuples with Tuple.SetClass().
+   Cluster(data []base.NumericTuple) []base.Feature
+}
@@ -0,0 +1,105 @@
+package clustering
+
+// Density-based spatial clustering of applications with noise (Ester et al. 1996).
+// A core tuple has at least minPoints tuples (counting itself) within epsilon of it.
+// Clusters are grown from core tuples through every tuple within epsilon of a core tuple.
+// Tuples that cannot be reached from any core tuple are noise.
+
+import (
+   "github.com/eriq-augustine/goml/base"
+)
+
+const (
+   DBSCAN_DEFAULT_MIN_POINTS = 5
+)
+
+type DBSCAN struct {
+   epsilon float64
+   minPoints int
+   distancer base.Distancer
+   numClusters int
+}
+
+// Pass nil for |distancer| to get base.Euclidean.
+// Pass a non-positive value for |minPoints| to get the default.
+func NewDBSCAN(epsilon float64, minPoints int, distancer base.Distancer) *DBSCAN {
+   if (epsilon <= 0) {
+      panic("epsilon must be positive.");
+   }
+
+   if (minPoints <= 0) {
+      minPoints = DBSCAN_DEFAULT_MIN_POINTS;
+   }
+
+   if (distancer == nil) {
+      distancer = base.Euclidean{};
+   }
+
+   var dbscan DBSCAN = DBSCAN{
+      epsilon: epsilon,
+      minPoints: minPoints,
+      distancer: distancer,
+   };
+
+   return &dbscan;
+}
+
+// Labels are base.IntFeature, numbered from 0 in the order that clusters are found (by tuple index).
+// Noise is labeled with NOISE_LABEL.
+// A border tuple (close to core tuples from different clusters) goes to the first cluster that reaches it.
+func (this *DBSCAN) Cluster(data []base.NumericTuple) []base.Feature {
+   var neighbors [][]int = radiusNeighbors(this.distancer, data, this.epsilon);
+
+   var assignments []int = make([]int, len(data));
+   var visited []bool = make([]bool, len(data));
+   for i, _ := range(assignments) {
+      assignments[i] = NOISE_LABEL;
+   }
+
+   this.numClusters = 0;
+   for i, _ := range(data) {
+      if (visited[i] || len(neighbors[i]) < this.minPoints) {
+         continue;
+      }
+
+      var cluster int = this.numClusters;
+      this.numClusters++;
+
+      visited[i] = true;
+      assignments[i] = cluster;
+
+      // Breadth first expansion from the core tuple.
+      var queue []int = append([]int(nil), neighbors[i]...);
+      for len(queue) > 0 {
+         var current int = queue[0];
+         queue = queue[1:];
+
+         if (assignments[current] == NOISE_LABEL) {
+            assignments[current] = cluster;
+         }
+
+         if (visited[current]) {
+            continue;
+         }
+         visited[current] = true;
+
+         // Only core tuples spread the cluster.
+         if (len(neighbors[current]) < this.minPoints) {
+            continue;
+         }
+
+         for _, neighbor := range(neighbors[current]) {
+            if (!visited[neighbor]) {
+               queue = append(queue, neighbor);
+            }
+         }
+      }
+   }
+
+   return clusterLabels(assignments);
+}
+
+// The number of clusters (not counting noise) found in the last call to Cluster().
+func (this DBSCAN) NumClusters() int {
+   return this.numClusters;
+}
@@ -0,0 +1,94 @@
+package clustering
+
+import (
+   "math"
+   "math/rand"
+   "testing"
+
+   "github.com/eriq-augustine/goml/base"
+)
+
+// Two interleaved half circles (that k-means can not separate), and some far away noise.
+// The noise is labeled with NOISE_LABEL in the truth.
+func moons(pointsPerMoon int, noise []([]float64), seed int64) ([]base.NumericTuple, []int) {
+   var random *rand.Rand = rand.New(rand.NewSource(seed));
+
+   var data []base.NumericTuple = make([]base.NumericTuple, 0);
+   var truth []int = make([]int, 0);
+
+   for i := 0; i < pointsPerMoon; i++ {
+      var angle float64 = math.Pi * float64(i) / float64(pointsPerMoon - 1);
+
+      data = append(data, base.NewFloatTuple([]float64{math.Cos(angle) + random.NormFloat64() * 0.03, math.Sin(angle) + random.NormFloat64() * 0.03}, nil));
+      truth = append(truth, 0);
+
+      data = append(data, base.NewFloatTuple([]float64{1.0 - math.Cos(angle) + random.NormFloat64() * 0.03, 0.5 - math.Sin(angle) + random.NormFloat64() * 0.03}, nil));
+      truth = append(truth, 1);
+   }
+
+   for _, point := range(noise) {
+      data = append(data, base.NewFloatTuple(point, nil));
+      truth = append(truth, NOISE_LABEL);
+   }
+
+   return data, truth;
+}
+
+// Like sameClusters(), but noise must be labeled as exactly NOISE_LABEL.
+func sameClustersWithNoise(truth []int, labels []base.Feature) bool {
+   for i, _ := range(truth) {
+      if ((truth[i] == NOISE_LABEL) != (labels[i] == base.Int(NOISE_LABEL))) {
+         return false;
+      }
+   }
+
+   return sameClusters(truth, labels);
+}
+
+type densityTestCase struct {
+   Name string
+   Clusterer Clusterer
+   ExpectedClusters int
+}
+
+func TestDBSCAN(t *testing.T) {
+   data, truth := moons(100, [][]float64{{5, 5}, {-5, 5}, {0, -5}}, 8);
+
+   var testCases []densityTestCase = []densityTestCase{
+      densityTestCase{"Moons", NewDBSCAN(0.2, 4, nil), 2},
+      densityTestCase{"Moons - Default Min Points", NewDBSCAN(0.2, 0, base.Euclidean{}), 2},
+      densityTestCase{"Moons - Manhattan", NewDBSCAN(0.25, 4, base.Manhattan{}), 2},
+   };
+
+   for _, testCase := range(testCases) {
+      var labels []base.Feature = testCase.Clusterer.Cluster(data);
+      if (!sameClustersWithNoise(truth, labels)) {
+         t.Errorf("(%s) -- Wrong clusters: %v", testCase.Name, labels);
+      }
+
+      var numClusters int = testCase.Clusterer.(*DBSCAN).NumClusters();
+      if (numClusters != testCase.ExpectedClusters) {
+         t.Errorf("(%s) -- Wrong number of clusters. Expected: %d, Got: %d", testCase.Name, testCase.ExpectedClusters, numClusters);
+      }
+   }
+}
+
+// Every tuple is noise when nothing is dense enough.
+func TestDBSCANAllNoise(t *testing.T) {
+   var data []base.NumericTuple = []base.NumericTuple{
+      base.NewFloatTuple([]float64{0, 0}, nil),
+      base.NewFloatTuple([]float64{10, 0}, nil),
+      base.NewFloatTuple([]float64{0, 10}, nil),
+   };
+
+   var dbscan *DBSCAN = NewDBSCAN(1, 2, nil);
+   for i, label := range(dbscan.Cluster(data)) {
+      if (label != base.Int(NOISE_LABEL)) {
+         t.Errorf("[%d] -- Expected noise, got: %v", i, label);
+      }
+   }
+
+   if (dbscan.NumClusters() != 0) {
+      t.Errorf("Expected no clusters, got: %d", dbscan.NumClusters());
+   }
+}
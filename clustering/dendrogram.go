@@ -0,0 +1,264 @@
+package clustering
+
+// Merge (linkage) trees from hierarchical clustering.
+
+import (
+   "bytes"
+   "encoding/json"
+   "fmt"
+   "sort"
+   "strconv"
+
+   "github.com/eriq-augustine/goml/base"
+   "github.com/eriq-augustine/goml/util"
+)
+
+// The full merge tree from a hierarchical clustering.
+// The leaves are the tuples (by index) and every internal node merges two clusters at some distance.
+type Dendrogram struct {
+   numTuples int
+   // Ordered by distance.
+   merges []linkageMerge
+}
+
+// Nodes [0, NumTuples()) are the tuples, Merges()[i] creates node NumTuples() + i.
+type DendrogramMerge struct {
+   Left int
+   Right int
+   Distance float64
+   // The number of tuples in the merged cluster.
+   Size int
+}
+
+// A merge in a linkage tree.
+// Nodes [0, numTuples) are the tuples, the merge at index i creates node numTuples + i.
+type linkageMerge struct {
+   left int
+   right int
+   distance float64
+   size int
+}
+
+// Turn |edges| (pairs of tuples in the clusters to merge) into a merge tree by merging from shortest to longest.
+// Edges of the same distance are merged in the order given.
+func linkageTree(numTuples int, edges []linkageMerge) []linkageMerge {
+   edges = append([]linkageMerge(nil), edges...);
+   sort.SliceStable(edges, func(i int, j int) bool {
+      return edges[i].distance < edges[j].distance;
+   });
+
+   // Union-find over the tree nodes.
+   var parents []int = make([]int, 2 * numTuples - 1);
+   var sizes []int = make([]int, 2 * numTuples - 1);
+   for i, _ := range(parents) {
+      parents[i] = i;
+      sizes[i] = 1;
+   }
+
+   var find func(int) int = func(node int) int {
+      for parents[node] != node {
+         parents[node] = parents[parents[node]];
+         node = parents[node];
+      }
+      return node;
+   };
+
+   var merges []linkageMerge = make([]linkageMerge, len(edges));
+   for i, edge := range(edges) {
+      // The lower node goes on the left so that the tree does not depend on the order of the edges.
+      var left int = util.MinInt(find(edge.left), find(edge.right));
+      var right int = util.MaxInt(find(edge.left), find(edge.right));
+      var node int = numTuples + i;
+
+      sizes[node] = sizes[left] + sizes[right];
+      parents[left] = node;
+      parents[right] = node;
+
+      merges[i] = linkageMerge{left: left, right: right, distance: edge.distance, size: sizes[node]};
+   }
+
+   return merges;
+}
+
+func linkageSize(merges []linkageMerge, node int) int {
+   if (node <= len(merges)) {
+      return 1;
+   }
+
+   return merges[node - len(merges) - 1].size;
+}
+
+// All the tuples under |node|.
+func linkageTuples(merges []linkageMerge, node int) []int {
+   var tuples []int = make([]int, 0);
+
+   var stack []int = []int{node};
+   for len(stack) > 0 {
+      var current int = stack[len(stack) - 1];
+      stack = stack[:len(stack) - 1];
+
+      if (current <= len(merges)) {
+         tuples = append(tuples, current);
+      } else {
+         var merge linkageMerge = merges[current - len(merges) - 1];
+         stack = append(stack, merge.left, merge.right);
+      }
+   }
+
+   return tuples;
+}
+
+func newDendrogram(numTuples int, merges []linkageMerge) *Dendrogram {
+   var dendrogram Dendrogram = Dendrogram{
+      numTuples: numTuples,
+      merges: merges,
+   };
+
+   return &dendrogram;
+}
+
+func (this Dendrogram) NumTuples() int {
+   return this.numTuples;
+}
+
+// Ordered by distance.
+func (this Dendrogram) Merges() []DendrogramMerge {
+   var merges []DendrogramMerge = make([]DendrogramMerge, len(this.merges));
+   for i, merge := range(this.merges) {
+      merges[i] = DendrogramMerge{merge.left, merge.right, merge.distance, merge.size};
+   }
+
+   return merges;
+}
+
+// Cut the tree so that there are |numClusters| clusters.
+// Labels are base.IntFeature, numbered from 0 in order of each cluster's first tuple.
+func (this Dendrogram) CutByCount(numClusters int) []base.Feature {
+   if (numClusters < 1 || numClusters > this.numTuples) {
+      panic(fmt.Sprintf("Number of clusters must be in [1, %d], got %d.", this.numTuples, numClusters));
+   }
+
+   return this.cut(this.numTuples - numClusters);
+}
+
+// Cut the tree so that every merge at or below |threshold| is kept.
+// Labels are base.IntFeature, numbered from 0 in order of each cluster's first tuple.
+func (this Dendrogram) CutByDistance(threshold float64) []base.Feature {
+   var numMerges int = 0;
+   for numMerges < len(this.merges) && this.merges[numMerges].distance <= threshold {
+      numMerges++;
+   }
+
+   return this.cut(numMerges);
+}
+
+// Label the tuples using only the first |numMerges| merges.
+func (this Dendrogram) cut(numMerges int) []base.Feature {
+   // The cluster (node) that each node ends up in.
+   var roots []int = make([]int, this.numTuples + numMerges);
+   for i, _ := range(roots) {
+      roots[i] = i;
+   }
+
+   // Later merges are higher up, so walk down from them.
+   for i := numMerges - 1; i >= 0; i-- {
+      var node int = this.numTuples + i;
+      roots[this.merges[i].left] = roots[node];
+      roots[this.merges[i].right] = roots[node];
+   }
+
+   var labels map[int]int = make(map[int]int);
+   var assignments []int = make([]int, this.numTuples);
+   for i, _ := range(assignments) {
+      label, ok := labels[roots[i]];
+      if (!ok) {
+         label = len(labels);
+         labels[roots[i]] = label;
+      }
+
+      assignments[i] = label;
+   }
+
+   return clusterLabels(assignments);
+}
+
+// The tree in Newick format, eg: "(2:3,(0:1,1:1):2);".
+// Branch lengths are the difference in merge distance between a node and its parent (tuples are at 0).
+// Pass nil for |names| to name each tuple by its index.
+func (this Dendrogram) Newick(names []string) string {
+   if (names != nil && len(names) != this.numTuples) {
+      panic(fmt.Sprintf("Expected %d names (one per tuple), got %d.", this.numTuples, len(names)));
+   }
+
+   var buffer bytes.Buffer;
+   this.writeNewick(&buffer, this.numTuples + len(this.merges) - 1, names);
+   buffer.WriteString(";");
+
+   return buffer.String();
+}
+
+func (this Dendrogram) writeNewick(buffer *bytes.Buffer, node int, names []string) {
+   if (node < this.numTuples) {
+      if (names == nil) {
+         buffer.WriteString(strconv.Itoa(node));
+      } else {
+         buffer.WriteString(names[node]);
+      }
+      return;
+   }
+
+   var merge linkageMerge = this.merges[node - this.numTuples];
+
+   buffer.WriteString("(");
+   for i, child := range([]int{merge.left, merge.right}) {
+      if (i > 0) {
+         buffer.WriteString(",");
+      }
+
+      this.writeNewick(buffer, child, names);
+      buffer.WriteString(":");
+      buffer.WriteString(strconv.FormatFloat(merge.distance - this.height(child), 'g', -1, 64));
+   }
+   buffer.WriteString(")");
+}
+
+// The merge distance of a node (0 for tuples).
+func (this Dendrogram) height(node int) float64 {
+   if (node < this.numTuples) {
+      return 0;
+   }
+
+   return this.merges[node - this.numTuples].distance;
+}
+
+type dendrogramNode struct {
+   Node int `json:"node"`
+   Distance float64 `json:"distance"`
+   Size int `json:"size"`
+   Children []dendrogramNode `json:"children,omitempty"`
+}
+
+// The tree as nested JSON objects, eg:
+// {"node": 4, "distance": 3, "size": 3, "children": [{"node": 2, "distance": 0, "size": 1}, {"node": 3, ...}]}
+// Tuples are the nodes with the same number as their index.
+func (this Dendrogram) MarshalJSON() ([]byte, error) {
+   if (this.numTuples == 0) {
+      return []byte("null"), nil;
+   }
+
+   return json.Marshal(this.jsonNode(this.numTuples + len(this.merges) - 1));
+}
+
+func (this Dendrogram) jsonNode(node int) dendrogramNode {
+   if (node < this.numTuples) {
+      return dendrogramNode{Node: node, Distance: 0, Size: 1};
+   }
+
+   var merge linkageMerge = this.merges[node - this.numTuples];
+   return dendrogramNode{
+      Node: node,
+      Distance: merge.distance,
+      Size: merge.size,
+      Children: []dendrogramNode{this.jsonNode(merge.left), this.jsonNode(merge.right)},
+   };
+}
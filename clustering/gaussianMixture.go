@@ -0,0 +1,449 @@
+package clustering
+
+// A mixture of k Gaussians fit with expectation-maximization (Dempster, Laird, and Rubin 1977).
+// E-step: the responsibility of each component for each tuple (the posterior probability of the component).
+// M-step: the weights, means, and covariances that maximize the expected log-likelihood given the responsibilities.
+// Everything in the E-step is done in log space so tiny densities do not underflow.
+// Initialized from a k-means clustering.
+
+import (
+   "fmt"
+   "math"
+
+   "github.com/eriq-augustine/goml/base"
+   "github.com/eriq-augustine/goml/util"
+
+   "gonum.org/v1/gonum/mat"
+)
+
+const (
+   GMM_DEFAULT_MAX_ITERATIONS = 100
+   // On the change in the mean log-likelihood (per tuple).
+   GMM_DEFAULT_TOLERENCE = 1e-3
+   // Added to the diagonal of every covariance to keep it positive definite.
+   GMM_DEFAULT_REGULARIZATION = 1e-6
+)
+
+type CovarianceType int
+
+const (
+   // Each component has its own unrestricted covariance matrix.
+   GMM_COVARIANCE_FULL CovarianceType = iota
+   // Each component has its own diagonal covariance matrix (features are independent).
+   GMM_COVARIANCE_DIAGONAL
+   // Each component has a single variance for all features.
+   GMM_COVARIANCE_SPHERICAL
+)
+
+type GaussianMixture struct {
+   k int
+   covarianceType CovarianceType
+   maxIterations int
+   tolerence float64
+   regularization float64
+
+   weights []float64
+   components []gaussianComponent
+   // The mean log-likelihood per tuple at the end of the last fit.
+   logLikelihood float64
+   iterations int
+   converged bool
+}
+
+type gaussianComponent struct {
+   mean []float64
+   // Always the full matrix (even for diagonal and spherical).
+   covariance [][]float64
+   // The lower Cholesky factor of the covariance.
+   // Only set for GMM_COVARIANCE_FULL.
+   lower [][]float64
+   logDeterminant float64
+}
+
+// Pass a non-positive value for |maxIterations| or |tolerence| to get the default.
+// Pass a negative value for |regularization| to get the default.
+func NewGaussianMixture(k int, covarianceType CovarianceType, maxIterations int, tolerence float64, regularization float64) *GaussianMixture {
+   if (k <= 0) {
+      panic("k must be >= 1");
+   }
+
+   if (covarianceType < GMM_COVARIANCE_FULL || covarianceType > GMM_COVARIANCE_SPHERICAL) {
+      panic(fmt.Sprintf("Unknown covariance type: %d", covarianceType));
+   }
+
+   if (maxIterations <= 0) {
+      maxIterations = GMM_DEFAULT_MAX_ITERATIONS;
+   }
+
+   if (tolerence <= 0) {
+      tolerence = GMM_DEFAULT_TOLERENCE;
+   }
+
+   if (regularization < 0) {
+      regularization = GMM_DEFAULT_REGULARIZATION;
+   }
+
+   var mixture GaussianMixture = GaussianMixture{
+      k: k,
+      covarianceType: covarianceType,
+      maxIterations: maxIterations,
+      tolerence: tolerence,
+      regularization: regularization,
+   };
+
+   return &mixture;
+}
+
+// Fit the mixture and label each tuple with its most responsible component.
+// Labels are base.IntFeature, the index of the component.
+func (this *GaussianMixture) Cluster(data []base.NumericTuple) []base.Feature {
+   this.Fit(data);
+   return this.Predict(data);
+}
+
+func (this *GaussianMixture) Fit(data []base.NumericTuple) {
+   if (len(data) < this.k) {
+      panic(fmt.Sprintf("Need at least k (%d) tuples to fit, got %d.", this.k, len(data)));
+   }
+
+   var points [][]float64 = make([][]float64, len(data));
+   for i, tuple := range(data) {
+      points[i] = tuple.ToFloatSlice();
+   }
+
+   // Start from hard k-means assignments.
+   var kMeans *KMeans = NewKMeans(this.k, base.Euclidean{}, 0, 1, 0);
+   var responsibilities [][]float64 = make([][]float64, len(data));
+   for i, label := range(kMeans.Cluster(data)) {
+      responsibilities[i] = make([]float64, this.k);
+      responsibilities[i][label.(base.IntFeature).IntValue()] = 1;
+   }
+   this.maximize(points, responsibilities);
+
+   this.converged = false;
+   this.logLikelihood = math.Inf(-1);
+   this.iterations = 0;
+   for this.iterations < this.maxIterations {
+      this.iterations++;
+
+      var logLikelihood float64;
+      responsibilities, logLikelihood = this.expect(points);
+      this.maximize(points, responsibilities);
+
+      var change float64 = logLikelihood - this.logLikelihood;
+      this.logLikelihood = logLikelihood;
+
+      if (math.Abs(change) < this.tolerence) {
+         this.converged = true;
+         break;
+      }
+   }
+
+   // The log-likelihood of the final parameters.
+   _, this.logLikelihood = this.expect(points);
+}
+
+// Label each tuple with its most responsible component.
+func (this GaussianMixture) Predict(data []base.NumericTuple) []base.Feature {
+   var assignments []int = make([]int, len(data));
+   for i, responsibilities := range(this.Responsibilities(data)) {
+      assignments[i], _ = util.Max(responsibilities);
+   }
+
+   return clusterLabels(assignments);
+}
+
+// The soft assignments: the probability of each component (given the tuple) for each tuple.
+// [tuple][component]
+func (this GaussianMixture) Responsibilities(data []base.NumericTuple) [][]float64 {
+   responsibilities, _ := this.expect(this.checkFit(data));
+   return responsibilities;
+}
+
+// The log of the mixture density at each tuple.
+func (this GaussianMixture) ScoreSamples(data []base.NumericTuple) []float64 {
+   var points [][]float64 = this.checkFit(data);
+
+   var scores []float64 = make([]float64, len(points));
+   for i, point := range(points) {
+      scores[i] = util.LogSumExp(this.weightedLogDensities(point));
+   }
+
+   return scores;
+}
+
+// The mean log-likelihood (per tuple) of the training data at the end of the last call to Fit().
+func (this GaussianMixture) LogLikelihood() float64 {
+   return this.logLikelihood;
+}
+
+// Bayesian information criterion, lower is better.
+func (this GaussianMixture) BIC(data []base.NumericTuple) float64 {
+   return -2.0 * this.totalLogLikelihood(data) + float64(this.NumParameters()) * math.Log(float64(len(data)));
+}
+
+// Akaike information criterion, lower is better.
+func (this GaussianMixture) AIC(data []base.NumericTuple) float64 {
+   return -2.0 * this.totalLogLikelihood(data) + 2.0 * float64(this.NumParameters());
+}
+
+// The number of free parameters (used for BIC and AIC).
+func (this GaussianMixture) NumParameters() int {
+   if (this.components == nil) {
+      panic("GaussianMixture must be fit first.");
+   }
+
+   var numFeatures int = len(this.components[0].mean);
+
+   var covarianceParams int;
+   switch this.covarianceType {
+   case GMM_COVARIANCE_FULL:
+      covarianceParams = this.k * numFeatures * (numFeatures + 1) / 2;
+   case GMM_COVARIANCE_DIAGONAL:
+      covarianceParams = this.k * numFeatures;
+   case GMM_COVARIANCE_SPHERICAL:
+      covarianceParams = this.k;
+   }
+
+   // The weights must sum to one, so one is not free.
+   return covarianceParams + this.k * numFeatures + this.k - 1;
+}
+
+func (this GaussianMixture) Weights() []float64 {
+   return append([]float64(nil), this.weights...);
+}
+
+// [component][feature]
+func (this GaussianMixture) Means() [][]float64 {
+   var means [][]float64 = make([][]float64, len(this.components));
+   for i, component := range(this.components) {
+      means[i] = append([]float64(nil), component.mean...);
+   }
+
+   return means;
+}
+
+// The full covariance matrix of each component (even for diagonal and spherical).
+// [component][feature][feature]
+func (this GaussianMixture) Covariances() [][][]float64 {
+   var covariances [][][]float64 = make([][][]float64, len(this.components));
+   for i, component := range(this.components) {
+      covariances[i] = make([][]float64, len(component.covariance));
+      for j, row := range(component.covariance) {
+         covariances[i][j] = append([]float64(nil), row...);
+      }
+   }
+
+   return covariances;
+}
+
+// The number of EM iterations in the last call to Fit().
+func (this GaussianMixture) Iterations() int {
+   return this.iterations;
+}
+
+// Did the last call to Fit() converge before running out of iterations.
+func (this GaussianMixture) Converged() bool {
+   return this.converged;
+}
+
+func (this GaussianMixture) checkFit(data []base.NumericTuple) [][]float64 {
+   if (this.components == nil) {
+      panic("GaussianMixture must be fit first.");
+   }
+
+   var points [][]float64 = make([][]float64, len(data));
+   for i, tuple := range(data) {
+      if (tuple.DataSize() != len(this.components[0].mean)) {
+         panic(fmt.Sprintf("Expected %d features, got %d.", len(this.components[0].mean), tuple.DataSize()));
+      }
+      points[i] = tuple.ToFloatSlice();
+   }
+
+   return points;
+}
+
+func (this GaussianMixture) totalLogLikelihood(data []base.NumericTuple) float64 {
+   var total float64 = 0;
+   for _, score := range(this.ScoreSamples(data)) {
+      total += score;
+   }
+
+   return total;
+}
+
+// Returns the responsibilities and the mean log-likelihood.
+func (this GaussianMixture) expect(points [][]float64) ([][]float64, float64) {
+   var responsibilities [][]float64 = make([][]float64, len(points));
+   var logLikelihood float64 = 0;
+
+   for i, point := range(points) {
+      var logDensities []float64 = this.weightedLogDensities(point);
+      var logTotal float64 = util.LogSumExp(logDensities);
+      logLikelihood += logTotal;
+
+      responsibilities[i] = make([]float64, this.k);
+      for component, logDensity := range(logDensities) {
+         responsibilities[i][component] = math.Exp(logDensity - logTotal);
+      }
+   }
+
+   return responsibilities, logLikelihood / float64(len(points));
+}
+
+// log(weight * density) for each component.
+func (this GaussianMixture) weightedLogDensities(point []float64) []float64 {
+   var logDensities []float64 = make([]float64, this.k);
+   for i, component := range(this.components) {
+      logDensities[i] = math.Log(this.weights[i]) + this.logDensity(component, point);
+   }
+
+   return logDensities;
+}
+
+func (this GaussianMixture) logDensity(component gaussianComponent, point []float64) float64 {
+   var numFeatures int = len(point);
+
+   var diff []float64 = make([]float64, numFeatures);
+   for i, _ := range(diff) {
+      diff[i] = point[i] - component.mean[i];
+   }
+
+   // (x - mean)' covariance^-1 (x - mean)
+   var mahalanobis float64 = 0;
+   if (this.covarianceType == GMM_COVARIANCE_FULL) {
+      // Forward substitution with the Cholesky factor: solve L y = diff, then the distance is y'y.
+      var solved []float64 = make([]float64, numFeatures);
+      for i := 0; i < numFeatures; i++ {
+         var sum float64 = diff[i];
+         for j := 0; j < i; j++ {
+            sum -= component.lower[i][j] * solved[j];
+         }
+         solved[i] = sum / component.lower[i][i];
+         mahalanobis += solved[i] * solved[i];
+      }
+   } else {
+      for i, value := range(diff) {
+         mahalanobis += value * value / component.covariance[i][i];
+      }
+   }
+
+   return -0.5 * (float64(numFeatures) * math.Log(2.0 * math.Pi) + component.logDeterminant + mahalanobis);
+}
+
+func (this *GaussianMixture) maximize(points [][]float64, responsibilities [][]float64) {
+   var numFeatures int = len(points[0]);
+
+   this.weights = make([]float64, this.k);
+   this.components = make([]gaussianComponent, this.k);
+
+   for component := 0; component < this.k; component++ {
+      // A little extra so empty components do not divide by zero.
+      var total float64 = 10.0 * util.EPSILON;
+      var mean []float64 = make([]float64, numFeatures);
+
+      for i, point := range(points) {
+         total += responsibilities[i][component];
+         for j, value := range(point) {
+            mean[j] += responsibilities[i][component] * value;
+         }
+      }
+
+      for j, _ := range(mean) {
+         mean[j] /= total;
+      }
+
+      var covariance [][]float64 = make([][]float64, numFeatures);
+      for j, _ := range(covariance) {
+         covariance[j] = make([]float64, numFeatures);
+      }
+
+      for i, point := range(points) {
+         for j := 0; j < numFeatures; j++ {
+            for l := 0; l <= j; l++ {
+               if (j != l && this.covarianceType != GMM_COVARIANCE_FULL) {
+                  continue;
+               }
+
+               covariance[j][l] += responsibilities[i][component] * (point[j] - mean[j]) * (point[l] - mean[l]);
+            }
+         }
+      }
+
+      for j := 0; j < numFeatures; j++ {
+         for l := 0; l <= j; l++ {
+            covariance[j][l] /= total;
+            covariance[l][j] = covariance[j][l];
+         }
+      }
+
+      if (this.covarianceType == GMM_COVARIANCE_SPHERICAL) {
+         var variance float64 = 0;
+         for j := 0; j < numFeatures; j++ {
+            variance += covariance[j][j];
+         }
+         variance /= float64(numFeatures);
+
+         for j := 0; j < numFeatures; j++ {
+            covariance[j][j] = variance;
+         }
+      }
+
+      for j := 0; j < numFeatures; j++ {
+         covariance[j][j] += this.regularization;
+      }
+
+      this.weights[component] = total;
+      this.components[component] = this.newComponent(mean, covariance);
+   }
+
+   var totalWeight float64 = 0;
+   for _, weight := range(this.weights) {
+      totalWeight += weight;
+   }
+
+   for component, _ := range(this.weights) {
+      this.weights[component] /= totalWeight;
+   }
+}
+
+func (this GaussianMixture) newComponent(mean []float64, covariance [][]float64) gaussianComponent {
+   var component gaussianComponent = gaussianComponent{
+      mean: mean,
+      covariance: covariance,
+   };
+
+   if (this.covarianceType != GMM_COVARIANCE_FULL) {
+      for i, _ := range(covariance) {
+         component.logDeterminant += math.Log(covariance[i][i]);
+      }
+
+      return component;
+   }
+
+   var symmetric *mat.SymDense = mat.NewSymDense(len(covariance), nil);
+   for i, row := range(covariance) {
+      for j := i; j < len(row); j++ {
+         symmetric.SetSym(i, j, row[j]);
+      }
+   }
+
+   var cholesky mat.Cholesky;
+   if (!cholesky.Factorize(symmetric)) {
+      panic("Covariance is not positive definite, try more regularization.");
+   }
+
+   var lower mat.TriDense;
+   cholesky.LTo(&lower);
+
+   component.lower = make([][]float64, len(covariance));
+   for i, _ := range(component.lower) {
+      component.lower[i] = make([]float64, len(covariance));
+      for j := 0; j <= i; j++ {
+         component.lower[i][j] = lower.At(i, j);
+      }
+   }
+   component.logDeterminant = cholesky.LogDet();
+
+   return component;
+}
@@ -0,0 +1,131 @@
+package clustering
+
+import (
+   "math"
+   "math/rand"
+   "testing"
+
+   "github.com/eriq-augustine/goml/base"
+)
+
+type gaussianMixtureTestCase struct {
+   Name string
+   CovarianceType CovarianceType
+}
+
+func TestGaussianMixture(t *testing.T) {
+   base.Seed(13);
+   data, truth := blobs([][]float64{{0, 0}, {8, 8}, {-8, 8}}, 100, 1.0, 13);
+
+   var testCases []gaussianMixtureTestCase = []gaussianMixtureTestCase{
+      gaussianMixtureTestCase{"Full", GMM_COVARIANCE_FULL},
+      gaussianMixtureTestCase{"Diagonal", GMM_COVARIANCE_DIAGONAL},
+      gaussianMixtureTestCase{"Spherical", GMM_COVARIANCE_SPHERICAL},
+   };
+
+   for _, testCase := range(testCases) {
+      var mixture *GaussianMixture = NewGaussianMixture(3, testCase.CovarianceType, 0, 0, -1);
+      var labels []base.Feature = mixture.Cluster(data);
+
+      if (!sameClusters(truth, labels)) {
+         t.Errorf("(%s) -- Wrong clusters.", testCase.Name);
+      }
+
+      if (!mixture.Converged()) {
+         t.Errorf("(%s) -- Did not converge in %d iterations.", testCase.Name, mixture.Iterations());
+      }
+
+      var totalWeight float64 = 0;
+      for i, weight := range(mixture.Weights()) {
+         totalWeight += weight;
+         if (math.Abs(weight - 1.0 / 3.0) > 0.01) {
+            t.Errorf("(%s)[%d] -- Bad weight. Expected: %v, Got: %v", testCase.Name, i, 1.0 / 3.0, weight);
+         }
+      }
+
+      if (math.Abs(totalWeight - 1.0) > 1e-9) {
+         t.Errorf("(%s) -- Weights do not sum to one: %v", testCase.Name, totalWeight);
+      }
+
+      for i, responsibilities := range(mixture.Responsibilities(data)) {
+         var total float64 = 0;
+         for _, responsibility := range(responsibilities) {
+            total += responsibility;
+         }
+
+         if (math.Abs(total - 1.0) > 1e-9) {
+            t.Errorf("(%s)[%d] -- Responsibilities do not sum to one: %v", testCase.Name, i, total);
+            break;
+         }
+      }
+
+      var meanScore float64 = 0;
+      for _, score := range(mixture.ScoreSamples(data)) {
+         meanScore += score / float64(len(data));
+      }
+
+      if (math.Abs(meanScore - mixture.LogLikelihood()) > 1e-9) {
+         t.Errorf("(%s) -- Scores do not match the log-likelihood. Expected: %v, Got: %v", testCase.Name, mixture.LogLikelihood(), meanScore);
+      }
+   }
+}
+
+// A single full covariance component should recover the covariance of correlated data.
+func TestGaussianMixtureCovariance(t *testing.T) {
+   var random *rand.Rand = rand.New(rand.NewSource(14));
+
+   // x ~ N(0, 4), y = 0.375x + N(0, 0.4375): var(y) = 1, cov(x, y) = 1.5.
+   var data []base.NumericTuple = make([]base.NumericTuple, 5000);
+   for i, _ := range(data) {
+      var x float64 = random.NormFloat64() * 2.0;
+      var y float64 = 0.375 * x + random.NormFloat64() * math.Sqrt(0.4375);
+      data[i] = base.NewFloatTuple([]float64{x + 3, y - 1}, nil);
+   }
+
+   var mixture *GaussianMixture = NewGaussianMixture(1, GMM_COVARIANCE_FULL, 0, 0, 0);
+   mixture.Fit(data);
+
+   var expectedMean []float64 = []float64{3, -1};
+   var expectedCovariance [][]float64 = [][]float64{{4, 1.5}, {1.5, 1}};
+
+   for i, value := range(mixture.Means()[0]) {
+      if (math.Abs(value - expectedMean[i]) > 0.1) {
+         t.Errorf("[%d] -- Bad mean. Expected: %v, Got: %v", i, expectedMean[i], value);
+      }
+   }
+
+   for i, row := range(mixture.Covariances()[0]) {
+      for j, value := range(row) {
+         if (math.Abs(value - expectedCovariance[i][j]) > 0.15) {
+            t.Errorf("[%d][%d] -- Bad covariance. Expected: %v, Got: %v", i, j, expectedCovariance[i][j], value);
+         }
+      }
+   }
+}
+
+// BIC and AIC should prefer the true number of components.
+func TestGaussianMixtureModelSelection(t *testing.T) {
+   base.Seed(15);
+   data, _ := blobs([][]float64{{0, 0}, {6, 0}}, 150, 1.0, 15);
+
+   var bics []float64 = make([]float64, 0);
+   var aics []float64 = make([]float64, 0);
+   for k := 1; k <= 4; k++ {
+      var mixture *GaussianMixture = NewGaussianMixture(k, GMM_COVARIANCE_FULL, 0, 0, -1);
+      mixture.Fit(data);
+
+      bics = append(bics, mixture.BIC(data));
+      aics = append(aics, mixture.AIC(data));
+   }
+
+   for i, _ := range(bics) {
+      if (i != 1 && bics[i] <= bics[1]) {
+         t.Errorf("BIC did not choose two components. BICs: %v", bics);
+         break;
+      }
+   }
+
+   if (aics[0] <= aics[1]) {
+      t.Errorf("AIC chose one component over two. AICs: %v", aics);
+   }
+}
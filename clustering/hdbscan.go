@@ -0,0 +1,247 @@
+package clustering
+
+// Hierarchical DBSCAN (Campello, Moulavi, and Sander 2013).
+// Effectively runs DBSCAN over every epsilon at once and keeps the most stable clusters,
+// so clusters of different densities can be found and there is no epsilon to pick.
+//  1. The core distance of a tuple is the distance to its minSamples-th nearest tuple (counting itself).
+//  2. The mutual reachability distance between two tuples is max(core(a), core(b), distance(a, b)).
+//  3. Build the single linkage tree from the minimum spanning tree of the mutual reachability graph.
+//  4. Condense the tree: splits that leave a side with less than minClusterSize tuples are just
+//     tuples falling out of the cluster, not new clusters.
+//  5. Choose the clusters with the most stability (excess of mass), never choosing a cluster and its descendant.
+// Uses the full pairwise distance matrix, so memory is quadratic in the number of tuples.
+
+import (
+   "math"
+   "sort"
+
+   "github.com/eriq-augustine/goml/base"
+   "github.com/eriq-augustine/goml/util"
+)
+
+const (
+   HDBSCAN_DEFAULT_MIN_CLUSTER_SIZE = 5
+)
+
+type HDBSCAN struct {
+   minClusterSize int
+   minSamples int
+   distancer base.Distancer
+   numClusters int
+}
+
+// A cluster in the condensed tree.
+type condensedCluster struct {
+   // -1 for the root.
+   parent int
+   children []int
+   // Lambda (1 / distance) when the cluster split off its parent.
+   birth float64
+   stability float64
+}
+
+// Pass nil for |distancer| to get base.Euclidean.
+// Pass a non-positive value for |minClusterSize| (must be at least 2) to get the default.
+// Pass a non-positive value for |minSamples| to use |minClusterSize|.
+// Larger |minSamples| make the clustering more conservative (more tuples are noise).
+func NewHDBSCAN(minClusterSize int, minSamples int, distancer base.Distancer) *HDBSCAN {
+   if (minClusterSize <= 1) {
+      minClusterSize = HDBSCAN_DEFAULT_MIN_CLUSTER_SIZE;
+   }
+
+   if (minSamples <= 0) {
+      minSamples = minClusterSize;
+   }
+
+   if (distancer == nil) {
+      distancer = base.Euclidean{};
+   }
+
+   var hdbscan HDBSCAN = HDBSCAN{
+      minClusterSize: minClusterSize,
+      minSamples: minSamples,
+      distancer: distancer,
+   };
+
+   return &hdbscan;
+}
+
+// Labels are base.IntFeature, numbered from 0.
+// Noise is labeled with NOISE_LABEL.
+func (this *HDBSCAN) Cluster(data []base.NumericTuple) []base.Feature {
+   var assignments []int = make([]int, len(data));
+   for i, _ := range(assignments) {
+      assignments[i] = NOISE_LABEL;
+   }
+
+   this.numClusters = 0;
+   if (len(data) < this.minClusterSize) {
+      return clusterLabels(assignments);
+   }
+
+   var distances [][]float64 = pairwiseDistances(this.distancer, data);
+   var merges []linkageMerge = singleLinkage(mutualReachability(distances, this.minSamples));
+
+   // The cluster each tuple was last in and the clusters in the condensed tree.
+   var tupleClusters []int = make([]int, len(data));
+   var clusters []condensedCluster = []condensedCluster{condensedCluster{parent: -1, birth: 0}};
+   this.condense(merges, len(data) + len(merges) - 1, 0, tupleClusters, &clusters);
+
+   var selected []bool = selectClusters(clusters);
+
+   // Relabel the selected clusters from 0.
+   var labels []int = make([]int, len(clusters));
+   for i, _ := range(clusters) {
+      labels[i] = NOISE_LABEL;
+      if (selected[i]) {
+         labels[i] = this.numClusters;
+         this.numClusters++;
+      }
+   }
+
+   for i, cluster := range(tupleClusters) {
+      for ; cluster != -1; cluster = clusters[cluster].parent {
+         if (selected[cluster]) {
+            assignments[i] = labels[cluster];
+            break;
+         }
+      }
+   }
+
+   return clusterLabels(assignments);
+}
+
+// The number of clusters (not counting noise) found in the last call to Cluster().
+func (this HDBSCAN) NumClusters() int {
+   return this.numClusters;
+}
+
+// Walk down the single linkage tree from |node| which is a part of |cluster|.
+func (this HDBSCAN) condense(merges []linkageMerge, node int, cluster int, tupleClusters []int, clusters *[]condensedCluster) {
+   var numTuples int = len(merges) + 1;
+   var merge linkageMerge = merges[node - numTuples];
+   // Duplicate tuples merge at distance zero.
+   var lambda float64 = 1.0 / math.Max(merge.distance, util.EPSILON);
+
+   var leftSize int = linkageSize(merges, merge.left);
+   var rightSize int = linkageSize(merges, merge.right);
+
+   // No matter what happens, every tuple under this node leaves |cluster| here.
+   (*clusters)[cluster].stability += float64(merge.size) * (lambda - (*clusters)[cluster].birth);
+
+   if (leftSize >= this.minClusterSize && rightSize >= this.minClusterSize) {
+      for _, child := range([]int{merge.left, merge.right}) {
+         var childCluster int = len(*clusters);
+         *clusters = append(*clusters, condensedCluster{parent: cluster, birth: lambda});
+         (*clusters)[cluster].children = append((*clusters)[cluster].children, childCluster);
+         this.condense(merges, child, childCluster, tupleClusters, clusters);
+      }
+
+      return;
+   }
+
+   for _, child := range([]int{merge.left, merge.right}) {
+      if (linkageSize(merges, child) >= this.minClusterSize) {
+         // The cluster just shrank, it continues down this child.
+         // Undo the stability for the tuples that are still in it.
+         (*clusters)[cluster].stability -= float64(linkageSize(merges, child)) * (lambda - (*clusters)[cluster].birth);
+         this.condense(merges, child, cluster, tupleClusters, clusters);
+      } else {
+         for _, tuple := range(linkageTuples(merges, child)) {
+            tupleClusters[tuple] = cluster;
+         }
+      }
+   }
+}
+
+// Excess of mass: working up from the leaves, keep a cluster if it is more stable than its chosen descendants.
+// The root is never chosen.
+func selectClusters(clusters []condensedCluster) []bool {
+   var selected []bool = make([]bool, len(clusters));
+
+   // Children always come after their parents.
+   for i := len(clusters) - 1; i > 0; i-- {
+      var childStability float64 = 0;
+      for _, child := range(clusters[i].children) {
+         childStability += clusters[child].stability;
+      }
+
+      if (len(clusters[i].children) == 0 || clusters[i].stability >= childStability) {
+         selected[i] = true;
+         deselectDescendants(clusters, i, selected);
+      } else {
+         clusters[i].stability = childStability;
+      }
+   }
+
+   return selected;
+}
+
+func deselectDescendants(clusters []condensedCluster, cluster int, selected []bool) {
+   for _, child := range(clusters[cluster].children) {
+      selected[child] = false;
+      deselectDescendants(clusters, child, selected);
+   }
+}
+
+// The mutual reachability distance between every pair of tuples.
+func mutualReachability(distances [][]float64, minSamples int) [][]float64 {
+   var coreDistances []float64 = make([]float64, len(distances));
+   for i, row := range(distances) {
+      var sorted []float64 = append([]float64(nil), row...);
+      sort.Float64s(sorted);
+      coreDistances[i] = sorted[util.MinInt(minSamples, len(sorted)) - 1];
+   }
+
+   var reachability [][]float64 = make([][]float64, len(distances));
+   for i, row := range(distances) {
+      reachability[i] = make([]float64, len(row));
+      for j, distance := range(row) {
+         reachability[i][j] = math.Max(distance, math.Max(coreDistances[i], coreDistances[j]));
+      }
+   }
+
+   return reachability;
+}
+
+// Prim's algorithm for the minimum spanning tree over the complete graph,
+// and then the tree's edges are merged from shortest to longest.
+func singleLinkage(distances [][]float64) []linkageMerge {
+   var numTuples int = len(distances);
+
+   var inTree []bool = make([]bool, numTuples);
+   // The shortest edge from each tuple to the tree, and the tree tuple on the other end.
+   var bestDistances []float64 = make([]float64, numTuples);
+   var bestSources []int = make([]int, numTuples);
+   for i, _ := range(bestDistances) {
+      bestDistances[i] = math.Inf(1);
+   }
+
+   var edges []linkageMerge = make([]linkageMerge, 0, numTuples - 1);
+
+   var current int = 0;
+   inTree[current] = true;
+   for len(edges) < numTuples - 1 {
+      var next int = -1;
+      for i := 0; i < numTuples; i++ {
+         if (inTree[i]) {
+            continue;
+         }
+
+         if (distances[current][i] < bestDistances[i]) {
+            bestDistances[i] = distances[current][i];
+            bestSources[i] = current;
+         }
+
+         if (next == -1 || bestDistances[i] < bestDistances[next]) {
+            next = i;
+         }
+      }
+
+      edges = append(edges, linkageMerge{left: bestSources[next], right: next, distance: bestDistances[next]});
+      inTree[next] = true;
+      current = next;
+   }
+
+   return linkageTree(numTuples, edges);
+}
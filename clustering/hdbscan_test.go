@@ -0,0 +1,61 @@
+package clustering
+
+import (
+   "testing"
+
+   "github.com/eriq-augustine/goml/base"
+)
+
+func TestHDBSCAN(t *testing.T) {
+   data, truth := moons(100, [][]float64{{5, 5}, {-5, 5}, {0, -5}}, 9);
+
+   var testCases []densityTestCase = []densityTestCase{
+      densityTestCase{"Moons", NewHDBSCAN(10, 0, nil), 2},
+      densityTestCase{"Moons - Min Samples", NewHDBSCAN(10, 3, base.Euclidean{}), 2},
+      densityTestCase{"Moons - Manhattan", NewHDBSCAN(10, 5, base.Manhattan{}), 2},
+   };
+
+   for _, testCase := range(testCases) {
+      var labels []base.Feature = testCase.Clusterer.Cluster(data);
+      if (!sameClustersWithNoise(truth, labels)) {
+         t.Errorf("(%s) -- Wrong clusters: %v", testCase.Name, labels);
+      }
+
+      var numClusters int = testCase.Clusterer.(*HDBSCAN).NumClusters();
+      if (numClusters != testCase.ExpectedClusters) {
+         t.Errorf("(%s) -- Wrong number of clusters. Expected: %d, Got: %d", testCase.Name, testCase.ExpectedClusters, numClusters);
+      }
+   }
+}
+
+// A tight and a loose blob, no single DBSCAN epsilon fits both.
+func TestHDBSCANVaryingDensity(t *testing.T) {
+   tight, _ := blobs([][]float64{{0, 0}}, 60, 0.1, 10);
+   loose, _ := blobs([][]float64{{20, 0}}, 60, 2.0, 11);
+
+   var data []base.NumericTuple = append(tight, loose...);
+
+   var hdbscan *HDBSCAN = NewHDBSCAN(15, 5, nil);
+   var labels []base.Feature = hdbscan.Cluster(data);
+
+   if (hdbscan.NumClusters() != 2) {
+      t.Fatalf("Wrong number of clusters. Expected: 2, Got: %d", hdbscan.NumClusters());
+   }
+
+   var noise int = 0;
+   for i, label := range(labels) {
+      if (label == base.Int(NOISE_LABEL)) {
+         noise++;
+         continue;
+      }
+
+      if ((i < len(tight)) != (label == labels[0])) {
+         t.Errorf("[%d] -- Tuple is in the wrong cluster: %v", i, label);
+      }
+   }
+
+   // A few of the loose blob's outskirts may be noise.
+   if (noise > len(data) / 10) {
+      t.Errorf("Too much noise: %d", noise);
+   }
+}
@@ -0,0 +1,192 @@
+package clustering
+
+// k-means clustering with Lloyd's algorithm and k-means++ seeding.
+// Tuples are assigned to their closest centroid (by the distancer),
+// and centroids are moved to the mean of their tuples until they stop moving.
+// Note that the mean only minimizes squared Euclidean distance,
+// so other distances will work but may not converge as nicely.
+
+import (
+   "fmt"
+   "math"
+   "math/rand"
+
+   "github.com/eriq-augustine/goml/base"
+)
+
+const (
+   KMEANS_DEFAULT_MAX_ITERATIONS = 300
+   KMEANS_DEFAULT_NUM_RESTARTS = 10
+   KMEANS_DEFAULT_TOLERENCE = 1e-4
+)
+
+type KMeans struct {
+   k int
+   distancer base.Distancer
+   maxIterations int
+   // Each restart is seeded differently and the one with the lowest inertia is kept.
+   numRestarts int
+   // Stop when no centroid moves (by the distancer) more than this.
+   tolerence float64
+
+   centroids []base.NumericTuple
+   inertia float64
+   iterations int
+}
+
+// Pass nil for |distancer| to get base.Euclidean.
+// Pass a non-positive value for |maxIterations|, |numRestarts|, or |tolerence| to get the default.
+func NewKMeans(k int, distancer base.Distancer, maxIterations int, numRestarts int, tolerence float64) *KMeans {
+   if (k <= 0) {
+      panic("k must be >= 1");
+   }
+
+   if (distancer == nil) {
+      distancer = base.Euclidean{};
+   }
+
+   if (maxIterations <= 0) {
+      maxIterations = KMEANS_DEFAULT_MAX_ITERATIONS;
+   }
+
+   if (numRestarts <= 0) {
+      numRestarts = KMEANS_DEFAULT_NUM_RESTARTS;
+   }
+
+   if (tolerence <= 0) {
+      tolerence = KMEANS_DEFAULT_TOLERENCE;
+   }
+
+   var kMeans KMeans = KMeans{
+      k: k,
+      distancer: distancer,
+      maxIterations: maxIterations,
+      numRestarts: numRestarts,
+      tolerence: tolerence,
+      centroids: nil,
+   };
+
+   return &kMeans;
+}
+
+// Labels are base.IntFeature, the index of the cluster's centroid.
+func (this *KMeans) Cluster(data []base.NumericTuple) []base.Feature {
+   if (len(data) < this.k) {
+      panic(fmt.Sprintf("Need at least k (%d) tuples to cluster, got %d.", this.k, len(data)));
+   }
+
+   var random *rand.Rand = base.NewRandom();
+
+   var bestAssignments []int = nil;
+   this.inertia = math.Inf(1);
+
+   for restart := 0; restart < this.numRestarts; restart++ {
+      centroids, assignments, inertia, iterations := this.lloyd(data, kMeansPlusPlus(this.distancer, data, this.k, random));
+
+      if (inertia < this.inertia) {
+         this.centroids = centroids;
+         this.inertia = inertia;
+         this.iterations = iterations;
+         bestAssignments = assignments;
+      }
+   }
+
+   return clusterLabels(bestAssignments);
+}
+
+// Label each tuple with its closest centroid.
+func (this KMeans) Predict(data []base.NumericTuple) []base.Feature {
+   if (this.centroids == nil) {
+      panic("KMeans must be clustered before predicting.");
+   }
+
+   var assignments []int = make([]int, len(data));
+   for i, tuple := range(data) {
+      assignments[i], _ = nearestCentroid(this.distancer, this.centroids, tuple);
+   }
+
+   return clusterLabels(assignments);
+}
+
+// Returns the centroids, the cluster of each tuple, the inertia, and the number of iterations.
+func (this KMeans) lloyd(data []base.NumericTuple, centroids []base.NumericTuple) ([]base.NumericTuple, []int, float64, int) {
+   var assignments []int = make([]int, len(data));
+   var distances []float64 = make([]float64, len(data));
+   var numFeatures int = data[0].DataSize();
+
+   var iteration int = 0;
+   for iteration < this.maxIterations {
+      iteration++;
+
+      for i, tuple := range(data) {
+         assignments[i], distances[i] = nearestCentroid(this.distancer, centroids, tuple);
+      }
+
+      var sums [][]float64 = make([][]float64, this.k);
+      var counts []int = make([]int, this.k);
+      for i, _ := range(sums) {
+         sums[i] = make([]float64, numFeatures);
+      }
+
+      for i, tuple := range(data) {
+         counts[assignments[i]]++;
+         for j := 0; j < numFeatures; j++ {
+            sums[assignments[i]][j] += tuple.GetNumericData(j);
+         }
+      }
+
+      var maxShift float64 = 0;
+      for cluster, _ := range(centroids) {
+         var newCentroid base.NumericTuple;
+
+         if (counts[cluster] == 0) {
+            // Empty cluster, move it to the tuple that is the furthest from its own centroid.
+            var furthest int = 0;
+            for i, distance := range(distances) {
+               if (distance > distances[furthest]) {
+                  furthest = i;
+               }
+            }
+
+            newCentroid = copyTuple(data[furthest]);
+            distances[furthest] = 0;
+         } else {
+            for j, _ := range(sums[cluster]) {
+               sums[cluster][j] /= float64(counts[cluster]);
+            }
+            newCentroid = base.NewFloatTuple(sums[cluster], nil);
+         }
+
+         maxShift = math.Max(maxShift, this.distancer.Distance(centroids[cluster], newCentroid));
+         centroids[cluster] = newCentroid;
+      }
+
+      if (maxShift <= this.tolerence) {
+         break;
+      }
+   }
+
+   // Final assignments for the final centroids.
+   var inertia float64 = 0;
+   for i, tuple := range(data) {
+      assignments[i], distances[i] = nearestCentroid(this.distancer, centroids, tuple);
+      inertia += distances[i] * distances[i];
+   }
+
+   return centroids, assignments, inertia, iteration;
+}
+
+// Indexed by cluster label.
+func (this KMeans) Centroids() [][]float64 {
+   return centroidSlices(this.centroids);
+}
+
+// The sum of squared distances from each tuple to its closest centroid (from the last call to Cluster()).
+func (this KMeans) Inertia() float64 {
+   return this.inertia;
+}
+
+// The number of iterations used by the kept restart in the last call to Cluster().
+func (this KMeans) Iterations() int {
+   return this.iterations;
+}
@@ -0,0 +1,161 @@
+package clustering
+
+import (
+   "math"
+   "math/rand"
+   "testing"
+
+   "github.com/eriq-augustine/goml/base"
+)
+
+type kMeansTestCase struct {
+   Name string
+   K int
+   Distancer base.Distancer
+   Centers [][]float64
+   PointsPerCenter int
+}
+
+// Gaussian blobs around each center.
+// Returns the tuples and the index of the center each was generated from.
+func blobs(centers [][]float64, pointsPerCenter int, stddev float64, seed int64) ([]base.NumericTuple, []int) {
+   var random *rand.Rand = rand.New(rand.NewSource(seed));
+
+   var data []base.NumericTuple = make([]base.NumericTuple, 0, len(centers) * pointsPerCenter);
+   var truth []int = make([]int, 0, len(centers) * pointsPerCenter);
+
+   for i := 0; i < pointsPerCenter; i++ {
+      for center, mean := range(centers) {
+         var point []float64 = make([]float64, len(mean));
+         for j, _ := range(point) {
+            point[j] = mean[j] + random.NormFloat64() * stddev;
+         }
+
+         data = append(data, base.NewFloatTuple(point, nil));
+         truth = append(truth, center);
+      }
+   }
+
+   return data, truth;
+}
+
+// True if the labels split the tuples exactly the same way as |truth| (up to renaming).
+func sameClusters(truth []int, labels []base.Feature) bool {
+   var truthToLabel map[int]base.Feature = make(map[int]base.Feature);
+   var labelToTruth map[base.Feature]int = make(map[base.Feature]int);
+
+   for i, label := range(labels) {
+      mappedLabel, ok := truthToLabel[truth[i]];
+      if (ok && mappedLabel != label) {
+         return false;
+      }
+
+      mappedTruth, ok := labelToTruth[label];
+      if (ok && mappedTruth != truth[i]) {
+         return false;
+      }
+
+      truthToLabel[truth[i]] = label;
+      labelToTruth[label] = truth[i];
+   }
+
+   return true;
+}
+
+func TestKMeans(t *testing.T) {
+   var testCases []kMeansTestCase = []kMeansTestCase{
+      kMeansTestCase{"Two", 2, nil, [][]float64{{0, 0}, {10, 10}}, 50},
+      kMeansTestCase{"Three", 3, base.Euclidean{}, [][]float64{{0, 0}, {10, 0}, {0, 10}}, 50},
+      kMeansTestCase{"Manhattan", 3, base.Manhattan{}, [][]float64{{0, 0, 0}, {10, 0, 0}, {0, 0, 10}}, 50},
+      kMeansTestCase{"One", 1, nil, [][]float64{{5, 5}}, 20},
+   };
+
+   for _, testCase := range(testCases) {
+      base.Seed(4);
+      data, truth := blobs(testCase.Centers, testCase.PointsPerCenter, 1.0, 4);
+
+      var kMeans *KMeans = NewKMeans(testCase.K, testCase.Distancer, 0, 0, 0);
+      var labels []base.Feature = kMeans.Cluster(data);
+
+      if (!sameClusters(truth, labels)) {
+         t.Errorf("(%s) -- Wrong clusters: %v", testCase.Name, labels);
+      }
+
+      var predicted []base.Feature = kMeans.Predict(data);
+      for i, _ := range(labels) {
+         if (labels[i] != predicted[i]) {
+            t.Errorf("(%s)[%d] -- Prediction does not match the cluster. Expected: %v, Got: %v", testCase.Name, i, labels[i], predicted[i]);
+         }
+      }
+
+      // The centroids should be near the true centers.
+      var centroids [][]float64 = kMeans.Centroids();
+      for i, label := range(labels) {
+         var centroid []float64 = centroids[label.(base.IntFeature).IntValue()];
+         for j, value := range(centroid) {
+            if (math.Abs(value - testCase.Centers[truth[i]][j]) > 1.0) {
+               t.Errorf("(%s) -- Centroid is far from the true center. Centroid: %v, Center: %v", testCase.Name, centroid, testCase.Centers[truth[i]]);
+               break;
+            }
+         }
+      }
+
+      var inertia float64 = 0;
+      for i, tuple := range(data) {
+         var distance float64 = kMeans.distancer.Distance(base.NewFloatTuple(centroids[labels[i].(base.IntFeature).IntValue()], nil), tuple);
+         inertia += distance * distance;
+      }
+
+      if (math.Abs(inertia - kMeans.Inertia()) > 1e-9 * inertia) {
+         t.Errorf("(%s) -- Bad inertia. Expected: %v, Got: %v", testCase.Name, inertia, kMeans.Inertia());
+      }
+   }
+}
+
+// More restarts can only help and the same seed gives the same clusters.
+func TestKMeansRestarts(t *testing.T) {
+   data, _ := blobs([][]float64{{0, 0}, {3, 0}, {0, 3}, {3, 3}, {6, 6}}, 30, 1.0, 5);
+
+   base.Seed(5);
+   var single *KMeans = NewKMeans(5, nil, 0, 1, 0);
+   var singleLabels []base.Feature = single.Cluster(data);
+
+   base.Seed(5);
+   var repeat *KMeans = NewKMeans(5, nil, 0, 1, 0);
+   var repeatLabels []base.Feature = repeat.Cluster(data);
+
+   for i, _ := range(singleLabels) {
+      if (singleLabels[i] != repeatLabels[i]) {
+         t.Fatalf("[%d] -- Same seed gave different clusters.", i);
+      }
+   }
+
+   base.Seed(5);
+   var multiple *KMeans = NewKMeans(5, nil, 0, 20, 0);
+   multiple.Cluster(data);
+
+   if (multiple.Inertia() > single.Inertia()) {
+      t.Errorf("More restarts gave a worse inertia. Single: %v, Multiple: %v", single.Inertia(), multiple.Inertia());
+   }
+}
+
+// Duplicate points can leave a cluster empty.
+func TestKMeansDuplicates(t *testing.T) {
+   var data []base.NumericTuple = []base.NumericTuple{
+      base.NewFloatTuple([]float64{1, 1}, nil),
+      base.NewFloatTuple([]float64{1, 1}, nil),
+      base.NewFloatTuple([]float64{1, 1}, nil),
+      base.NewFloatTuple([]float64{5, 5}, nil),
+   };
+
+   var kMeans *KMeans = NewKMeans(3, nil, 0, 0, 0);
+   var labels []base.Feature = kMeans.Cluster(data);
+
+   if (labels[0] != labels[1] || labels[0] != labels[2] || labels[0] == labels[3]) {
+      t.Errorf("Wrong clusters: %v", labels);
+   }
+
+   if (kMeans.Inertia() != 0) {
+      t.Errorf("Expected zero inertia, got: %v", kMeans.Inertia());
+   }
+}
@@ -0,0 +1,212 @@
+package clustering
+
+// Mini-batch k-means (Sculley 2010).
+// Instead of a full pass over the data per step, each step only looks at a small batch of tuples.
+// Each centroid is moved towards the tuples assigned to it with a per-centroid learning rate of
+// 1 / (the number of tuples it has ever been assigned), so it is a running mean of its tuples.
+// Use PartialFit() to stream data that does not fit in memory.
+
+import (
+   "fmt"
+   "math"
+   "math/rand"
+
+   "github.com/eriq-augustine/goml/base"
+   "github.com/eriq-augustine/goml/util"
+)
+
+const (
+   MINI_BATCH_KMEANS_DEFAULT_BATCH_SIZE = 1024
+   MINI_BATCH_KMEANS_DEFAULT_MAX_ITERATIONS = 100
+   MINI_BATCH_KMEANS_DEFAULT_TOLERENCE = 1e-4
+   // k-means++ is seeded on a sample of this many batches.
+   MINI_BATCH_KMEANS_INIT_BATCHES = 3
+)
+
+type MiniBatchKMeans struct {
+   k int
+   distancer base.Distancer
+   batchSize int
+   // The maximum number of full passes (over shuffled batches) that Cluster() makes.
+   maxIterations int
+   // Cluster() stops when no centroid moves (by the distancer) more than this in a full pass.
+   tolerence float64
+   random *rand.Rand
+
+   centroids []base.NumericTuple
+   // The number of tuples each centroid has been assigned.
+   counts []int
+   inertia float64
+   iterations int
+}
+
+// Pass nil for |distancer| to get base.Euclidean.
+// Pass a non-positive value for |batchSize|, |maxIterations|, or |tolerence| to get the default.
+func NewMiniBatchKMeans(k int, distancer base.Distancer, batchSize int, maxIterations int, tolerence float64) *MiniBatchKMeans {
+   if (k <= 0) {
+      panic("k must be >= 1");
+   }
+
+   if (distancer == nil) {
+      distancer = base.Euclidean{};
+   }
+
+   if (batchSize <= 0) {
+      batchSize = MINI_BATCH_KMEANS_DEFAULT_BATCH_SIZE;
+   }
+
+   if (maxIterations <= 0) {
+      maxIterations = MINI_BATCH_KMEANS_DEFAULT_MAX_ITERATIONS;
+   }
+
+   if (tolerence <= 0) {
+      tolerence = MINI_BATCH_KMEANS_DEFAULT_TOLERENCE;
+   }
+
+   var kMeans MiniBatchKMeans = MiniBatchKMeans{
+      k: k,
+      distancer: distancer,
+      batchSize: batchSize,
+      maxIterations: maxIterations,
+      tolerence: tolerence,
+      random: base.NewRandom(),
+      centroids: nil,
+      counts: nil,
+   };
+
+   return &kMeans;
+}
+
+// Start over and cluster all of |data|.
+// Labels are base.IntFeature, the index of the cluster's centroid.
+func (this *MiniBatchKMeans) Cluster(data []base.NumericTuple) []base.Feature {
+   this.centroids = nil;
+   this.init(data);
+
+   var points []int = util.RangeSlice(len(data));
+
+   this.iterations = 0;
+   for this.iterations < this.maxIterations {
+      this.iterations++;
+
+      var previous []base.NumericTuple = append([]base.NumericTuple(nil), this.centroids...);
+
+      this.random.Shuffle(len(points), func(i int, j int) {
+         points[i], points[j] = points[j], points[i];
+      });
+
+      for batch := 0; batch < int(math.Ceil(float64(len(points)) / float64(this.batchSize))); batch++ {
+         var batchStart int = batch * this.batchSize;
+         var batchEnd int = util.MinInt(len(points), ((batch + 1) * this.batchSize));
+         this.update(base.SelectNumericTuples(data, points[batchStart:batchEnd]));
+      }
+
+      var maxShift float64 = 0;
+      for i, centroid := range(this.centroids) {
+         maxShift = math.Max(maxShift, this.distancer.Distance(previous[i], centroid));
+      }
+
+      if (maxShift <= this.tolerence) {
+         break;
+      }
+   }
+
+   var assignments []int = make([]int, len(data));
+   this.inertia = 0;
+   for i, tuple := range(data) {
+      var distance float64;
+      assignments[i], distance = nearestCentroid(this.distancer, this.centroids, tuple);
+      this.inertia += distance * distance;
+   }
+
+   return clusterLabels(assignments);
+}
+
+// Update the centroids with one more chunk of data (eg from a stream or a file too large for memory).
+// The first chunk must have at least k tuples, it is used to seed the centroids.
+// The chunk is split into batches of the batch size.
+func (this *MiniBatchKMeans) PartialFit(data []base.NumericTuple) {
+   if (this.centroids == nil) {
+      this.init(data);
+   }
+
+   for batchStart := 0; batchStart < len(data); batchStart += this.batchSize {
+      this.update(data[batchStart:util.MinInt(len(data), batchStart + this.batchSize)]);
+   }
+}
+
+// Label each tuple with its closest centroid.
+func (this MiniBatchKMeans) Predict(data []base.NumericTuple) []base.Feature {
+   if (this.centroids == nil) {
+      panic("MiniBatchKMeans must be clustered (or partially fit) before predicting.");
+   }
+
+   var assignments []int = make([]int, len(data));
+   for i, tuple := range(data) {
+      assignments[i], _ = nearestCentroid(this.distancer, this.centroids, tuple);
+   }
+
+   return clusterLabels(assignments);
+}
+
+// Seed the centroids with k-means++ on a random sample of |data|.
+func (this *MiniBatchKMeans) init(data []base.NumericTuple) {
+   if (len(data) < this.k) {
+      panic(fmt.Sprintf("Need at least k (%d) tuples to seed the centroids, got %d.", this.k, len(data)));
+   }
+
+   var sample []base.NumericTuple = data;
+   var sampleSize int = util.MaxInt(this.k, MINI_BATCH_KMEANS_INIT_BATCHES * this.batchSize);
+   if (len(data) > sampleSize) {
+      sample = base.SelectNumericTuples(data, this.random.Perm(len(data))[:sampleSize]);
+   }
+
+   this.centroids = kMeansPlusPlus(this.distancer, sample, this.k, this.random);
+   this.counts = make([]int, this.k);
+}
+
+// Assign the whole batch with the current centroids, then move each centroid towards its tuples.
+func (this *MiniBatchKMeans) update(batch []base.NumericTuple) {
+   var assignments []int = make([]int, len(batch));
+   for i, tuple := range(batch) {
+      assignments[i], _ = nearestCentroid(this.distancer, this.centroids, tuple);
+   }
+
+   // Only the changed centroids get new tuples.
+   var centroids [][]float64 = make([][]float64, this.k);
+
+   for i, tuple := range(batch) {
+      var cluster int = assignments[i];
+      if (centroids[cluster] == nil) {
+         centroids[cluster] = this.centroids[cluster].ToFloatSlice();
+      }
+
+      this.counts[cluster]++;
+      var learningRate float64 = 1.0 / float64(this.counts[cluster]);
+
+      for j, _ := range(centroids[cluster]) {
+         centroids[cluster][j] += learningRate * (tuple.GetNumericData(j) - centroids[cluster][j]);
+      }
+   }
+
+   for cluster, centroid := range(centroids) {
+      if (centroid != nil) {
+         this.centroids[cluster] = base.NewFloatTuple(centroid, nil);
+      }
+   }
+}
+
+// Indexed by cluster label.
+func (this MiniBatchKMeans) Centroids() [][]float64 {
+   return centroidSlices(this.centroids);
+}
+
+// The sum of squared distances from each tuple to its closest centroid (from the last call to Cluster()).
+func (this MiniBatchKMeans) Inertia() float64 {
+   return this.inertia;
+}
+
+// The number of full passes made in the last call to Cluster().
+func (this MiniBatchKMeans) Iterations() int {
+   return this.iterations;
+}
@@ -0,0 +1,58 @@
+package clustering
+
+import (
+   "testing"
+
+   "github.com/eriq-augustine/goml/base"
+)
+
+type miniBatchKMeansTestCase struct {
+   Name string
+   K int
+   BatchSize int
+   Centers [][]float64
+   PointsPerCenter int
+}
+
+func TestMiniBatchKMeans(t *testing.T) {
+   var testCases []miniBatchKMeansTestCase = []miniBatchKMeansTestCase{
+      miniBatchKMeansTestCase{"Two", 2, 16, [][]float64{{0, 0}, {10, 10}}, 200},
+      miniBatchKMeansTestCase{"Three", 3, 32, [][]float64{{0, 0}, {10, 0}, {0, 10}}, 200},
+      miniBatchKMeansTestCase{"Default Batch", 3, 0, [][]float64{{0, 0, 0}, {10, 0, 0}, {0, 0, 10}}, 100},
+   };
+
+   for _, testCase := range(testCases) {
+      base.Seed(6);
+      data, truth := blobs(testCase.Centers, testCase.PointsPerCenter, 1.0, 6);
+
+      var miniBatch *MiniBatchKMeans = NewMiniBatchKMeans(testCase.K, nil, testCase.BatchSize, 0, 0);
+      var labels []base.Feature = miniBatch.Cluster(data);
+
+      if (!sameClusters(truth, labels)) {
+         t.Errorf("(%s) -- Wrong clusters.", testCase.Name);
+      }
+
+      // Should be about as good as full k-means.
+      var kMeans *KMeans = NewKMeans(testCase.K, nil, 0, 0, 0);
+      kMeans.Cluster(data);
+
+      if (miniBatch.Inertia() > 1.05 * kMeans.Inertia()) {
+         t.Errorf("(%s) -- Inertia is much worse than k-means. Mini-batch: %v, k-means: %v", testCase.Name, miniBatch.Inertia(), kMeans.Inertia());
+      }
+   }
+}
+
+// Streaming the data in chunks should find the same clusters.
+func TestMiniBatchKMeansPartialFit(t *testing.T) {
+   base.Seed(7);
+   data, truth := blobs([][]float64{{0, 0}, {10, 0}, {0, 10}, {10, 10}}, 250, 1.0, 7);
+
+   var miniBatch *MiniBatchKMeans = NewMiniBatchKMeans(4, nil, 50, 0, 0);
+   for chunkStart := 0; chunkStart < len(data); chunkStart += 200 {
+      miniBatch.PartialFit(data[chunkStart:chunkStart + 200]);
+   }
+
+   if (!sameClusters(truth, miniBatch.Predict(data))) {
+      t.Errorf("Wrong clusters after partial fits. Centroids: %v", miniBatch.Centroids());
+   }
+}
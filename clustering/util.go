@@ -0,0 +1,177 @@
+package clustering
+
+import (
+   "math"
+   "math/rand"
+
+   "github.com/eriq-augustine/goml/base"
+   "github.com/eriq-augustine/goml/util"
+)
+
+const (
+   // Each row is a full pass over the data, so not many are needed to be worth a worker.
+   MIN_ROWS_PER_WORKER = 16
+)
+
+// A computed row of a (pairwise) distance computation.
+type distanceRow struct {
+   index int
+   values []float64
+   neighbors []int
+}
+
+// The distance between every pair of tuples.
+// Rows are computed in parallel.
+func pairwiseDistances(distancer base.Distancer, data []base.NumericTuple) [][]float64 {
+   var distances [][]float64 = make([][]float64, len(data));
+
+   var rows chan distanceRow = computeRows(distancer, data, -1);
+   for i := 0; i < len(data); i++ {
+      var row distanceRow = <-rows;
+      distances[row.index] = row.values;
+   }
+   close(rows);
+
+   return distances;
+}
+
+// The indexes of all the tuples within |radius| of each tuple (including itself), in increasing order.
+// Rows are computed in parallel.
+func radiusNeighbors(distancer base.Distancer, data []base.NumericTuple, radius float64) [][]int {
+   var neighbors [][]int = make([][]int, len(data));
+
+   var rows chan distanceRow = computeRows(distancer, data, radius);
+   for i := 0; i < len(data); i++ {
+      var row distanceRow = <-rows;
+      neighbors[row.index] = row.neighbors;
+   }
+   close(rows);
+
+   return neighbors;
+}
+
+// Start workers that compute a row for each tuple.
+// A negative |radius| gets full rows of distances, otherwise only the neighbors are kept.
+// The caller must read len(data) rows and then close the channel.
+func computeRows(distancer base.Distancer, data []base.NumericTuple, radius float64) chan distanceRow {
+   var numWorkers int = util.MinInt(util.MaxInt(1, len(data) / MIN_ROWS_PER_WORKER), base.GetMaxProcs());
+   var rowsPerWorker int = int(math.Ceil(float64(len(data)) / float64(numWorkers)));
+
+   var rows chan distanceRow = make(chan distanceRow, len(data));
+
+   for worker := 0; worker < numWorkers; worker++ {
+      go rowWorker(rows, distancer, data, radius, worker * rowsPerWorker, rowsPerWorker);
+   }
+
+   return rows;
+}
+
+// startIndex + |numberOfRows| may be more than |data| (if work did not divide evenly).
+func rowWorker(rows chan<-distanceRow, distancer base.Distancer, data []base.NumericTuple,
+               radius float64, startIndex int, numberOfRows int) {
+   for i := startIndex; (i - startIndex) < numberOfRows && i < len(data); i++ {
+      var row distanceRow = distanceRow{index: i};
+
+      if (radius < 0) {
+         row.values = make([]float64, len(data));
+      } else {
+         row.neighbors = make([]int, 0);
+      }
+
+      for j, tuple := range(data) {
+         var distance float64 = distancer.Distance(data[i], tuple);
+
+         if (radius < 0) {
+            row.values[j] = distance;
+         } else if (distance <= radius) {
+            row.neighbors = append(row.neighbors, j);
+         }
+      }
+
+      rows <- row;
+   }
+}
+
+// The index of the closest centroid (ties go to the lowest index) and the distance to it.
+func nearestCentroid(distancer base.Distancer, centroids []base.NumericTuple, tuple base.NumericTuple) (int, float64) {
+   var bestIndex int = -1;
+   var bestDistance float64 = math.Inf(1);
+
+   for i, centroid := range(centroids) {
+      var distance float64 = distancer.Distance(centroid, tuple);
+      if (distance < bestDistance) {
+         bestIndex = i;
+         bestDistance = distance;
+      }
+   }
+
+   return bestIndex, bestDistance;
+}
+
+// k-means++ seeding (Arthur and Vassilvitskii 2007).
+// The first centroid is a random tuple, every other centroid is a tuple chosen
+// with probability proportional to its squared distance from the closest centroid so far.
+func kMeansPlusPlus(distancer base.Distancer, data []base.NumericTuple, k int, random *rand.Rand) []base.NumericTuple {
+   var centroids []base.NumericTuple = make([]base.NumericTuple, 0, k);
+   centroids = append(centroids, copyTuple(data[random.Intn(len(data))]));
+
+   // The squared distance from each tuple to its closest centroid.
+   var distances []float64 = make([]float64, len(data));
+   for i, tuple := range(data) {
+      var distance float64 = distancer.Distance(centroids[0], tuple);
+      distances[i] = distance * distance;
+   }
+
+   for len(centroids) < k {
+      var total float64 = 0;
+      for _, distance := range(distances) {
+         total += distance;
+      }
+
+      // All the points are already centroids, just pick any.
+      var chosen int = random.Intn(len(data));
+      if (total > 0) {
+         var target float64 = random.Float64() * total;
+         for i, distance := range(distances) {
+            target -= distance;
+            if (target < 0) {
+               chosen = i;
+               break;
+            }
+         }
+      }
+
+      var centroid base.NumericTuple = copyTuple(data[chosen]);
+      centroids = append(centroids, centroid);
+
+      for i, tuple := range(data) {
+         var distance float64 = distancer.Distance(centroid, tuple);
+         distances[i] = math.Min(distances[i], distance * distance);
+      }
+   }
+
+   return centroids;
+}
+
+// A FloatTuple (without a class) with the same data as |tuple|.
+func copyTuple(tuple base.NumericTuple) base.NumericTuple {
+   return base.NewFloatTuple(tuple.ToFloatSlice(), nil);
+}
+
+func clusterLabels(assignments []int) []base.Feature {
+   var labels []base.Feature = make([]base.Feature, len(assignments));
+   for i, cluster := range(assignments) {
+      labels[i] = base.Int(cluster);
+   }
+
+   return labels;
+}
+
+func centroidSlices(centroids []base.NumericTuple) [][]float64 {
+   var rtn [][]float64 = make([][]float64, len(centroids));
+   for i, centroid := range(centroids) {
+      rtn[i] = centroid.ToFloatSlice();
+   }
+
+   return rtn;
+}
@@ -0,0 +1,180 @@
+package features
+
+// Shared pieces for the categorical encoders (OneHotEncoder and OrdinalEncoder).
+
+import (
+   "fmt"
+   "sort"
+
+   "github.com/eriq-augustine/goml/base"
+)
+
+// What to do with a category that was not seen during fitting.
+type UnknownCategory int
+
+const (
+   // Panic.
+   ENCODER_UNKNOWN_ERROR UnknownCategory = iota
+   // One-hot: all the column's indicators are zero. Ordinal: the code is -1.
+   ENCODER_UNKNOWN_IGNORE
+)
+
+const (
+   // The category name used for the bucket of infrequent categories (see maxCategories).
+   ENCODER_INFREQUENT_NAME = "infrequent"
+   ENCODER_UNKNOWN_CODE = -1
+)
+
+// The categories learned for one column.
+type categoryVocabulary struct {
+   // In output order (sorted by their string value), not including the infrequent ones.
+   categories []base.Feature
+   indexes map[base.Feature]int
+   // Categories that were seen, but folded into the infrequent bucket.
+   infrequent map[base.Feature]bool
+}
+
+// Learn the categories in |column|.
+// If |maxCategories| is positive and there are more categories than that,
+// then only the (maxCategories - 1) most frequent are kept and the rest share an infrequent bucket.
+func newCategoryVocabulary(tuples []base.Tuple, column int, maxCategories int) *categoryVocabulary {
+   var counts map[base.Feature]int = make(map[base.Feature]int);
+   var categories []base.Feature = make([]base.Feature, 0);
+
+   for _, tuple := range(tuples) {
+      var value base.Feature = tuple.GetData(column);
+      if (counts[value] == 0) {
+         categories = append(categories, value);
+      }
+      counts[value]++;
+   }
+
+   sortCategories(categories);
+
+   var infrequent map[base.Feature]bool = make(map[base.Feature]bool);
+   if (maxCategories > 0 && len(categories) > maxCategories) {
+      var byCount []base.Feature = append([]base.Feature(nil), categories...);
+      sort.SliceStable(byCount, func(i int, j int) bool {
+         return counts[byCount[i]] > counts[byCount[j]];
+      });
+
+      for _, category := range(byCount[maxCategories - 1:]) {
+         infrequent[category] = true;
+      }
+
+      var kept []base.Feature = make([]base.Feature, 0, maxCategories - 1);
+      for _, category := range(categories) {
+         if (!infrequent[category]) {
+            kept = append(kept, category);
+         }
+      }
+      categories = kept;
+   }
+
+   var indexes map[base.Feature]int = make(map[base.Feature]int);
+   for i, category := range(categories) {
+      indexes[category] = i;
+   }
+
+   var vocabulary categoryVocabulary = categoryVocabulary{
+      categories: categories,
+      indexes: indexes,
+      infrequent: infrequent,
+   };
+
+   return &vocabulary;
+}
+
+// The number of codes: one per category plus one for the infrequent bucket (if there is one).
+func (this categoryVocabulary) size() int {
+   if (len(this.infrequent) > 0) {
+      return len(this.categories) + 1;
+   }
+
+   return len(this.categories);
+}
+
+// Get the code for |value|, or false if it was never seen.
+// Infrequent categories all share the last code.
+func (this categoryVocabulary) code(value base.Feature) (int, bool) {
+   index, ok := this.indexes[value];
+   if (ok) {
+      return index, true;
+   }
+
+   if (this.infrequent[value]) {
+      return len(this.categories), true;
+   }
+
+   return ENCODER_UNKNOWN_CODE, false;
+}
+
+// The display names of all the codes (in code order).
+func (this categoryVocabulary) names() []string {
+   var names []string = make([]string, 0, this.size());
+   for _, category := range(this.categories) {
+      names = append(names, fmt.Sprintf("%v", category.Value()));
+   }
+
+   if (len(this.infrequent) > 0) {
+      names = append(names, ENCODER_INFREQUENT_NAME);
+   }
+
+   return names;
+}
+
+// Sort by string value (then by type) so that codes do not depend on the order of the training data.
+func sortCategories(categories []base.Feature) {
+   sort.SliceStable(categories, func(i int, j int) bool {
+      var a string = fmt.Sprintf("%v", categories[i].Value());
+      var b string = fmt.Sprintf("%v", categories[j].Value());
+      if (a != b) {
+         return a < b;
+      }
+
+      return fmt.Sprintf("%T", categories[i]) < fmt.Sprintf("%T", categories[j]);
+   });
+}
+
+// If |columns| is nil, then find every column that holds a StringFeature or BoolFeature.
+// Otherwise, check the given columns.
+func categoricalColumns(tuples []base.Tuple, columns []int) []int {
+   if (columns != nil) {
+      for _, column := range(columns) {
+         if (column < 0 || column >= tuples[0].DataSize()) {
+            panic(fmt.Sprintf("Bad categorical column: %d", column));
+         }
+      }
+
+      return append([]int(nil), columns...);
+   }
+
+   var isCategorical []bool = make([]bool, tuples[0].DataSize());
+   for _, tuple := range(tuples) {
+      for i, _ := range(isCategorical) {
+         switch tuple.GetData(i).(type) {
+         case base.StringFeature, base.BoolFeature:
+            isCategorical[i] = true;
+         }
+      }
+   }
+
+   columns = make([]int, 0);
+   for i, categorical := range(isCategorical) {
+      if (categorical) {
+         columns = append(columns, i);
+      }
+   }
+
+   return columns;
+}
+
+// The numeric value of a column that is not being encoded.
+func passThroughValue(tuple base.Tuple, column int) base.NumericFeature {
+   value, ok := tuple.GetData(column).(base.NumericFeature);
+   if (!ok) {
+      panic(fmt.Sprintf("Column %d is not being encoded, so it must be numeric. Found: %v (%T)", column, tuple.GetData(column), tuple.GetData(column)));
+   }
+
+   return value;
+}
@@ -0,0 +1,324 @@
+package features
+
+// Fill in missing values (base.NilFeature or NaN) with values learned from the training tuples.
+// Non-missing values must be numeric (see OneHotEncoder and OrdinalEncoder for categorical columns).
+// Always produces FloatTuples, optionally with an extra indicator (0/1) column for each input column
+// that had missing values in the training data.
+
+import (
+   "fmt"
+   "math"
+   "sort"
+
+   "github.com/eriq-augustine/goml/base"
+   "github.com/eriq-augustine/goml/util"
+)
+
+type Imputation int
+
+const (
+   // The mean of the non-missing training values.
+   IMPUTE_MEAN Imputation = iota
+   // The median of the non-missing training values.
+   IMPUTE_MEDIAN
+   // The most common non-missing training value (the smallest on ties).
+   IMPUTE_MOST_FREQUENT
+   // A fixed value.
+   IMPUTE_CONSTANT
+   // The mean value of the k nearest training tuples that have the value.
+   // Distances only use the features that both tuples have (scaled up to make up for the missing ones).
+   IMPUTE_KNN
+)
+
+const (
+   IMPUTER_DEFAULT_K = 5
+)
+
+type Imputer struct {
+   imputation Imputation
+   addIndicators bool
+   // Only used for IMPUTE_CONSTANT.
+   fillValue float64
+   // Only used for IMPUTE_KNN.
+   k int
+
+   // The fill value for every feature (for IMPUTE_KNN, the fallback when there are no neighbors).
+   fillValues []float64
+   // Which features had missing training values.
+   hasMissing []bool
+   // The training data, with NaN for missing values (only kept for IMPUTE_KNN).
+   trainingData [][]float64
+}
+
+// Use NewConstantImputer() and NewKnnImputer() for the strategies that take a parameter.
+// If |addIndicators| is true, then an indicator column is added for every feature that had missing training values.
+func NewImputer(imputation Imputation, addIndicators bool) *Imputer {
+   if (imputation < IMPUTE_MEAN || imputation > IMPUTE_KNN) {
+      panic(fmt.Sprintf("Unknown imputation: %d", imputation));
+   }
+
+   var imputer Imputer = Imputer{
+      imputation: imputation,
+      addIndicators: addIndicators,
+      fillValue: 0,
+      k: IMPUTER_DEFAULT_K,
+   };
+
+   return &imputer;
+}
+
+func NewConstantImputer(fillValue float64, addIndicators bool) *Imputer {
+   var imputer *Imputer = NewImputer(IMPUTE_CONSTANT, addIndicators);
+   imputer.fillValue = fillValue;
+
+   return imputer;
+}
+
+// Pass a non-positive value for |k| to get IMPUTER_DEFAULT_K.
+func NewKnnImputer(k int, addIndicators bool) *Imputer {
+   var imputer *Imputer = NewImputer(IMPUTE_KNN, addIndicators);
+   if (k > 0) {
+      imputer.k = k;
+   }
+
+   return imputer;
+}
+
+// Features that are missing in every training tuple are filled with zero
+// (or the constant for IMPUTE_CONSTANT).
+func (this *Imputer) Fit(tuples []base.Tuple) {
+   if (len(tuples) == 0) {
+      panic("Need at least one tuple to fit an imputer.");
+   }
+
+   var data [][]float64 = missingData(tuples);
+   var numFeatures int = len(data[0]);
+
+   this.fillValues = make([]float64, numFeatures);
+   this.hasMissing = make([]bool, numFeatures);
+   this.trainingData = nil;
+
+   for j := 0; j < numFeatures; j++ {
+      var column []float64 = make([]float64, 0, len(data));
+      for _, point := range(data) {
+         if (math.IsNaN(point[j])) {
+            this.hasMissing[j] = true;
+         } else {
+            column = append(column, point[j]);
+         }
+      }
+
+      if (this.imputation == IMPUTE_CONSTANT) {
+         this.fillValues[j] = this.fillValue;
+         continue;
+      }
+
+      if (len(column) == 0) {
+         this.fillValues[j] = 0;
+         continue;
+      }
+
+      switch this.imputation {
+      case IMPUTE_MEAN, IMPUTE_KNN:
+         for _, value := range(column) {
+            this.fillValues[j] += value / float64(len(column));
+         }
+      case IMPUTE_MEDIAN:
+         this.fillValues[j] = util.Quantile(column, 0.5);
+      case IMPUTE_MOST_FREQUENT:
+         this.fillValues[j] = mostFrequent(column);
+      default:
+         panic(fmt.Sprintf("Unknown imputation: %d", this.imputation));
+      }
+   }
+
+   if (this.imputation == IMPUTE_KNN) {
+      this.trainingData = data;
+   }
+}
+
+func (this Imputer) Transform(tuples []base.Tuple) []base.Tuple {
+   if (this.fillValues == nil) {
+      panic("Imputer must be fit before transforming.");
+   }
+
+   var rtn []base.Tuple = make([]base.Tuple, len(tuples));
+   for i, point := range(missingData(tuples)) {
+      if (len(point) != len(this.fillValues)) {
+         panic(fmt.Sprintf("Expected %d features, got %d.", len(this.fillValues), len(point)));
+      }
+
+      var indicators []float64 = make([]float64, 0);
+      if (this.addIndicators) {
+         for j, value := range(point) {
+            if (!this.hasMissing[j]) {
+               continue;
+            }
+
+            if (math.IsNaN(value)) {
+               indicators = append(indicators, 1);
+            } else {
+               indicators = append(indicators, 0);
+            }
+         }
+      }
+
+      var neighbors [][]float64 = nil;
+      for j, value := range(point) {
+         if (!math.IsNaN(value)) {
+            continue;
+         }
+
+         if (this.imputation != IMPUTE_KNN) {
+            point[j] = this.fillValues[j];
+            continue;
+         }
+
+         if (neighbors == nil) {
+            neighbors = this.sortedNeighbors(point);
+         }
+         point[j] = this.knnValue(neighbors, j);
+      }
+
+      rtn[i] = base.NewFloatTuple(append(point, indicators...), tuples[i].GetClass());
+   }
+
+   return rtn;
+}
+
+// The same columns (all numeric now), followed by an indicator column named "<input name>_missing"
+// for every feature that had missing training values (if indicators were requested).
+func (this Imputer) OutputSchema(input Schema) Schema {
+   if (this.fillValues == nil) {
+      panic("Imputer must be fit before getting the output schema.");
+   }
+
+   var schema Schema = make(Schema, len(input));
+   for i, column := range(input) {
+      schema[i] = Column{column.Name, true};
+   }
+
+   if (this.addIndicators) {
+      for i, column := range(input) {
+         if (this.hasMissing[i]) {
+            schema = append(schema, Column{column.Name + "_missing", true});
+         }
+      }
+   }
+
+   return schema;
+}
+
+// The learned fill value for each feature (for IMPUTE_KNN, the value used when there are no neighbors).
+func (this Imputer) FillValues() []float64 {
+   return append([]float64(nil), this.fillValues...);
+}
+
+// All the training tuples, ordered by distance to |point| (closest first).
+// Training tuples that share no present features with |point| are left out.
+func (this Imputer) sortedNeighbors(point []float64) [][]float64 {
+   var indexes []int = make([]int, 0, len(this.trainingData));
+   var distances []float64 = make([]float64, len(this.trainingData));
+   for i, other := range(this.trainingData) {
+      distance, ok := nanEuclidean(point, other);
+      if (ok) {
+         indexes = append(indexes, i);
+         distances[i] = distance;
+      }
+   }
+
+   // Stable, so ties go to the earlier training tuple.
+   sort.SliceStable(indexes, func(i int, j int) bool {
+      return distances[indexes[i]] < distances[indexes[j]];
+   });
+
+   var neighbors [][]float64 = make([][]float64, len(indexes));
+   for i, index := range(indexes) {
+      neighbors[i] = this.trainingData[index];
+   }
+
+   return neighbors;
+}
+
+// The mean of |feature| over the closest k neighbors that have it.
+func (this Imputer) knnValue(neighbors [][]float64, feature int) float64 {
+   var sum float64 = 0;
+   var count int = 0;
+   for _, neighbor := range(neighbors) {
+      if (count >= this.k) {
+         break;
+      }
+
+      if (math.IsNaN(neighbor[feature])) {
+         continue;
+      }
+
+      sum += neighbor[feature];
+      count++;
+   }
+
+   if (count == 0) {
+      return this.fillValues[feature];
+   }
+
+   return sum / float64(count);
+}
+
+// Euclidean distance over the features present in both points,
+// scaled by (number of features / number of features used).
+// Returns false if there are no shared features.
+func nanEuclidean(a []float64, b []float64) (float64, bool) {
+   var sum float64 = 0;
+   var count int = 0;
+   for i, _ := range(a) {
+      if (math.IsNaN(a[i]) || math.IsNaN(b[i])) {
+         continue;
+      }
+
+      sum += (a[i] - b[i]) * (a[i] - b[i]);
+      count++;
+   }
+
+   if (count == 0) {
+      return 0, false;
+   }
+
+   return math.Sqrt(sum * float64(len(a)) / float64(count)), true;
+}
+
+// The smallest of the most common values.
+func mostFrequent(values []float64) float64 {
+   var counts map[float64]int = make(map[float64]int);
+   for _, value := range(values) {
+      counts[value]++;
+   }
+
+   var best float64 = values[0];
+   for value, count := range(counts) {
+      if (count > counts[best] || (count == counts[best] && value < best)) {
+         best = value;
+      }
+   }
+
+   return best;
+}
+
+// The data of every tuple with NaN for missing (base.NilFeature) values.
+func missingData(tuples []base.Tuple) [][]float64 {
+   var data [][]float64 = make([][]float64, len(tuples));
+   for i, tuple := range(tuples) {
+      data[i] = make([]float64, tuple.DataSize());
+      for j, _ := range(data[i]) {
+         switch value := tuple.GetData(j).(type) {
+         case base.NilFeature:
+            data[i][j] = math.NaN();
+         case base.NumericFeature:
+            data[i][j] = value.NumericValue();
+         default:
+            panic(fmt.Sprintf("Can only impute numeric features (encode categorical features first). Found: %v (%T)", value, value));
+         }
+      }
+   }
+
+   return data;
+}
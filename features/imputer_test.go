@@ -0,0 +1,172 @@
+package features
+
+import (
+   "math"
+   "reflect"
+   "testing"
+
+   "github.com/eriq-augustine/goml/base"
+)
+
+type imputerTestCase struct {
+   Name string
+   Imputer *Imputer
+   ExpectedFillValues []float64
+   Input []base.Tuple
+   ExpectedOutput [][]float64
+   ExpectedSchema []string
+}
+
+func imputerTestData() []base.Tuple {
+   return []base.Tuple{
+      base.NewTuple([]interface{}{1, nil, 10.0}, "A"),
+      base.NewTuple([]interface{}{2, 4.0, nil}, "A"),
+      base.NewTuple([]interface{}{nil, 4.0, 30.0}, "A"),
+      base.NewTuple([]interface{}{5, 6.0, 20.0}, "B"),
+      base.NewTuple([]interface{}{2, 8.0, nil}, "B"),
+   };
+}
+
+func TestImputer(t *testing.T) {
+   var testCases []imputerTestCase = []imputerTestCase{
+      imputerTestCase{
+         "Mean",
+         NewImputer(IMPUTE_MEAN, false),
+         []float64{2.5, 5.5, 20},
+         []base.Tuple{base.NewTuple([]interface{}{nil, nil, 5.0}, nil)},
+         [][]float64{{2.5, 5.5, 5}},
+         []string{"x0", "x1", "x2"},
+      },
+      imputerTestCase{
+         "Median",
+         NewImputer(IMPUTE_MEDIAN, false),
+         []float64{2, 5, 20},
+         []base.Tuple{base.NewTuple([]interface{}{nil, 1.0, nil}, nil)},
+         [][]float64{{2, 1, 20}},
+         []string{"x0", "x1", "x2"},
+      },
+      imputerTestCase{
+         "Most Frequent",
+         NewImputer(IMPUTE_MOST_FREQUENT, false),
+         []float64{2, 4, 10},
+         []base.Tuple{base.NewTuple([]interface{}{nil, nil, nil}, nil)},
+         [][]float64{{2, 4, 10}},
+         []string{"x0", "x1", "x2"},
+      },
+      imputerTestCase{
+         "Constant",
+         NewConstantImputer(-1, false),
+         []float64{-1, -1, -1},
+         []base.Tuple{base.NewFloatTuple([]float64{math.NaN(), 3, math.NaN()}, nil)},
+         [][]float64{{-1, 3, -1}},
+         []string{"x0", "x1", "x2"},
+      },
+      imputerTestCase{
+         "Indicators",
+         NewImputer(IMPUTE_MEAN, true),
+         []float64{2.5, 5.5, 20},
+         []base.Tuple{
+            base.NewTuple([]interface{}{nil, nil, 5.0}, nil),
+            base.NewTuple([]interface{}{1.0, 2.0, 3.0}, nil),
+         },
+         [][]float64{
+            {2.5, 5.5, 5, 1, 1, 0},
+            {1, 2, 3, 0, 0, 0},
+         },
+         []string{"x0", "x1", "x2", "x0_missing", "x1_missing", "x2_missing"},
+      },
+      imputerTestCase{
+         // Only the last feature can be compared: the closest is the fourth tuple.
+         "KNN - One",
+         NewKnnImputer(1, false),
+         []float64{2.5, 5.5, 20},
+         []base.Tuple{base.NewTuple([]interface{}{nil, nil, 21.0}, nil)},
+         [][]float64{{5, 6, 21}},
+         []string{"x0", "x1", "x2"},
+      },
+      imputerTestCase{
+         // The second closest (third tuple) is missing the first feature, so the first tuple is used instead.
+         "KNN - Two",
+         NewKnnImputer(2, false),
+         []float64{2.5, 5.5, 20},
+         []base.Tuple{base.NewTuple([]interface{}{nil, nil, 21.0}, nil)},
+         [][]float64{{3, 5, 21}},
+         []string{"x0", "x1", "x2"},
+      },
+      imputerTestCase{
+         // No training tuple has anything in common, so the mean is used.
+         "KNN - No Neighbors",
+         NewKnnImputer(0, false),
+         []float64{2.5, 5.5, 20},
+         []base.Tuple{base.NewTuple([]interface{}{nil, nil, nil}, nil)},
+         [][]float64{{2.5, 5.5, 20}},
+         []string{"x0", "x1", "x2"},
+      },
+   };
+
+   var data []base.Tuple = imputerTestData();
+
+   for _, testCase := range(testCases) {
+      testCase.Imputer.Fit(data);
+
+      for i, value := range(testCase.Imputer.FillValues()) {
+         if (math.Abs(value - testCase.ExpectedFillValues[i]) > 1e-9) {
+            t.Errorf("(%s)[%d] -- Bad fill value. Expected: %v, Got: %v", testCase.Name, i, testCase.ExpectedFillValues[i], value);
+         }
+      }
+
+      var names []string = testCase.Imputer.OutputSchema(InferSchema(data)).Names();
+      if (!reflect.DeepEqual(names, testCase.ExpectedSchema)) {
+         t.Errorf("(%s) -- Bad schema. Expected: %v, Got: %v", testCase.Name, testCase.ExpectedSchema, names);
+      }
+
+      var output []base.Tuple = testCase.Imputer.Transform(testCase.Input);
+      for i, tuple := range(output) {
+         numericTuple, ok := tuple.(base.NumericTuple);
+         if (!ok) {
+            t.Errorf("(%s)[%d] -- Expected a NumericTuple, got: %T", testCase.Name, i, tuple);
+            continue;
+         }
+
+         var actual []float64 = numericTuple.ToFloatSlice();
+         if (len(actual) != len(testCase.ExpectedOutput[i])) {
+            t.Errorf("(%s)[%d] -- Wrong number of features. Expected: %v, Got: %v", testCase.Name, i, testCase.ExpectedOutput[i], actual);
+            continue;
+         }
+
+         for j, value := range(actual) {
+            if (math.Abs(value - testCase.ExpectedOutput[i][j]) > 1e-9) {
+               t.Errorf("(%s)[%d][%d] -- Bad imputation. Expected: %v, Got: %v", testCase.Name, i, j, testCase.ExpectedOutput[i][j], value);
+            }
+         }
+      }
+   }
+}
+
+// Training tuples are imputed too, and only the features that had missing values get indicators.
+func TestImputerTraining(t *testing.T) {
+   var data []base.Tuple = []base.Tuple{
+      base.NewTuple([]interface{}{1.0, nil}, "A"),
+      base.NewTuple([]interface{}{2.0, 3.0}, "B"),
+      base.NewTuple([]interface{}{3.0, 5.0}, "B"),
+   };
+
+   var imputer *Imputer = NewImputer(IMPUTE_MEAN, true);
+   imputer.Fit(data);
+
+   var names []string = imputer.OutputSchema(Schema{{"age", true}, {"income", true}}).Names();
+   if (!reflect.DeepEqual(names, []string{"age", "income", "income_missing"})) {
+      t.Errorf("Bad schema: %v", names);
+   }
+
+   var expected [][]float64 = [][]float64{{1, 4, 1}, {2, 3, 0}, {3, 5, 0}};
+   for i, tuple := range(imputer.Transform(data)) {
+      if (tuple.GetClass() != data[i].GetClass()) {
+         t.Errorf("[%d] -- Class changed. Expected: %v, Got: %v", i, data[i].GetClass(), tuple.GetClass());
+      }
+
+      if (!reflect.DeepEqual(tuple.(base.NumericTuple).ToFloatSlice(), expected[i])) {
+         t.Errorf("[%d] -- Bad imputation. Expected: %v, Got: %v", i, expected[i], tuple.(base.NumericTuple).ToFloatSlice());
+      }
+   }
+}
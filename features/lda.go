@@ -0,0 +1,217 @@
+package features
+
+// Supervised projection with linear discriminant analysis (Fisher 1936).
+// Projects onto the directions that best separate the class means relative to the spread within each class
+// (the top eigenvectors of S_w^-1 S_b, where S_w and S_b are the within and between class covariances).
+// There are at most C - 1 useful directions (for C classes).
+// Unlike the selecting reducers, this creates new features: Reduce() returns new FloatTuples.
+// Every call to Init() refits.
+
+import (
+   "fmt"
+   "sort"
+
+   "github.com/eriq-augustine/goml/base"
+   "github.com/eriq-augustine/goml/util"
+
+   "gonum.org/v1/gonum/mat"
+)
+
+const (
+   // Added to the diagonal of the within class covariance to keep it invertible.
+   LDA_REDUCER_REGULARIZATION = 1e-6
+)
+
+type LDAReducer struct {
+   numComponents int
+
+   means []float64
+   // [component][feature]
+   projection [][]float64
+   explainedVarianceRatio []float64
+}
+
+// Pass a non-positive value for |numComponents| to use C - 1 (the most possible).
+// More than C - 1 components will be reduced to C - 1.
+func NewLDAReducer(numComponents int) *LDAReducer {
+   var reducer LDAReducer = LDAReducer{
+      numComponents: numComponents,
+   };
+
+   return &reducer;
+}
+
+// All tuples must be base.NumericTuple and have a class.
+func (this *LDAReducer) Init(tuples []base.Tuple) {
+   var data [][]float64 = numericData(tuples);
+   var numFeatures int = len(data[0]);
+
+   var labelMap map[base.Feature]int = make(map[base.Feature]int);
+   var labels []int = make([]int, len(tuples));
+   for i, tuple := range(tuples) {
+      label, ok := labelMap[tuple.GetClass()];
+      if (!ok) {
+         label = len(labelMap);
+         labelMap[tuple.GetClass()] = label;
+      }
+
+      labels[i] = label;
+   }
+
+   var numClasses int = len(labelMap);
+   if (numClasses < 2) {
+      panic("Need at least two classes for LDA.");
+   }
+
+   if (len(data) <= numClasses) {
+      panic(fmt.Sprintf("Need more tuples (%d) than classes (%d) to estimate a covariance.", len(data), numClasses));
+   }
+
+   this.means = columnMeans(data);
+
+   var counts []float64 = make([]float64, numClasses);
+   var classMeans [][]float64 = make([][]float64, numClasses);
+   for i, _ := range(classMeans) {
+      classMeans[i] = make([]float64, numFeatures);
+   }
+
+   for i, point := range(data) {
+      counts[labels[i]]++;
+      for j, value := range(point) {
+         classMeans[labels[i]][j] += value;
+      }
+   }
+
+   for label, mean := range(classMeans) {
+      for j, _ := range(mean) {
+         mean[j] /= counts[label];
+      }
+   }
+
+   var within *mat.SymDense = mat.NewSymDense(numFeatures, nil);
+   var between *mat.SymDense = mat.NewSymDense(numFeatures, nil);
+
+   for i, point := range(data) {
+      var mean []float64 = classMeans[labels[i]];
+      for j := 0; j < numFeatures; j++ {
+         for k := j; k < numFeatures; k++ {
+            within.SetSym(j, k, within.At(j, k) + (point[j] - mean[j]) * (point[k] - mean[k]) / float64(len(data) - numClasses));
+         }
+      }
+   }
+
+   for label, mean := range(classMeans) {
+      var weight float64 = counts[label] / float64(len(data));
+      for j := 0; j < numFeatures; j++ {
+         for k := j; k < numFeatures; k++ {
+            between.SetSym(j, k, between.At(j, k) + weight * (mean[j] - this.means[j]) * (mean[k] - this.means[k]));
+         }
+      }
+   }
+
+   for j := 0; j < numFeatures; j++ {
+      within.SetSym(j, j, within.At(j, j) + LDA_REDUCER_REGULARIZATION);
+   }
+
+   // Whiten with the Cholesky factor of S_w (S_w = L L'), then it is a symmetric eigen problem:
+   // L^-1 S_b L^-T v = lambda v, and the directions are w = L^-T v (so w' S_w w = 1).
+   var cholesky mat.Cholesky;
+   if (!cholesky.Factorize(within)) {
+      panic("Within class covariance is not positive definite.");
+   }
+
+   var lower mat.TriDense;
+   cholesky.LTo(&lower);
+
+   var lowerInverse mat.TriDense;
+   err := lowerInverse.InverseTri(&lower);
+   if (err != nil) {
+      panic(fmt.Sprintf("Failed to invert the Cholesky factor: %v", err));
+   }
+
+   var whitened mat.Dense;
+   whitened.Product(&lowerInverse, between, lowerInverse.T());
+
+   var symmetric *mat.SymDense = mat.NewSymDense(numFeatures, nil);
+   for j := 0; j < numFeatures; j++ {
+      for k := j; k < numFeatures; k++ {
+         // Average out any rounding asymmetry.
+         symmetric.SetSym(j, k, (whitened.At(j, k) + whitened.At(k, j)) / 2.0);
+      }
+   }
+
+   var eigen mat.EigenSym;
+   if (!eigen.Factorize(symmetric, true)) {
+      panic("Eigen decomposition failed.");
+   }
+
+   var values []float64 = eigen.Values(nil);
+   var vectors mat.Dense;
+   eigen.VectorsTo(&vectors);
+
+   var directions mat.Dense;
+   directions.Mul(lowerInverse.T(), &vectors);
+
+   // Eigenvalues come out in ascending order.
+   var order []int = util.RangeSlice(len(values));
+   sort.SliceStable(order, func(i int, j int) bool {
+      return values[order[i]] > values[order[j]];
+   });
+
+   var numComponents int = numClasses - 1;
+   if (numComponents > numFeatures) {
+      numComponents = numFeatures;
+   }
+
+   if (this.numComponents > 0 && this.numComponents < numComponents) {
+      numComponents = this.numComponents;
+   }
+
+   var totalValue float64 = 0;
+   for _, value := range(values) {
+      if (value > 0) {
+         totalValue += value;
+      }
+   }
+
+   this.projection = make([][]float64, numComponents);
+   this.explainedVarianceRatio = make([]float64, numComponents);
+   for i := 0; i < numComponents; i++ {
+      this.projection[i] = mat.Col(nil, order[i], &directions);
+      fixSign(this.projection[i]);
+
+      if (totalValue > 0 && values[order[i]] > 0) {
+         this.explainedVarianceRatio[i] = values[order[i]] / totalValue;
+      }
+   }
+}
+
+// Returns new FloatTuples (with the same classes) with one feature per discriminant direction.
+func (this LDAReducer) Reduce(tuples []base.Tuple) []base.Tuple {
+   if (this.projection == nil) {
+      panic("LDAReducer must be initialized before reducing.");
+   }
+
+   return projectTuples(tuples, this.means, this.projection);
+}
+
+// No input features are selected, every output feature is a mix of all of them.
+func (this LDAReducer) GetFeatures() []int {
+   return make([]int, 0);
+}
+
+// The discriminant directions (scaled so the within class variance along each is one).
+// [component][feature]
+func (this LDAReducer) Components() [][]float64 {
+   var components [][]float64 = make([][]float64, len(this.projection));
+   for i, component := range(this.projection) {
+      components[i] = append([]float64(nil), component...);
+   }
+
+   return components;
+}
+
+// The fraction of the between class separation along each direction.
+func (this LDAReducer) ExplainedVarianceRatio() []float64 {
+   return append([]float64(nil), this.explainedVarianceRatio...);
+}
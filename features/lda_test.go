@@ -0,0 +1,80 @@
+package features
+
+import (
+   "math"
+   "testing"
+
+   "github.com/eriq-augustine/goml/base"
+)
+
+type ldaReducerTestCase struct {
+   Name string
+   NumComponents int
+   ExpectedComponents int
+}
+
+func TestLDAReducer(t *testing.T) {
+   var testCases []ldaReducerTestCase = []ldaReducerTestCase{
+      ldaReducerTestCase{"Default", 0, 2},
+      ldaReducerTestCase{"One", 1, 1},
+      ldaReducerTestCase{"Too Many", 5, 2},
+   };
+
+   // Three classes that differ only in the first two (of four) features.
+   var centers [][]float64 = [][]float64{{0, 0, 0, 0}, {5, 0, 0, 0}, {0, 5, 0, 0}};
+   var data []base.Tuple = gaussianTestData(600, centers, [][]float64{{0, 0, 10, 0}, {0, 0, 0, 10}}, 1, 18);
+
+   for _, testCase := range(testCases) {
+      var reducer *LDAReducer = NewLDAReducer(testCase.NumComponents);
+      reducer.Init(data);
+
+      var reduced []base.Tuple = reducer.Reduce(data);
+      if (!checkNumComponents(t, testCase.Name, reduced, testCase.ExpectedComponents)) {
+         continue;
+      }
+
+      // The noise features should be (nearly) ignored.
+      for i, component := range(reducer.Components()) {
+         var signal float64 = math.Abs(component[0]) + math.Abs(component[1]);
+         var noise float64 = math.Abs(component[2]) + math.Abs(component[3]);
+         if (noise > 0.1 * signal) {
+            t.Errorf("(%s)[%d] -- Component uses the noise features: %v", testCase.Name, i, component);
+         }
+      }
+
+      // The within class variance along every direction is one.
+      var sums map[base.Feature][]float64 = make(map[base.Feature][]float64);
+      var counts map[base.Feature]float64 = make(map[base.Feature]float64);
+      for _, tuple := range(reduced) {
+         if (sums[tuple.GetClass()] == nil) {
+            sums[tuple.GetClass()] = make([]float64, tuple.DataSize());
+         }
+
+         counts[tuple.GetClass()]++;
+         for j, value := range(tuple.(base.NumericTuple).ToFloatSlice()) {
+            sums[tuple.GetClass()][j] += value;
+         }
+      }
+
+      for j := 0; j < testCase.ExpectedComponents; j++ {
+         var variance float64 = 0;
+         for _, tuple := range(reduced) {
+            var diff float64 = tuple.(base.NumericTuple).GetNumericData(j) - sums[tuple.GetClass()][j] / counts[tuple.GetClass()];
+            variance += diff * diff / float64(len(reduced) - len(counts));
+         }
+
+         if (math.Abs(variance - 1.0) > 1e-3) {
+            t.Errorf("(%s)[%d] -- Bad within class variance. Expected: 1, Got: %v", testCase.Name, j, variance);
+         }
+      }
+
+      var totalRatio float64 = 0;
+      for _, ratio := range(reducer.ExplainedVarianceRatio()) {
+         totalRatio += ratio;
+      }
+
+      if (testCase.ExpectedComponents == 2 && math.Abs(totalRatio - 1.0) > 1e-6) {
+         t.Errorf("(%s) -- The C - 1 components should explain everything: %v", testCase.Name, totalRatio);
+      }
+   }
+}
@@ -0,0 +1,144 @@
+package features
+
+// Expand categorical columns into one indicator (0/1) column per category.
+// Other columns must be numeric and are passed through (in place).
+// Always produces FloatTuples, so categorical data can be used by the numeric classifiers.
+
+import (
+   "fmt"
+
+   "github.com/eriq-augustine/goml/base"
+)
+
+type OneHotEncoder struct {
+   // Nil means every StringFeature or BoolFeature column.
+   columns []int
+   handleUnknown UnknownCategory
+   maxCategories int
+
+   numInputs int
+   // Indexed by input column, nil for columns that are passed through.
+   vocabularies []*categoryVocabulary
+}
+
+// Pass nil for |columns| to encode every column that has a StringFeature or BoolFeature.
+// If |maxCategories| is positive, then each column gets at most that many indicators:
+// the least frequent categories all share a single indicator (named ENCODER_INFREQUENT_NAME).
+func NewOneHotEncoder(columns []int, handleUnknown UnknownCategory, maxCategories int) *OneHotEncoder {
+   if (handleUnknown < ENCODER_UNKNOWN_ERROR || handleUnknown > ENCODER_UNKNOWN_IGNORE) {
+      panic(fmt.Sprintf("Unknown unknown category handling: %d", handleUnknown));
+   }
+
+   if (maxCategories == 1) {
+      panic("Need at least two categories per column for an infrequent bucket.");
+   }
+
+   var encoder OneHotEncoder = OneHotEncoder{
+      columns: columns,
+      handleUnknown: handleUnknown,
+      maxCategories: maxCategories,
+   };
+
+   return &encoder;
+}
+
+func (this *OneHotEncoder) Fit(tuples []base.Tuple) {
+   if (len(tuples) == 0) {
+      panic("Need at least one tuple to fit an encoder.");
+   }
+
+   this.numInputs = tuples[0].DataSize();
+   this.vocabularies = make([]*categoryVocabulary, this.numInputs);
+   for _, column := range(categoricalColumns(tuples, this.columns)) {
+      this.vocabularies[column] = newCategoryVocabulary(tuples, column, this.maxCategories);
+   }
+}
+
+func (this OneHotEncoder) Transform(tuples []base.Tuple) []base.Tuple {
+   if (this.vocabularies == nil) {
+      panic("OneHotEncoder must be fit before transforming.");
+   }
+
+   var rtn []base.Tuple = make([]base.Tuple, len(tuples));
+   for i, tuple := range(tuples) {
+      if (tuple.DataSize() != this.numInputs) {
+         panic(fmt.Sprintf("Expected %d features, got %d.", this.numInputs, tuple.DataSize()));
+      }
+
+      var data []float64 = make([]float64, 0, this.numInputs);
+      for column, vocabulary := range(this.vocabularies) {
+         if (vocabulary == nil) {
+            data = append(data, passThroughValue(tuple, column).NumericValue());
+            continue;
+         }
+
+         var indicators []float64 = make([]float64, vocabulary.size());
+         code, ok := vocabulary.code(tuple.GetData(column));
+         if (ok) {
+            indicators[code] = 1;
+         } else if (this.handleUnknown == ENCODER_UNKNOWN_ERROR) {
+            panic(fmt.Sprintf("Unknown category in column %d: %v", column, tuple.GetData(column)));
+         }
+
+         data = append(data, indicators...);
+      }
+
+      rtn[i] = base.NewFloatTuple(data, tuple.GetClass());
+   }
+
+   return rtn;
+}
+
+// Indicator columns are named "<input name>=<category>".
+func (this OneHotEncoder) OutputSchema(input Schema) Schema {
+   var sources []int = this.SourceColumns();
+
+   var schema Schema = make(Schema, 0, len(sources));
+   for column, vocabulary := range(this.vocabularies) {
+      if (vocabulary == nil) {
+         schema = append(schema, Column{input[column].Name, true});
+         continue;
+      }
+
+      for _, name := range(vocabulary.names()) {
+         schema = append(schema, Column{fmt.Sprintf("%s=%s", input[column].Name, name), true});
+      }
+   }
+
+   return schema;
+}
+
+// For each output column, the index of the input column it came from.
+func (this OneHotEncoder) SourceColumns() []int {
+   if (this.vocabularies == nil) {
+      panic("OneHotEncoder must be fit before getting columns.");
+   }
+
+   var sources []int = make([]int, 0, this.numInputs);
+   for column, vocabulary := range(this.vocabularies) {
+      var size int = 1;
+      if (vocabulary != nil) {
+         size = vocabulary.size();
+      }
+
+      for i := 0; i < size; i++ {
+         sources = append(sources, column);
+      }
+   }
+
+   return sources;
+}
+
+// The categories (in indicator order) for an encoded input column, or nil if the column is passed through.
+// The infrequent bucket (if any) is not included.
+func (this OneHotEncoder) Categories(column int) []base.Feature {
+   if (this.vocabularies == nil) {
+      panic("OneHotEncoder must be fit before getting categories.");
+   }
+
+   if (this.vocabularies[column] == nil) {
+      return nil;
+   }
+
+   return append([]base.Feature(nil), this.vocabularies[column].categories...);
+}
@@ -0,0 +1,131 @@
+package features
+
+import (
+   "reflect"
+   "testing"
+
+   "github.com/eriq-augustine/goml/base"
+)
+
+type oneHotTestCase struct {
+   Name string
+   Encoder *OneHotEncoder
+   ExpectedSchema []string
+   ExpectedSources []int
+   Input []base.Tuple
+   ExpectedOutput [][]float64
+}
+
+// Color is categorical, size is numeric, and the flag is a bool.
+func encoderTestData() []base.Tuple {
+   return []base.Tuple{
+      base.NewTuple([]interface{}{"red", 1.5, true}, "A"),
+      base.NewTuple([]interface{}{"green", 2.0, false}, "A"),
+      base.NewTuple([]interface{}{"red", 3.0, true}, "B"),
+      base.NewTuple([]interface{}{"blue", 4.0, true}, "B"),
+      base.NewTuple([]interface{}{"red", 5.0, false}, "B"),
+      base.NewTuple([]interface{}{"green", 6.0, true}, "B"),
+   };
+}
+
+func TestOneHotEncoder(t *testing.T) {
+   var testCases []oneHotTestCase = []oneHotTestCase{
+      oneHotTestCase{
+         "Auto Columns",
+         NewOneHotEncoder(nil, ENCODER_UNKNOWN_ERROR, 0),
+         []string{"color=blue", "color=green", "color=red", "size", "flag=false", "flag=true"},
+         []int{0, 0, 0, 1, 2, 2},
+         []base.Tuple{
+            base.NewTuple([]interface{}{"green", 7.0, true}, nil),
+            base.NewTuple([]interface{}{"blue", -1.0, false}, nil),
+         },
+         [][]float64{
+            {0, 1, 0, 7, 0, 1},
+            {1, 0, 0, -1, 1, 0},
+         },
+      },
+      oneHotTestCase{
+         "Given Columns",
+         NewOneHotEncoder([]int{0}, ENCODER_UNKNOWN_ERROR, 0),
+         []string{"color=blue", "color=green", "color=red", "size", "flag"},
+         []int{0, 0, 0, 1, 2},
+         []base.Tuple{
+            base.NewTuple([]interface{}{"red", 7.0, true}, nil),
+         },
+         [][]float64{
+            {0, 0, 1, 7, 1},
+         },
+      },
+      oneHotTestCase{
+         "Ignore Unknown",
+         NewOneHotEncoder(nil, ENCODER_UNKNOWN_IGNORE, 0),
+         []string{"color=blue", "color=green", "color=red", "size", "flag=false", "flag=true"},
+         []int{0, 0, 0, 1, 2, 2},
+         []base.Tuple{
+            base.NewTuple([]interface{}{"purple", 7.0, true}, nil),
+         },
+         [][]float64{
+            {0, 0, 0, 7, 0, 1},
+         },
+      },
+      oneHotTestCase{
+         // Red is the most common color, the others share a column.
+         "Max Categories",
+         NewOneHotEncoder(nil, ENCODER_UNKNOWN_IGNORE, 2),
+         []string{"color=red", "color=infrequent", "size", "flag=false", "flag=true"},
+         []int{0, 0, 1, 2, 2},
+         []base.Tuple{
+            base.NewTuple([]interface{}{"blue", 7.0, true}, nil),
+            base.NewTuple([]interface{}{"purple", 7.0, true}, nil),
+            base.NewTuple([]interface{}{"red", 7.0, false}, nil),
+         },
+         [][]float64{
+            {0, 1, 7, 0, 1},
+            {0, 0, 7, 0, 1},
+            {1, 0, 7, 1, 0},
+         },
+      },
+   };
+
+   var schema Schema = Schema{{"color", false}, {"size", true}, {"flag", true}};
+
+   for _, testCase := range(testCases) {
+      testCase.Encoder.Fit(encoderTestData());
+
+      var names []string = testCase.Encoder.OutputSchema(schema).Names();
+      if (!reflect.DeepEqual(names, testCase.ExpectedSchema)) {
+         t.Errorf("(%s) -- Bad schema. Expected: %v, Got: %v", testCase.Name, testCase.ExpectedSchema, names);
+      }
+
+      var sources []int = testCase.Encoder.SourceColumns();
+      if (!reflect.DeepEqual(sources, testCase.ExpectedSources)) {
+         t.Errorf("(%s) -- Bad source columns. Expected: %v, Got: %v", testCase.Name, testCase.ExpectedSources, sources);
+      }
+
+      var output []base.Tuple = testCase.Encoder.Transform(testCase.Input);
+      for i, tuple := range(output) {
+         numericTuple, ok := tuple.(base.NumericTuple);
+         if (!ok) {
+            t.Errorf("(%s)[%d] -- Expected a NumericTuple, got: %T", testCase.Name, i, tuple);
+            continue;
+         }
+
+         if (!reflect.DeepEqual(numericTuple.ToFloatSlice(), testCase.ExpectedOutput[i])) {
+            t.Errorf("(%s)[%d] -- Bad encoding. Expected: %v, Got: %v", testCase.Name, i, testCase.ExpectedOutput[i], numericTuple.ToFloatSlice());
+         }
+      }
+   }
+}
+
+func TestOneHotEncoderUnknownPanics(t *testing.T) {
+   var encoder *OneHotEncoder = NewOneHotEncoder(nil, ENCODER_UNKNOWN_ERROR, 0);
+   encoder.Fit(encoderTestData());
+
+   defer func() {
+      if (recover() == nil) {
+         t.Errorf("Expected a panic on an unknown category.");
+      }
+   }();
+
+   encoder.Transform([]base.Tuple{base.NewTuple([]interface{}{"purple", 7.0, true}, nil)});
+}
@@ -0,0 +1,137 @@
+package features
+
+// Replace each category with an integer code (0, 1, ..., in sorted order of the categories).
+// Other columns must be numeric and are passed through.
+// Produces NumericTuples where the codes are base.IntFeature.
+
+import (
+   "fmt"
+
+   "github.com/eriq-augustine/goml/base"
+)
+
+type OrdinalEncoder struct {
+   // Nil means every StringFeature or BoolFeature column.
+   columns []int
+   handleUnknown UnknownCategory
+
+   numInputs int
+   // Indexed by input column, nil for columns that are passed through.
+   vocabularies []*categoryVocabulary
+}
+
+// Pass nil for |columns| to encode every column that has a StringFeature or BoolFeature.
+// With ENCODER_UNKNOWN_IGNORE, unseen categories get ENCODER_UNKNOWN_CODE.
+func NewOrdinalEncoder(columns []int, handleUnknown UnknownCategory) *OrdinalEncoder {
+   if (handleUnknown < ENCODER_UNKNOWN_ERROR || handleUnknown > ENCODER_UNKNOWN_IGNORE) {
+      panic(fmt.Sprintf("Unknown unknown category handling: %d", handleUnknown));
+   }
+
+   var encoder OrdinalEncoder = OrdinalEncoder{
+      columns: columns,
+      handleUnknown: handleUnknown,
+   };
+
+   return &encoder;
+}
+
+func (this *OrdinalEncoder) Fit(tuples []base.Tuple) {
+   if (len(tuples) == 0) {
+      panic("Need at least one tuple to fit an encoder.");
+   }
+
+   this.numInputs = tuples[0].DataSize();
+   this.vocabularies = make([]*categoryVocabulary, this.numInputs);
+   for _, column := range(categoricalColumns(tuples, this.columns)) {
+      this.vocabularies[column] = newCategoryVocabulary(tuples, column, 0);
+   }
+}
+
+func (this OrdinalEncoder) Transform(tuples []base.Tuple) []base.Tuple {
+   if (this.vocabularies == nil) {
+      panic("OrdinalEncoder must be fit before transforming.");
+   }
+
+   var rtn []base.Tuple = make([]base.Tuple, len(tuples));
+   for i, tuple := range(tuples) {
+      if (tuple.DataSize() != this.numInputs) {
+         panic(fmt.Sprintf("Expected %d features, got %d.", this.numInputs, tuple.DataSize()));
+      }
+
+      var data []interface{} = make([]interface{}, this.numInputs);
+      for column, vocabulary := range(this.vocabularies) {
+         if (vocabulary == nil) {
+            data[column] = passThroughValue(tuple, column);
+            continue;
+         }
+
+         code, ok := vocabulary.code(tuple.GetData(column));
+         if (!ok && this.handleUnknown == ENCODER_UNKNOWN_ERROR) {
+            panic(fmt.Sprintf("Unknown category in column %d: %v", column, tuple.GetData(column)));
+         }
+
+         data[column] = base.Int(code);
+      }
+
+      rtn[i] = base.NewNumericTuple(data, tuple.GetClass());
+   }
+
+   return rtn;
+}
+
+// The same columns, but now they are all numeric.
+func (this OrdinalEncoder) OutputSchema(input Schema) Schema {
+   if (this.vocabularies == nil) {
+      panic("OrdinalEncoder must be fit before getting the output schema.");
+   }
+
+   var schema Schema = make(Schema, len(input));
+   for i, column := range(input) {
+      schema[i] = Column{column.Name, true};
+   }
+
+   return schema;
+}
+
+// The categories (in code order) for an encoded input column, or nil if the column is passed through.
+func (this OrdinalEncoder) Categories(column int) []base.Feature {
+   if (this.vocabularies == nil) {
+      panic("OrdinalEncoder must be fit before getting categories.");
+   }
+
+   if (this.vocabularies[column] == nil) {
+      return nil;
+   }
+
+   return append([]base.Feature(nil), this.vocabularies[column].categories...);
+}
+
+// Map codes back to the original categories (the inverse of Transform()).
+// Unknown codes become base.NilFeature.
+func (this OrdinalEncoder) InverseTransform(tuples []base.Tuple) []base.Tuple {
+   if (this.vocabularies == nil) {
+      panic("OrdinalEncoder must be fit before transforming.");
+   }
+
+   var rtn []base.Tuple = make([]base.Tuple, len(tuples));
+   for i, tuple := range(tuples) {
+      var data []interface{} = make([]interface{}, this.numInputs);
+      for column, vocabulary := range(this.vocabularies) {
+         if (vocabulary == nil) {
+            data[column] = tuple.GetData(column);
+            continue;
+         }
+
+         var code int = int(passThroughValue(tuple, column).NumericValue());
+         if (code < 0 || code >= len(vocabulary.categories)) {
+            data[column] = base.Nil();
+         } else {
+            data[column] = vocabulary.categories[code];
+         }
+      }
+
+      rtn[i] = base.NewTuple(data, tuple.GetClass());
+   }
+
+   return rtn;
+}
@@ -0,0 +1,62 @@
+package features
+
+import (
+   "reflect"
+   "testing"
+
+   "github.com/eriq-augustine/goml/base"
+)
+
+func TestOrdinalEncoder(t *testing.T) {
+   var encoder *OrdinalEncoder = NewOrdinalEncoder(nil, ENCODER_UNKNOWN_IGNORE);
+   encoder.Fit(encoderTestData());
+
+   var expectedCategories []base.Feature = []base.Feature{base.String("blue"), base.String("green"), base.String("red")};
+   if (!reflect.DeepEqual(encoder.Categories(0), expectedCategories)) {
+      t.Errorf("Bad categories. Expected: %v, Got: %v", expectedCategories, encoder.Categories(0));
+   }
+
+   if (encoder.Categories(1) != nil) {
+      t.Errorf("Numeric column should not have categories, got: %v", encoder.Categories(1));
+   }
+
+   var input []base.Tuple = []base.Tuple{
+      base.NewTuple([]interface{}{"red", 7.0, false}, "A"),
+      base.NewTuple([]interface{}{"purple", 2.5, true}, "B"),
+   };
+   var expected [][]base.Feature = [][]base.Feature{
+      {base.Int(2), base.Float(7.0), base.Int(0)},
+      {base.Int(ENCODER_UNKNOWN_CODE), base.Float(2.5), base.Int(1)},
+   };
+
+   var output []base.Tuple = encoder.Transform(input);
+   for i, tuple := range(output) {
+      if (!tuple.IsNumeric()) {
+         t.Errorf("[%d] -- Expected a numeric tuple, got: %T", i, tuple);
+      }
+
+      if (tuple.GetClass() != input[i].GetClass()) {
+         t.Errorf("[%d] -- Class changed. Expected: %v, Got: %v", i, input[i].GetClass(), tuple.GetClass());
+      }
+
+      for j, expectedValue := range(expected[i]) {
+         if (tuple.GetData(j) != expectedValue) {
+            t.Errorf("[%d][%d] -- Bad encoding. Expected: %v (%T), Got: %v (%T)", i, j, expectedValue, expectedValue, tuple.GetData(j), tuple.GetData(j));
+         }
+      }
+   }
+
+   var restored []base.Tuple = encoder.InverseTransform(output);
+   var expectedRestored [][]base.Feature = [][]base.Feature{
+      {base.String("red"), base.Float(7.0), base.Bool(false)},
+      {base.Nil(), base.Float(2.5), base.Bool(true)},
+   };
+
+   for i, tuple := range(restored) {
+      for j, expectedValue := range(expectedRestored[i]) {
+         if (tuple.GetData(j) != expectedValue) {
+            t.Errorf("[%d][%d] -- Bad inverse. Expected: %v (%T), Got: %v (%T)", i, j, expectedValue, expectedValue, tuple.GetData(j), tuple.GetData(j));
+         }
+      }
+   }
+}
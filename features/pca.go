@@ -0,0 +1,207 @@
+package features
+
+// Principal component analysis.
+// Tuples are centered and projected onto the directions of greatest variance
+// (the right singular vectors of the centered data).
+// Unlike the selecting reducers, this creates new features: Reduce() returns new FloatTuples.
+// Every call to Init() refits.
+
+import (
+   "fmt"
+   "math"
+
+   "github.com/eriq-augustine/goml/base"
+
+   "gonum.org/v1/gonum/mat"
+)
+
+type PCAReducer struct {
+   numComponents int
+   // Only used if |numComponents| is not positive.
+   varianceTarget float64
+   // Scale every component to unit variance.
+   whiten bool
+
+   means []float64
+   // [component][feature], already scaled if whitening.
+   projection [][]float64
+   // [component][feature], always unit length.
+   components [][]float64
+   explainedVariance []float64
+   explainedVarianceRatio []float64
+}
+
+// If |numComponents| is positive, then keep that many components (at most the number of features or tuples).
+// Otherwise if |varianceTarget| is in (0, 1], then keep the fewest components that explain at least that fraction of the variance.
+// Otherwise keep all the components.
+// If |whiten| is true, then every output feature will have unit variance (on the training data).
+func NewPCAReducer(numComponents int, varianceTarget float64, whiten bool) *PCAReducer {
+   if (varianceTarget > 1) {
+      panic(fmt.Sprintf("Variance target must be in (0, 1], got: %v", varianceTarget));
+   }
+
+   var reducer PCAReducer = PCAReducer{
+      numComponents: numComponents,
+      varianceTarget: varianceTarget,
+      whiten: whiten,
+   };
+
+   return &reducer;
+}
+
+// All tuples must be base.NumericTuple.
+func (this *PCAReducer) Init(tuples []base.Tuple) {
+   if (len(tuples) < 2) {
+      panic("Need at least two tuples for PCA.");
+   }
+
+   var data [][]float64 = numericData(tuples);
+   this.means = columnMeans(data);
+
+   var centered *mat.Dense = mat.NewDense(len(data), len(data[0]), nil);
+   for i, row := range(data) {
+      for j, value := range(row) {
+         centered.Set(i, j, value - this.means[j]);
+      }
+   }
+
+   var svd mat.SVD;
+   if (!svd.Factorize(centered, mat.SVDThinV)) {
+      panic("SVD failed.");
+   }
+
+   var values []float64 = svd.Values(nil);
+   var vectors mat.Dense;
+   svd.VTo(&vectors);
+
+   var totalVariance float64 = 0;
+   var variances []float64 = make([]float64, len(values));
+   for i, value := range(values) {
+      variances[i] = value * value / float64(len(data) - 1);
+      totalVariance += variances[i];
+   }
+
+   var numComponents int = this.chooseNumComponents(variances, totalVariance);
+
+   this.components = make([][]float64, numComponents);
+   this.projection = make([][]float64, numComponents);
+   this.explainedVariance = variances[:numComponents];
+   this.explainedVarianceRatio = make([]float64, numComponents);
+
+   for i := 0; i < numComponents; i++ {
+      this.components[i] = mat.Col(nil, i, &vectors);
+      fixSign(this.components[i]);
+
+      if (totalVariance > 0) {
+         this.explainedVarianceRatio[i] = variances[i] / totalVariance;
+      }
+
+      this.projection[i] = append([]float64(nil), this.components[i]...);
+      if (this.whiten && variances[i] > 0) {
+         for j, _ := range(this.projection[i]) {
+            this.projection[i][j] /= math.Sqrt(variances[i]);
+         }
+      }
+   }
+}
+
+func (this PCAReducer) chooseNumComponents(variances []float64, totalVariance float64) int {
+   if (this.numComponents > 0) {
+      if (this.numComponents > len(variances)) {
+         return len(variances);
+      }
+      return this.numComponents;
+   }
+
+   if (this.varianceTarget <= 0 || totalVariance == 0) {
+      return len(variances);
+   }
+
+   var explained float64 = 0;
+   for i, variance := range(variances) {
+      explained += variance / totalVariance;
+      // A little slack for rounding.
+      if (explained >= this.varianceTarget - 1e-12) {
+         return i + 1;
+      }
+   }
+
+   return len(variances);
+}
+
+// Returns new FloatTuples (with the same classes) with one feature per component.
+func (this PCAReducer) Reduce(tuples []base.Tuple) []base.Tuple {
+   if (this.projection == nil) {
+      panic("PCAReducer must be initialized before reducing.");
+   }
+
+   return projectTuples(tuples, this.means, this.projection);
+}
+
+// No input features are selected, every output feature is a mix of all of them.
+func (this PCAReducer) GetFeatures() []int {
+   return make([]int, 0);
+}
+
+// Map reduced tuples back into the original feature space (exact if no components were dropped).
+func (this PCAReducer) InverseReduce(tuples []base.Tuple) []base.Tuple {
+   if (this.projection == nil) {
+      panic("PCAReducer must be initialized before reducing.");
+   }
+
+   var rtn []base.Tuple = make([]base.Tuple, len(tuples));
+   for i, point := range(numericData(tuples)) {
+      var original []float64 = append([]float64(nil), this.means...);
+      for component, value := range(point) {
+         if (this.whiten) {
+            value *= math.Sqrt(this.explainedVariance[component]);
+         }
+
+         for j, _ := range(original) {
+            original[j] += value * this.components[component][j];
+         }
+      }
+
+      rtn[i] = base.NewFloatTuple(original, tuples[i].GetClass());
+   }
+
+   return rtn;
+}
+
+// The principal axes (unit length), ordered by explained variance.
+// [component][feature]
+func (this PCAReducer) Components() [][]float64 {
+   var components [][]float64 = make([][]float64, len(this.components));
+   for i, component := range(this.components) {
+      components[i] = append([]float64(nil), component...);
+   }
+
+   return components;
+}
+
+// The variance of the training data along each component.
+func (this PCAReducer) ExplainedVariance() []float64 {
+   return append([]float64(nil), this.explainedVariance...);
+}
+
+// The fraction of the total variance along each component.
+func (this PCAReducer) ExplainedVarianceRatio() []float64 {
+   return append([]float64(nil), this.explainedVarianceRatio...);
+}
+
+// Singular vectors are only unique up to sign.
+// Make the largest (by magnitude) value positive so results are repeatable.
+func fixSign(vector []float64) {
+   var largest int = 0;
+   for i, value := range(vector) {
+      if (math.Abs(value) > math.Abs(vector[largest])) {
+         largest = i;
+      }
+   }
+
+   if (vector[largest] < 0) {
+      for i, _ := range(vector) {
+         vector[i] = -vector[i];
+      }
+   }
+}
@@ -0,0 +1,105 @@
+package features
+
+import (
+   "math"
+   "testing"
+
+   "github.com/eriq-augustine/goml/base"
+)
+
+type pcaTestCase struct {
+   Name string
+   NumComponents int
+   VarianceTarget float64
+   Whiten bool
+   ExpectedComponents int
+}
+
+func TestPCAReducer(t *testing.T) {
+   var testCases []pcaTestCase = []pcaTestCase{
+      pcaTestCase{"All", 0, 0, false, 3},
+      pcaTestCase{"Fixed", 2, 0, false, 2},
+      pcaTestCase{"Too Many", 10, 0, false, 3},
+      pcaTestCase{"Variance - One", 0, 0.95, false, 1},
+      pcaTestCase{"Variance - Two", 0, 0.999, false, 2},
+      pcaTestCase{"Whiten", 2, 0, true, 2},
+   };
+
+   // Mostly along (1, 2, 0), a bit along (0, 0, 1), and a tiny bit of noise everywhere.
+   var data []base.Tuple = gaussianTestData(500, [][]float64{{5, -3, 0}}, [][]float64{{10, 20, 0}, {0, 0, 2}}, 0.01, 16);
+
+   for _, testCase := range(testCases) {
+      var reducer *PCAReducer = NewPCAReducer(testCase.NumComponents, testCase.VarianceTarget, testCase.Whiten);
+      reducer.Init(data);
+
+      var reduced []base.Tuple = reducer.Reduce(data);
+      if (!checkNumComponents(t, testCase.Name, reduced, testCase.ExpectedComponents)) {
+         continue;
+      }
+
+      for i, tuple := range(reduced) {
+         if (tuple.GetClass() != data[i].GetClass()) {
+            t.Errorf("(%s)[%d] -- Class changed. Expected: %v, Got: %v", testCase.Name, i, data[i].GetClass(), tuple.GetClass());
+            break;
+         }
+      }
+
+      // The first component is (1, 2, 0) / sqrt(5).
+      var expected []float64 = []float64{1.0 / math.Sqrt(5), 2.0 / math.Sqrt(5), 0};
+      for i, value := range(reducer.Components()[0]) {
+         if (math.Abs(value - expected[i]) > 0.02) {
+            t.Errorf("(%s)[%d] -- Bad first component. Expected: %v, Got: %v", testCase.Name, i, expected[i], value);
+         }
+      }
+
+      // The reduced features are uncorrelated with the explained variances (or unit variances when whitened).
+      var points [][]float64 = numericData(reduced);
+      for i := 0; i < testCase.ExpectedComponents; i++ {
+         for j := 0; j <= i; j++ {
+            var covariance float64 = 0;
+            for _, point := range(points) {
+               covariance += point[i] * point[j] / float64(len(points) - 1);
+            }
+
+            var expectedCovariance float64 = 0;
+            if (i == j) {
+               expectedCovariance = 1;
+               if (!testCase.Whiten) {
+                  expectedCovariance = reducer.ExplainedVariance()[i];
+               }
+            }
+
+            if (math.Abs(covariance - expectedCovariance) > 1e-6 * math.Max(1, expectedCovariance)) {
+               t.Errorf("(%s)[%d][%d] -- Bad covariance. Expected: %v, Got: %v", testCase.Name, i, j, expectedCovariance, covariance);
+            }
+         }
+      }
+   }
+}
+
+// With every component kept, reducing and then inverting gives back the original data.
+func TestPCAReducerInverse(t *testing.T) {
+   var data []base.Tuple = gaussianTestData(50, [][]float64{{5, -3, 0}}, nil, 1, 17);
+
+   for _, whiten := range([]bool{false, true}) {
+      var reducer *PCAReducer = NewPCAReducer(0, 0, whiten);
+      reducer.Init(data);
+
+      var restored []base.Tuple = reducer.InverseReduce(reducer.Reduce(data));
+      for i, tuple := range(restored) {
+         for j := 0; j < tuple.DataSize(); j++ {
+            var expected float64 = data[i].(base.NumericTuple).GetNumericData(j);
+            var actual float64 = tuple.(base.NumericTuple).GetNumericData(j);
+
+            if (math.Abs(expected - actual) > 1e-9) {
+               t.Errorf("(%v)[%d][%d] -- Bad inverse. Expected: %v, Got: %v", whiten, i, j, expected, actual);
+            }
+         }
+      }
+   }
+
+   var ratios []float64 = NewPCAReducer(0, 0, false).ExplainedVarianceRatio();
+   if (len(ratios) != 0) {
+      t.Errorf("Expected no ratios before Init(), got: %v", ratios);
+   }
+}
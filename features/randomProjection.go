@@ -0,0 +1,203 @@
+package features
+
+// Random projection.
+// Tuples are multiplied by a random matrix, which (by the Johnson-Lindenstrauss lemma)
+// approximately preserves the pairwise distances with far fewer features.
+// Fitting only looks at the number of features, so it is very cheap even for very wide data.
+// Unlike the selecting reducers, this creates new features: Reduce() returns new FloatTuples.
+// Every call to Init() draws a new matrix (see base.Seed() for repeatable results).
+
+import (
+   "fmt"
+   "math"
+   "math/rand"
+
+   "github.com/eriq-augustine/goml/base"
+)
+
+type RandomProjection int
+
+const (
+   // Every entry is drawn from N(0, 1 / numComponents).
+   RANDOM_PROJECTION_GAUSSIAN RandomProjection = iota
+   // Entries are +/- sqrt(1 / (density * numComponents)) with probability density / 2 each, and zero otherwise (Li et al. 2006).
+   // Much faster to apply on wide data.
+   RANDOM_PROJECTION_SPARSE
+)
+
+const (
+   RANDOM_PROJECTION_DEFAULT_EPSILON = 0.1
+)
+
+type RandomProjectionReducer struct {
+   projectionType RandomProjection
+   numComponents int
+   epsilon float64
+   // The fraction of nonzero entries (sparse only). Non-positive means 1 / sqrt(numFeatures).
+   density float64
+
+   numFeatures int
+   // For each component, the features with a nonzero weight and their weights.
+   indices [][]int
+   weights [][]float64
+}
+
+// If |numComponents| is positive, then project to that many features.
+// Otherwise use the Johnson-Lindenstrauss bound for the number of training tuples
+// with |epsilon| (non-positive means RANDOM_PROJECTION_DEFAULT_EPSILON) as the allowed distortion.
+// |density| is only used for sparse projections, pass a non-positive value to get 1 / sqrt(numFeatures).
+func NewRandomProjectionReducer(projectionType RandomProjection, numComponents int, epsilon float64, density float64) *RandomProjectionReducer {
+   if (projectionType < RANDOM_PROJECTION_GAUSSIAN || projectionType > RANDOM_PROJECTION_SPARSE) {
+      panic(fmt.Sprintf("Unknown random projection: %d", projectionType));
+   }
+
+   if (epsilon <= 0) {
+      epsilon = RANDOM_PROJECTION_DEFAULT_EPSILON;
+   }
+
+   if (epsilon >= 1) {
+      panic(fmt.Sprintf("Epsilon must be in (0, 1), got: %v", epsilon));
+   }
+
+   if (density > 1) {
+      panic(fmt.Sprintf("Density must be in (0, 1], got: %v", density));
+   }
+
+   var reducer RandomProjectionReducer = RandomProjectionReducer{
+      projectionType: projectionType,
+      numComponents: numComponents,
+      epsilon: epsilon,
+      density: density,
+   };
+
+   return &reducer;
+}
+
+// The smallest number of features that a random projection can (with high probability)
+// embed |numTuples| tuples into while keeping every pairwise squared distance within a factor of (1 +/- |epsilon|).
+// See Dasgupta and Gupta (2003): k >= 4 ln(n) / (epsilon^2 / 2 - epsilon^3 / 3).
+func JohnsonLindenstraussMinDim(numTuples int, epsilon float64) int {
+   if (numTuples <= 0) {
+      panic(fmt.Sprintf("Number of tuples must be positive, got: %d", numTuples));
+   }
+
+   if (epsilon <= 0 || epsilon >= 1) {
+      panic(fmt.Sprintf("Epsilon must be in (0, 1), got: %v", epsilon));
+   }
+
+   var denominator float64 = epsilon * epsilon / 2.0 - epsilon * epsilon * epsilon / 3.0;
+   return int(math.Ceil(4.0 * math.Log(float64(numTuples)) / denominator));
+}
+
+// All tuples must be base.NumericTuple.
+// Only the number of features (and number of tuples if using the Johnson-Lindenstrauss bound) is used.
+func (this *RandomProjectionReducer) Init(tuples []base.Tuple) {
+   if (len(tuples) == 0) {
+      panic("Need at least one tuple for random projection.");
+   }
+
+   this.numFeatures = tuples[0].DataSize();
+
+   var numComponents int = this.numComponents;
+   if (numComponents <= 0) {
+      numComponents = JohnsonLindenstraussMinDim(len(tuples), this.epsilon);
+      // A single tuple has no pairwise distances, so the bound is zero.
+      if (numComponents <= 0) {
+         panic(fmt.Sprintf("The Johnson-Lindenstrauss bound for %d tuples is zero components. Pass the number of components.", len(tuples)));
+      }
+
+      if (numComponents > this.numFeatures) {
+         panic(fmt.Sprintf("The Johnson-Lindenstrauss bound (%d) for %d tuples with epsilon %v is more than the number of features (%d). " +
+               "Use a larger epsilon or pass the number of components.",
+               numComponents, len(tuples), this.epsilon, this.numFeatures));
+      }
+   }
+
+   var random *rand.Rand = base.NewRandom();
+
+   this.indices = make([][]int, numComponents);
+   this.weights = make([][]float64, numComponents);
+
+   switch this.projectionType {
+   case RANDOM_PROJECTION_GAUSSIAN:
+      var scale float64 = 1.0 / math.Sqrt(float64(numComponents));
+      for i := 0; i < numComponents; i++ {
+         this.indices[i] = make([]int, this.numFeatures);
+         this.weights[i] = make([]float64, this.numFeatures);
+         for j := 0; j < this.numFeatures; j++ {
+            this.indices[i][j] = j;
+            this.weights[i][j] = random.NormFloat64() * scale;
+         }
+      }
+   case RANDOM_PROJECTION_SPARSE:
+      var density float64 = this.density;
+      if (density <= 0) {
+         density = 1.0 / math.Sqrt(float64(this.numFeatures));
+      }
+
+      var scale float64 = math.Sqrt(1.0 / (density * float64(numComponents)));
+      for i := 0; i < numComponents; i++ {
+         this.indices[i] = make([]int, 0);
+         this.weights[i] = make([]float64, 0);
+         for j := 0; j < this.numFeatures; j++ {
+            var draw float64 = random.Float64();
+            if (draw >= density) {
+               continue;
+            }
+
+            this.indices[i] = append(this.indices[i], j);
+            if (draw < density / 2.0) {
+               this.weights[i] = append(this.weights[i], scale);
+            } else {
+               this.weights[i] = append(this.weights[i], -scale);
+            }
+         }
+      }
+   default:
+      panic(fmt.Sprintf("Unknown random projection: %d", this.projectionType));
+   }
+}
+
+// Returns new FloatTuples (with the same classes) with one feature per component.
+func (this RandomProjectionReducer) Reduce(tuples []base.Tuple) []base.Tuple {
+   if (this.indices == nil) {
+      panic("RandomProjectionReducer must be initialized before reducing.");
+   }
+
+   var rtn []base.Tuple = make([]base.Tuple, len(tuples));
+   for i, point := range(numericData(tuples)) {
+      if (len(point) != this.numFeatures) {
+         panic(fmt.Sprintf("Expected %d features, got %d.", this.numFeatures, len(point)));
+      }
+
+      var projected []float64 = make([]float64, len(this.indices));
+      for component, indices := range(this.indices) {
+         for j, index := range(indices) {
+            projected[component] += this.weights[component][j] * point[index];
+         }
+      }
+
+      rtn[i] = base.NewFloatTuple(projected, tuples[i].GetClass());
+   }
+
+   return rtn;
+}
+
+// No input features are selected, every output feature is a mix of (some of) them.
+func (this RandomProjectionReducer) GetFeatures() []int {
+   return make([]int, 0);
+}
+
+// The (dense) projection matrix.
+// [component][feature]
+func (this RandomProjectionReducer) Components() [][]float64 {
+   var components [][]float64 = make([][]float64, len(this.indices));
+   for i, indices := range(this.indices) {
+      components[i] = make([]float64, this.numFeatures);
+      for j, index := range(indices) {
+         components[i][index] = this.weights[i][j];
+      }
+   }
+
+   return components;
+}
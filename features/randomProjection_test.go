@@ -0,0 +1,119 @@
+package features
+
+import (
+   "math"
+   "testing"
+
+   "github.com/eriq-augustine/goml/base"
+)
+
+type jlTestCase struct {
+   NumTuples int
+   Epsilon float64
+   Expected int
+}
+
+type randomProjectionTestCase struct {
+   Name string
+   ProjectionType RandomProjection
+   NumComponents int
+   Epsilon float64
+   Density float64
+   ExpectedComponents int
+}
+
+func TestJohnsonLindenstraussMinDim(t *testing.T) {
+   var testCases []jlTestCase = []jlTestCase{
+      jlTestCase{1000000, 0.5, 664},
+      jlTestCase{1000000, 0.1, 11842},
+      jlTestCase{100, 0.5, 222},
+      jlTestCase{1, 0.5, 0},
+   };
+
+   for i, testCase := range(testCases) {
+      var actual int = JohnsonLindenstraussMinDim(testCase.NumTuples, testCase.Epsilon);
+      if (actual != testCase.Expected) {
+         t.Errorf("[%d] -- Bad dimension for (%d, %v). Expected: %d, Got: %d", i, testCase.NumTuples, testCase.Epsilon, testCase.Expected, actual);
+      }
+   }
+}
+
+func TestRandomProjectionReducer(t *testing.T) {
+   var testCases []randomProjectionTestCase = []randomProjectionTestCase{
+      randomProjectionTestCase{"Gaussian - JL", RANDOM_PROJECTION_GAUSSIAN, 0, 0.5, 0, 222},
+      randomProjectionTestCase{"Sparse - JL", RANDOM_PROJECTION_SPARSE, 0, 0.5, 0, 222},
+      randomProjectionTestCase{"Sparse - Density", RANDOM_PROJECTION_SPARSE, 0, 0.5, 0.3, 222},
+      randomProjectionTestCase{"Gaussian - Fixed", RANDOM_PROJECTION_GAUSSIAN, 400, 0.5, 0, 400},
+   };
+
+   var origin []float64 = make([]float64, 2000);
+   var data []base.Tuple = gaussianTestData(100, [][]float64{origin, origin, origin}, nil, 1, 20);
+   var points [][]float64 = numericData(data);
+   base.Seed(21);
+
+   for _, testCase := range(testCases) {
+      var reducer *RandomProjectionReducer = NewRandomProjectionReducer(testCase.ProjectionType, testCase.NumComponents, testCase.Epsilon, testCase.Density);
+      reducer.Init(data);
+
+      var reduced []base.Tuple = reducer.Reduce(data);
+      if (!checkNumComponents(t, testCase.Name, reduced, testCase.ExpectedComponents)) {
+         continue;
+      }
+
+      if (reduced[5].GetClass() != data[5].GetClass()) {
+         t.Errorf("(%s) -- Class changed. Expected: %v, Got: %v", testCase.Name, data[5].GetClass(), reduced[5].GetClass());
+      }
+
+      if (testCase.ProjectionType == RANDOM_PROJECTION_SPARSE) {
+         var density float64 = testCase.Density;
+         if (density <= 0) {
+            density = 1.0 / math.Sqrt(2000);
+         }
+
+         var nonZero int = 0;
+         var components [][]float64 = reducer.Components();
+         for _, component := range(components) {
+            for _, value := range(component) {
+               if (value != 0) {
+                  nonZero++;
+               }
+            }
+         }
+
+         var actualDensity float64 = float64(nonZero) / float64(len(components) * 2000);
+         if (math.Abs(actualDensity - density) > 0.1 * density) {
+            t.Errorf("(%s) -- Bad density. Expected: %v, Got: %v", testCase.Name, density, actualDensity);
+         }
+      }
+
+      // Every pairwise squared distance is preserved within (1 +/- epsilon).
+      var reducedPoints [][]float64 = numericData(reduced);
+      for i := 0; i < len(points); i++ {
+         for j := i + 1; j < len(points); j++ {
+            var original float64 = math.Pow(base.Euclidean{}.Distance(base.NewFloatTuple(points[i], nil), base.NewFloatTuple(points[j], nil)), 2);
+            var projected float64 = math.Pow(base.Euclidean{}.Distance(base.NewFloatTuple(reducedPoints[i], nil), base.NewFloatTuple(reducedPoints[j], nil)), 2);
+
+            if (math.Abs(projected / original - 1) > testCase.Epsilon) {
+               t.Errorf("(%s)[%d][%d] -- Distance not preserved. Original: %v, Projected: %v", testCase.Name, i, j, original, projected);
+            }
+         }
+      }
+   }
+}
+
+// With one tuple, the Johnson-Lindenstrauss bound is zero, which would silently produce empty tuples.
+func TestRandomProjectionReducerSingleTuple(t *testing.T) {
+   var data []base.Tuple = gaussianTestData(1, [][]float64{make([]float64, 50)}, nil, 1, 22);
+
+   var reducer *RandomProjectionReducer = NewRandomProjectionReducer(RANDOM_PROJECTION_GAUSSIAN, 3, 0, 0);
+   reducer.Init(data);
+   checkNumComponents(t, "Explicit", reducer.Reduce(data), 3);
+
+   defer func() {
+      if (recover() == nil) {
+         t.Errorf("Expected a panic when the Johnson-Lindenstrauss bound is zero.");
+      }
+   }();
+
+   NewRandomProjectionReducer(RANDOM_PROJECTION_GAUSSIAN, 0, 0, 0).Init(data);
+}
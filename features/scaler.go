@@ -0,0 +1,232 @@
+package features
+
+// Rescale every feature using statistics learned from the training tuples:
+//    x' = (x - offset) / scale
+// Scaling matters for anything that mixes features together, eg distances (Knn) and gradient descent (LogisticRegression).
+// Only works on NumericTuple, and always produces FloatTuples.
+
+import (
+   "fmt"
+   "math"
+
+   "github.com/eriq-augustine/goml/base"
+   "github.com/eriq-augustine/goml/util"
+)
+
+type Scaling int
+
+const (
+   // Zero mean and unit variance.
+   SCALER_STANDARD Scaling = iota
+   // Map the training min and max to the ends of a range ([0, 1] by default).
+   SCALER_MIN_MAX
+   // Divide by the largest absolute value, so everything is in [-1, 1] (and zeros stay zero).
+   SCALER_MAX_ABS
+   // Subtract the median and divide by the interquartile range, so outliers have little effect.
+   SCALER_ROBUST
+)
+
+type Scaler struct {
+   scaling Scaling
+   // The output range for SCALER_MIN_MAX.
+   low float64
+   high float64
+
+   offsets []float64
+   scales []float64
+}
+
+// Use NewMinMaxScaler() for a range other than [0, 1].
+func NewScaler(scaling Scaling) *Scaler {
+   if (scaling < SCALER_STANDARD || scaling > SCALER_ROBUST) {
+      panic(fmt.Sprintf("Unknown scaling: %d", scaling));
+   }
+
+   var scaler Scaler = Scaler{
+      scaling: scaling,
+      low: 0,
+      high: 1,
+   };
+
+   return &scaler;
+}
+
+// Map the training min and max of every feature to |low| and |high|.
+func NewMinMaxScaler(low float64, high float64) *Scaler {
+   if (low >= high) {
+      panic(fmt.Sprintf("Bad min-max range: [%v, %v]", low, high));
+   }
+
+   var scaler *Scaler = NewScaler(SCALER_MIN_MAX);
+   scaler.low = low;
+   scaler.high = high;
+
+   return scaler;
+}
+
+// All tuples must be base.NumericTuple.
+// Features that are constant (no spread) are only shifted.
+func (this *Scaler) Fit(tuples []base.Tuple) {
+   if (len(tuples) == 0) {
+      panic("Need at least one tuple to fit a scaler.");
+   }
+
+   var data [][]float64 = numericData(tuples);
+   var numFeatures int = len(data[0]);
+
+   this.offsets = make([]float64, numFeatures);
+   this.scales = make([]float64, numFeatures);
+
+   for j := 0; j < numFeatures; j++ {
+      var column []float64 = make([]float64, len(data));
+      for i, point := range(data) {
+         column[i] = point[j];
+      }
+
+      var offset float64;
+      var scale float64;
+
+      switch this.scaling {
+      case SCALER_STANDARD:
+         offset, scale = standardScale(column);
+      case SCALER_MIN_MAX:
+         offset, scale = minMaxScale(column);
+      case SCALER_MAX_ABS:
+         offset, scale = maxAbsScale(column);
+      case SCALER_ROBUST:
+         offset, scale = robustScale(column);
+      default:
+         panic(fmt.Sprintf("Unknown scaling: %d", this.scaling));
+      }
+
+      if (scale < util.EPSILON) {
+         scale = 1;
+      }
+
+      this.offsets[j] = offset;
+      this.scales[j] = scale;
+   }
+}
+
+func (this Scaler) Transform(tuples []base.Tuple) []base.Tuple {
+   this.checkFit();
+
+   var rtn []base.Tuple = make([]base.Tuple, len(tuples));
+   for i, point := range(numericData(tuples)) {
+      this.checkSize(point);
+
+      for j, value := range(point) {
+         point[j] = (value - this.offsets[j]) / this.scales[j];
+      }
+
+      if (this.scaling == SCALER_MIN_MAX) {
+         for j, value := range(point) {
+            point[j] = this.low + value * (this.high - this.low);
+         }
+      }
+
+      rtn[i] = base.NewFloatTuple(point, tuples[i].GetClass());
+   }
+
+   return rtn;
+}
+
+// Undo Transform().
+func (this Scaler) InverseTransform(tuples []base.Tuple) []base.Tuple {
+   this.checkFit();
+
+   var rtn []base.Tuple = make([]base.Tuple, len(tuples));
+   for i, point := range(numericData(tuples)) {
+      this.checkSize(point);
+
+      if (this.scaling == SCALER_MIN_MAX) {
+         for j, value := range(point) {
+            point[j] = (value - this.low) / (this.high - this.low);
+         }
+      }
+
+      for j, value := range(point) {
+         point[j] = value * this.scales[j] + this.offsets[j];
+      }
+
+      rtn[i] = base.NewFloatTuple(point, tuples[i].GetClass());
+   }
+
+   return rtn;
+}
+
+// The same columns, but now they are all numeric.
+func (this Scaler) OutputSchema(input Schema) Schema {
+   this.checkFit();
+
+   var schema Schema = make(Schema, len(input));
+   for i, column := range(input) {
+      schema[i] = Column{column.Name, true};
+   }
+
+   return schema;
+}
+
+// The learned value subtracted from each feature.
+func (this Scaler) Offsets() []float64 {
+   return append([]float64(nil), this.offsets...);
+}
+
+// The learned value each feature is divided by (after subtracting the offset).
+func (this Scaler) Scales() []float64 {
+   return append([]float64(nil), this.scales...);
+}
+
+func (this Scaler) checkFit() {
+   if (this.scales == nil) {
+      panic("Scaler must be fit before transforming.");
+   }
+}
+
+func (this Scaler) checkSize(point []float64) {
+   if (len(point) != len(this.scales)) {
+      panic(fmt.Sprintf("Expected %d features, got %d.", len(this.scales), len(point)));
+   }
+}
+
+// Mean and (population) standard deviation.
+func standardScale(column []float64) (float64, float64) {
+   var mean float64 = 0;
+   for _, value := range(column) {
+      mean += value;
+   }
+   mean /= float64(len(column));
+
+   var variance float64 = 0;
+   for _, value := range(column) {
+      variance += (value - mean) * (value - mean);
+   }
+   variance /= float64(len(column));
+
+   return mean, math.Sqrt(variance);
+}
+
+// The output range is applied separately, so this maps to [0, 1].
+func minMaxScale(column []float64) (float64, float64) {
+   var min float64 = math.Inf(1);
+   var max float64 = math.Inf(-1);
+   for _, value := range(column) {
+      min = math.Min(min, value);
+      max = math.Max(max, value);
+   }
+
+   return min, max - min;
+}
+
+func maxAbsScale(column []float64) (float64, float64) {
+   var max float64 = 0;
+   for _, value := range(column) {
+      max = math.Max(max, math.Abs(value));
+   }
+
+   return 0, max;
+}
+
+func robustScale(column []float64) (float64, float64) {
+   return util.Quantile(column, 0.5), util.Quantile(column, 0.75) - util.Quantile(column, 0.25);
+}
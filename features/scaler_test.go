@@ -0,0 +1,111 @@
+package features
+
+import (
+   "math"
+   "testing"
+
+   "github.com/eriq-augustine/goml/base"
+)
+
+type scalerTestCase struct {
+   Name string
+   Scaler *Scaler
+   ExpectedOffsets []float64
+   ExpectedScales []float64
+   Input []float64
+   ExpectedOutput []float64
+}
+
+func TestScaler(t *testing.T) {
+   // The second feature is constant.
+   var data []base.Tuple = []base.Tuple{
+      base.NewNumericTuple([]interface{}{1, 5, -4}, "A"),
+      base.NewNumericTuple([]interface{}{2, 5, -2}, "A"),
+      base.NewNumericTuple([]interface{}{3, 5, 0}, "B"),
+      base.NewNumericTuple([]interface{}{4, 5, 2}, "B"),
+      base.NewNumericTuple([]interface{}{10, 5, 4}, "B"),
+   };
+
+   var testCases []scalerTestCase = []scalerTestCase{
+      scalerTestCase{
+         "Standard",
+         NewScaler(SCALER_STANDARD),
+         []float64{4, 5, 0},
+         []float64{math.Sqrt(10), 1, math.Sqrt(8)},
+         []float64{4 + math.Sqrt(10), 6, -2},
+         []float64{1, 1, -2 / math.Sqrt(8)},
+      },
+      scalerTestCase{
+         "Min-Max",
+         NewScaler(SCALER_MIN_MAX),
+         []float64{1, 5, -4},
+         []float64{9, 1, 8},
+         []float64{10, 5, 0},
+         []float64{1, 0, 0.5},
+      },
+      scalerTestCase{
+         "Min-Max - Range",
+         NewMinMaxScaler(-1, 1),
+         []float64{1, 5, -4},
+         []float64{9, 1, 8},
+         []float64{10, 5, 0},
+         []float64{1, -1, 0},
+      },
+      scalerTestCase{
+         "Max-Abs",
+         NewScaler(SCALER_MAX_ABS),
+         []float64{0, 0, 0},
+         []float64{10, 5, 4},
+         []float64{-5, 5, 2},
+         []float64{-0.5, 1, 0.5},
+      },
+      scalerTestCase{
+         "Robust",
+         NewScaler(SCALER_ROBUST),
+         []float64{3, 5, 0},
+         []float64{2, 1, 4},
+         []float64{100, 5, 2},
+         []float64{48.5, 0, 0.5},
+      },
+   };
+
+   for _, testCase := range(testCases) {
+      testCase.Scaler.Fit(data);
+
+      for i, offset := range(testCase.Scaler.Offsets()) {
+         if (math.Abs(offset - testCase.ExpectedOffsets[i]) > 1e-9) {
+            t.Errorf("(%s)[%d] -- Bad offset. Expected: %v, Got: %v", testCase.Name, i, testCase.ExpectedOffsets[i], offset);
+         }
+      }
+
+      for i, scale := range(testCase.Scaler.Scales()) {
+         if (math.Abs(scale - testCase.ExpectedScales[i]) > 1e-9) {
+            t.Errorf("(%s)[%d] -- Bad scale. Expected: %v, Got: %v", testCase.Name, i, testCase.ExpectedScales[i], scale);
+         }
+      }
+
+      var input []base.Tuple = []base.Tuple{base.NewFloatTuple(testCase.Input, "C")};
+      var output []base.Tuple = testCase.Scaler.Transform(input);
+      if (output[0].GetClass() != base.String("C")) {
+         t.Errorf("(%s) -- Class changed. Expected: C, Got: %v", testCase.Name, output[0].GetClass());
+      }
+
+      for i, value := range(output[0].(base.NumericTuple).ToFloatSlice()) {
+         if (math.Abs(value - testCase.ExpectedOutput[i]) > 1e-9) {
+            t.Errorf("(%s)[%d] -- Bad transform. Expected: %v, Got: %v", testCase.Name, i, testCase.ExpectedOutput[i], value);
+         }
+      }
+
+      var restored []base.Tuple = testCase.Scaler.InverseTransform(testCase.Scaler.Transform(data));
+      for i, tuple := range(restored) {
+         for j := 0; j < tuple.DataSize(); j++ {
+            var expected float64 = data[i].(base.NumericTuple).GetNumericData(j);
+            var actual float64 = tuple.(base.NumericTuple).GetNumericData(j);
+
+            if (math.Abs(expected - actual) > 1e-9) {
+               t.Errorf("(%s)[%d][%d] -- Bad inverse. Expected: %v, Got: %v", testCase.Name, i, j, expected, actual);
+            }
+         }
+      }
+   }
+}
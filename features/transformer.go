@@ -0,0 +1,137 @@
+package features
+
+import (
+   "fmt"
+
+   "github.com/eriq-augustine/goml/base"
+)
+
+// Transforms tuples into new tuples (eg scaling, imputing, encoding, selecting, or projecting features).
+// Unlike a Reducer, learning the transformation (Fit) is kept separate from applying it (Transform),
+// so any number of them can be chained (see classification.Pipeline).
+type Transformer interface {
+   // Learn any parameters of the transformation from the (training) tuples.
+   // Every call refits.
+   Fit(tuples []base.Tuple)
+   // Apply the fitted transformation. The passed in tuples are not modified.
+   Transform(tuples []base.Tuple) []base.Tuple
+   // Describe the columns that Transform() produces when given columns described by |input|.
+   // Only valid after Fit().
+   OutputSchema(input Schema) Schema
+}
+
+// Describes one column (feature) of a tuple.
+type Column struct {
+   Name string
+   Numeric bool
+}
+
+type Schema []Column
+
+// Make a schema from the first tuple (all tuples should look the same).
+// Columns are named "x0", "x1", ...
+func InferSchema(tuples []base.Tuple) Schema {
+   if (len(tuples) == 0) {
+      panic("Need at least one tuple to infer a schema.");
+   }
+
+   var schema Schema = make(Schema, tuples[0].DataSize());
+   for i, _ := range(schema) {
+      schema[i] = Column{fmt.Sprintf("x%d", i), tuples[0].GetData(i).IsNumeric()};
+   }
+
+   return schema;
+}
+
+// Get the names of all the columns (in order).
+func (this Schema) Names() []string {
+   var names []string = make([]string, len(this));
+   for i, column := range(this) {
+      names[i] = column.Name;
+   }
+
+   return names;
+}
+
+// Get the index of the column with the given name, or -1 if there is no such column.
+func (this Schema) Index(name string) int {
+   for i, column := range(this) {
+      if (column.Name == name) {
+         return i;
+      }
+   }
+
+   return -1;
+}
+
+// Lets any Reducer be used as a Transformer.
+// Fit() calls Init() and Transform() calls Reduce().
+type ReducerTransformer struct {
+   reducer Reducer
+   // The number of features that Reduce() produced on the training data.
+   numOutputs int
+   // Reduce() handed back the same tuples (eg NoReducer).
+   passThrough bool
+}
+
+func NewReducerTransformer(reducer Reducer) *ReducerTransformer {
+   if (reducer == nil) {
+      reducer = NoReducer{};
+   }
+
+   var transformer ReducerTransformer = ReducerTransformer{
+      reducer: reducer,
+      numOutputs: -1,
+   };
+
+   return &transformer;
+}
+
+func (this *ReducerTransformer) Fit(tuples []base.Tuple) {
+   this.reducer.Init(tuples);
+
+   this.numOutputs = 0;
+   this.passThrough = true;
+   if (len(tuples) > 0) {
+      var reduced base.Tuple = this.reducer.Reduce(tuples[0:1])[0];
+      this.numOutputs = reduced.DataSize();
+      this.passThrough = (reduced == tuples[0]);
+   }
+}
+
+func (this ReducerTransformer) Transform(tuples []base.Tuple) []base.Tuple {
+   return this.reducer.Reduce(tuples);
+}
+
+// Selecting reducers keep the selected columns.
+// Reducers that create new features (eg PCAReducer) produce numeric columns named "component0", "component1", ...
+func (this ReducerTransformer) OutputSchema(input Schema) Schema {
+   if (this.numOutputs < 0) {
+      panic("ReducerTransformer must be fit before getting the output schema.");
+   }
+
+   var selected []int = this.reducer.GetFeatures();
+   if (len(selected) > 0) {
+      var schema Schema = make(Schema, len(selected));
+      for i, index := range(selected) {
+         schema[i] = input[index];
+      }
+
+      return schema;
+   }
+
+   if (this.passThrough) {
+      return append(Schema(nil), input...);
+   }
+
+   var schema Schema = make(Schema, this.numOutputs);
+   for i, _ := range(schema) {
+      schema[i] = Column{fmt.Sprintf("component%d", i), true};
+   }
+
+   return schema;
+}
+
+func (this ReducerTransformer) GetReducer() Reducer {
+   return this.reducer;
+}
@@ -0,0 +1,142 @@
+package features
+
+// Truncated SVD (aka latent semantic analysis when used on term counts).
+// Like PCA, but the data is NOT centered first, so zeros stay meaningful
+// (eg sparse counts, where centering would make every value nonzero).
+// Tuples are projected onto the top right singular vectors of the raw data.
+// Unlike the selecting reducers, this creates new features: Reduce() returns new FloatTuples.
+// Every call to Init() refits.
+
+import (
+   "fmt"
+
+   "github.com/eriq-augustine/goml/base"
+
+   "gonum.org/v1/gonum/mat"
+)
+
+type TruncatedSVDReducer struct {
+   numComponents int
+
+   // [component][feature], unit length.
+   components [][]float64
+   singularValues []float64
+   explainedVarianceRatio []float64
+}
+
+// |numComponents| must be positive.
+// More components than the number of features or tuples will be reduced to the smaller of the two.
+func NewTruncatedSVDReducer(numComponents int) *TruncatedSVDReducer {
+   if (numComponents <= 0) {
+      panic(fmt.Sprintf("Number of components must be positive, got: %d", numComponents));
+   }
+
+   var reducer TruncatedSVDReducer = TruncatedSVDReducer{
+      numComponents: numComponents,
+   };
+
+   return &reducer;
+}
+
+// All tuples must be base.NumericTuple.
+func (this *TruncatedSVDReducer) Init(tuples []base.Tuple) {
+   if (len(tuples) == 0) {
+      panic("Need at least one tuple for truncated SVD.");
+   }
+
+   var data [][]float64 = numericData(tuples);
+
+   var matrix *mat.Dense = mat.NewDense(len(data), len(data[0]), nil);
+   for i, row := range(data) {
+      matrix.SetRow(i, row);
+   }
+
+   var svd mat.SVD;
+   if (!svd.Factorize(matrix, mat.SVDThinV)) {
+      panic("SVD failed.");
+   }
+
+   var values []float64 = svd.Values(nil);
+   var vectors mat.Dense;
+   svd.VTo(&vectors);
+
+   var numComponents int = this.numComponents;
+   if (numComponents > len(values)) {
+      numComponents = len(values);
+   }
+
+   // Since the data is not centered, the ratio is over the total sum of squares (not the variance).
+   var total float64 = 0;
+   for _, value := range(values) {
+      total += value * value;
+   }
+
+   this.components = make([][]float64, numComponents);
+   this.singularValues = append([]float64(nil), values[:numComponents]...);
+   this.explainedVarianceRatio = make([]float64, numComponents);
+
+   for i := 0; i < numComponents; i++ {
+      this.components[i] = mat.Col(nil, i, &vectors);
+      fixSign(this.components[i]);
+
+      if (total > 0) {
+         this.explainedVarianceRatio[i] = values[i] * values[i] / total;
+      }
+   }
+}
+
+// Returns new FloatTuples (with the same classes) with one feature per component.
+func (this TruncatedSVDReducer) Reduce(tuples []base.Tuple) []base.Tuple {
+   if (this.components == nil) {
+      panic("TruncatedSVDReducer must be initialized before reducing.");
+   }
+
+   return projectTuples(tuples, nil, this.components);
+}
+
+// No input features are selected, every output feature is a mix of all of them.
+func (this TruncatedSVDReducer) GetFeatures() []int {
+   return make([]int, 0);
+}
+
+// Map reduced tuples back into the original feature space (exact if no components were dropped).
+func (this TruncatedSVDReducer) InverseReduce(tuples []base.Tuple) []base.Tuple {
+   if (this.components == nil) {
+      panic("TruncatedSVDReducer must be initialized before reducing.");
+   }
+
+   var rtn []base.Tuple = make([]base.Tuple, len(tuples));
+   for i, point := range(numericData(tuples)) {
+      var original []float64 = make([]float64, len(this.components[0]));
+      for component, value := range(point) {
+         for j, _ := range(original) {
+            original[j] += value * this.components[component][j];
+         }
+      }
+
+      rtn[i] = base.NewFloatTuple(original, tuples[i].GetClass());
+   }
+
+   return rtn;
+}
+
+// The right singular vectors (unit length), ordered by singular value.
+// [component][feature]
+func (this TruncatedSVDReducer) Components() [][]float64 {
+   var components [][]float64 = make([][]float64, len(this.components));
+   for i, component := range(this.components) {
+      components[i] = append([]float64(nil), component...);
+   }
+
+   return components;
+}
+
+// The largest singular values (in decreasing order).
+func (this TruncatedSVDReducer) SingularValues() []float64 {
+   return append([]float64(nil), this.singularValues...);
+}
+
+// The fraction of the total (uncentered) sum of squares along each component.
+func (this TruncatedSVDReducer) ExplainedVarianceRatio() []float64 {
+   return append([]float64(nil), this.explainedVarianceRatio...);
+}
@@ -0,0 +1,113 @@
+package features
+
+import (
+   "math"
+   "testing"
+
+   "github.com/eriq-augustine/goml/base"
+)
+
+type truncatedSVDTestCase struct {
+   Name string
+   NumComponents int
+   ExpectedComponents int
+   // Whether every tuple should be reconstructed exactly.
+   Exact bool
+}
+
+func TestTruncatedSVDReducer(t *testing.T) {
+   var testCases []truncatedSVDTestCase = []truncatedSVDTestCase{
+      truncatedSVDTestCase{"One", 1, 1, false},
+      truncatedSVDTestCase{"Rank", 2, 2, true},
+      truncatedSVDTestCase{"Too Many", 10, 5, true},
+   };
+
+   // Not centered, and every tuple is in the span of two fixed rows: (3, 0, 1, 0, 2) and (0, 1, 0, 4, 0).
+   var data []base.Tuple = gaussianTestData(40, [][]float64{{30, 10, 10, 40, 20}}, [][]float64{{3, 0, 1, 0, 2}, {0, 1, 0, 4, 0}}, 0, 19);
+
+   for _, testCase := range(testCases) {
+      var reducer *TruncatedSVDReducer = NewTruncatedSVDReducer(testCase.NumComponents);
+      reducer.Init(data);
+
+      var reduced []base.Tuple = reducer.Reduce(data);
+      if (!checkNumComponents(t, testCase.Name, reduced, testCase.ExpectedComponents)) {
+         continue;
+      }
+
+      var values []float64 = reducer.SingularValues();
+      for i := 1; i < len(values); i++ {
+         if (values[i] > values[i - 1]) {
+            t.Errorf("(%s)[%d] -- Singular values out of order: %v", testCase.Name, i, values);
+         }
+      }
+
+      // The data has rank two, so the first two components explain everything.
+      var explained float64 = 0;
+      for i, ratio := range(reducer.ExplainedVarianceRatio()) {
+         if (i < 2) {
+            explained += ratio;
+         }
+      }
+
+      var expectedExplained float64 = 1;
+      if (!testCase.Exact) {
+         expectedExplained = reducer.ExplainedVarianceRatio()[0];
+      }
+
+      if (math.Abs(explained - expectedExplained) > 1e-9) {
+         t.Errorf("(%s) -- Bad explained ratio. Expected: %v, Got: %v", testCase.Name, expectedExplained, explained);
+      }
+
+      var restored []base.Tuple = reducer.InverseReduce(reduced);
+      var maxError float64 = 0;
+      for i, tuple := range(restored) {
+         if (tuple.GetClass() != data[i].GetClass()) {
+            t.Errorf("(%s)[%d] -- Class changed. Expected: %v, Got: %v", testCase.Name, i, data[i].GetClass(), tuple.GetClass());
+         }
+
+         for j := 0; j < tuple.DataSize(); j++ {
+            var expected float64 = data[i].(base.NumericTuple).GetNumericData(j);
+            var actual float64 = tuple.(base.NumericTuple).GetNumericData(j);
+            maxError = math.Max(maxError, math.Abs(expected - actual));
+         }
+      }
+
+      if (testCase.Exact && maxError > 1e-9) {
+         t.Errorf("(%s) -- Bad inverse. Max error: %v", testCase.Name, maxError);
+      } else if (!testCase.Exact && maxError < 1e-3) {
+         t.Errorf("(%s) -- Inverse should lose information. Max error: %v", testCase.Name, maxError);
+      }
+   }
+}
+
+// Unlike PCA, the data is not centered, so a constant offset shows up as the first component.
+func TestTruncatedSVDReducerUncentered(t *testing.T) {
+   var data []base.Tuple = make([]base.Tuple, 0);
+   for i := 0; i < 20; i++ {
+      var offset float64 = 0.1;
+      if (i % 2 == 0) {
+         offset = -0.1;
+      }
+
+      data = append(data, base.NewFloatTuple([]float64{10 + offset, 10 - offset}, 0));
+   }
+
+   var reducer *TruncatedSVDReducer = NewTruncatedSVDReducer(1);
+   reducer.Init(data);
+
+   var expected []float64 = []float64{1.0 / math.Sqrt(2), 1.0 / math.Sqrt(2)};
+   for i, value := range(reducer.Components()[0]) {
+      if (math.Abs(value - expected[i]) > 1e-9) {
+         t.Errorf("[%d] -- Bad first component. Expected: %v, Got: %v", i, expected[i], value);
+      }
+   }
+
+   var pca *PCAReducer = NewPCAReducer(1, 0, false);
+   pca.Init(data);
+
+   // PCA only sees the difference, which is along (1, -1).
+   var component []float64 = pca.Components()[0];
+   if (math.Abs(math.Abs(component[0]) - 1.0 / math.Sqrt(2)) > 1e-9 || component[0] * component[1] >= 0) {
+      t.Errorf("Bad PCA component. Expected: +/-(1, -1) / sqrt(2), Got: %v", component);
+   }
+}
@@ -1,6 +1,7 @@
 package features;
 
 import (
+   "fmt"
    "reflect"
 
    "github.com/eriq-augustine/goml/base"
@@ -27,3 +28,62 @@ func SelectFeatures(tuples []base.Tuple, features []int) []base.Tuple {
 
    return rtn;
 }
+
+// The data of every tuple (which must all be base.NumericTuple).
+func numericData(tuples []base.Tuple) [][]float64 {
+   var data [][]float64 = make([][]float64, len(tuples));
+   for i, tuple := range(tuples) {
+      numericTuple, ok := tuple.(base.NumericTuple);
+      if (!ok) {
+         panic(fmt.Sprintf("Only NumericTuple is supported. Found type: %T", tuple));
+      }
+
+      data[i] = numericTuple.ToFloatSlice();
+   }
+
+   return data;
+}
+
+// Project every tuple onto each row of |projection|, after subtracting |center| (if it is not nil).
+// Returns new FloatTuples with the same classes.
+func projectTuples(tuples []base.Tuple, center []float64, projection [][]float64) []base.Tuple {
+   var rtn []base.Tuple = make([]base.Tuple, len(tuples));
+   for i, point := range(numericData(tuples)) {
+      if (len(projection) > 0 && len(point) != len(projection[0])) {
+         panic(fmt.Sprintf("Expected %d features, got %d.", len(projection[0]), len(point)));
+      }
+
+      if (center != nil) {
+         for j, _ := range(point) {
+            point[j] -= center[j];
+         }
+      }
+
+      var projected []float64 = make([]float64, len(projection));
+      for j, row := range(projection) {
+         for k, value := range(point) {
+            projected[j] += row[k] * value;
+         }
+      }
+
+      rtn[i] = base.NewFloatTuple(projected, tuples[i].GetClass());
+   }
+
+   return rtn;
+}
+
+// The mean of each column.
+func columnMeans(data [][]float64) []float64 {
+   var means []float64 = make([]float64, len(data[0]));
+   for _, row := range(data) {
+      for j, value := range(row) {
+         means[j] += value;
+      }
+   }
+
+   for j, _ := range(means) {
+      means[j] /= float64(len(data));
+   }
+
+   return means;
+}
@@ -0,0 +1,46 @@
+package features
+
+import (
+   "math/rand"
+   "testing"
+
+   "github.com/eriq-augustine/goml/base"
+)
+
+// Gaussian data for the reducer tests.
+// Tuple i is in class i % len(centers) and is that class's center,
+// plus N(0, 1) times each of |directions|, plus N(0, noise^2) on every feature.
+func gaussianTestData(numTuples int, centers [][]float64, directions [][]float64, noise float64, seed int64) []base.Tuple {
+   var random *rand.Rand = rand.New(rand.NewSource(seed));
+
+   var tuples []base.Tuple = make([]base.Tuple, numTuples);
+   for i, _ := range(tuples) {
+      var class int = i % len(centers);
+      var data []float64 = append([]float64(nil), centers[class]...);
+
+      for _, direction := range(directions) {
+         var weight float64 = random.NormFloat64();
+         for j, value := range(direction) {
+            data[j] += weight * value;
+         }
+      }
+
+      for j, _ := range(data) {
+         data[j] += random.NormFloat64() * noise;
+      }
+
+      tuples[i] = base.NewFloatTuple(data, class);
+   }
+
+   return tuples;
+}
+
+// Returns false (after reporting the error) if the reduced tuples do not have |expected| features.
+func checkNumComponents(t *testing.T, name string, reduced []base.Tuple, expected int) bool {
+   if (reduced[0].DataSize() != expected) {
+      t.Errorf("(%s) -- Wrong number of components. Expected: %d, Got: %d", name, expected, reduced[0].DataSize());
+      return false;
+   }
+
+   return true;
+}
@@ -0,0 +1,167 @@
+package regression
+
+// Least squares with a mix of L1 and L2 penalties on the weights (the intercept is not penalized).
+// Minimizes: 1 / (2n) * ||y - Xw||^2 + alpha * l1Ratio * ||w||_1 + alpha * (1 - l1Ratio) / 2 * ||w||^2
+// An |l1Ratio| of 1 is the lasso.
+// Solved with cyclic coordinate descent (Friedman et al. 2010).
+
+import (
+   "fmt"
+   "math"
+
+   "github.com/eriq-augustine/goml/base"
+   "github.com/eriq-augustine/goml/features"
+)
+
+const (
+   ELASTIC_NET_DEFAULT_ALPHA = 1.0
+   ELASTIC_NET_DEFAULT_L1_RATIO = 0.5
+   ELASTIC_NET_DEFAULT_MAX_ITERATIONS = 1000
+   ELASTIC_NET_DEFAULT_TOLERENCE = 1e-4
+)
+
+type ElasticNet struct {
+   reducer features.Reducer
+   alpha float64
+   l1Ratio float64
+   maxIterations int
+   // Stop when the largest weight change in a full pass is below this.
+   tolerence float64
+   model linearModel
+   iterations int
+}
+
+// Pass a negative value for |alpha| or |l1Ratio| to get the default.
+// Pass a non-positive value for |maxIterations| or |tolerence| to get the default.
+func NewElasticNet(reducer features.Reducer, alpha float64, l1Ratio float64, maxIterations int, tolerence float64) *ElasticNet {
+   if (reducer == nil) {
+      reducer = features.NoReducer{};
+   }
+
+   if (alpha < 0) {
+      alpha = ELASTIC_NET_DEFAULT_ALPHA;
+   }
+
+   if (l1Ratio < 0) {
+      l1Ratio = ELASTIC_NET_DEFAULT_L1_RATIO;
+   }
+
+   if (l1Ratio > 1) {
+      panic(fmt.Sprintf("l1Ratio must be in [0, 1], got: %v", l1Ratio));
+   }
+
+   if (maxIterations <= 0) {
+      maxIterations = ELASTIC_NET_DEFAULT_MAX_ITERATIONS;
+   }
+
+   if (tolerence <= 0) {
+      tolerence = ELASTIC_NET_DEFAULT_TOLERENCE;
+   }
+
+   var elasticNet ElasticNet = ElasticNet{
+      reducer: reducer,
+      alpha: alpha,
+      l1Ratio: l1Ratio,
+      maxIterations: maxIterations,
+      tolerence: tolerence,
+   };
+
+   return &elasticNet;
+}
+
+// An ElasticNet with only the L1 penalty.
+func NewLasso(reducer features.Reducer, alpha float64, maxIterations int, tolerence float64) *ElasticNet {
+   return NewElasticNet(reducer, alpha, 1.0, maxIterations, tolerence);
+}
+
+func (this *ElasticNet) Train(tuples []base.Tuple) {
+   this.reducer.Init(tuples);
+   tuples = this.reducer.Reduce(tuples);
+
+   data, targets := trainingData(tuples);
+   data, targets, featureMeans, targetMean := center(data, targets);
+
+   var numPoints float64 = float64(len(data));
+   var numFeatures int = len(data[0]);
+
+   var l1 float64 = numPoints * this.alpha * this.l1Ratio;
+   var l2 float64 = numPoints * this.alpha * (1.0 - this.l1Ratio);
+
+   var columnNorms []float64 = make([]float64, numFeatures);
+   for _, dataPoint := range(data) {
+      for j, value := range(dataPoint) {
+         columnNorms[j] += value * value;
+      }
+   }
+
+   var weights []float64 = make([]float64, numFeatures);
+   // With all weights at zero, the residuals are just the targets.
+   var residuals []float64 = append([]float64(nil), targets...);
+
+   this.iterations = 0;
+   for this.iterations < this.maxIterations {
+      this.iterations++;
+      var maxChange float64 = 0;
+
+      for j := 0; j < numFeatures; j++ {
+         if (columnNorms[j] == 0) {
+            continue;
+         }
+
+         // The correlation of feature j with the residuals as if feature j was not in the model.
+         var rho float64 = 0;
+         for i, dataPoint := range(data) {
+            rho += dataPoint[j] * (residuals[i] + dataPoint[j] * weights[j]);
+         }
+
+         var weight float64 = softThreshold(rho, l1) / (columnNorms[j] + l2);
+         var change float64 = weight - weights[j];
+         if (change == 0) {
+            continue;
+         }
+
+         for i, dataPoint := range(data) {
+            residuals[i] -= dataPoint[j] * change;
+         }
+
+         weights[j] = weight;
+         maxChange = math.Max(maxChange, math.Abs(change));
+      }
+
+      if (maxChange < this.tolerence) {
+         break;
+      }
+   }
+
+   this.model.weights = weights;
+   this.model.setIntercept(featureMeans, targetMean);
+}
+
+func (this ElasticNet) Predict(tuples []base.Tuple) []float64 {
+   tuples = this.reducer.Reduce(tuples);
+   return this.model.predict(numericData(tuples));
+}
+
+// Indexes are into the reduced features.
+func (this ElasticNet) Weights() []float64 {
+   return append([]float64(nil), this.model.weights...);
+}
+
+func (this ElasticNet) Intercept() float64 {
+   return this.model.intercept;
+}
+
+// The number of coordinate descent passes used in the last call to Train().
+func (this ElasticNet) Iterations() int {
+   return this.iterations;
+}
+
+func softThreshold(value float64, threshold float64) float64 {
+   if (value > threshold) {
+      return value - threshold;
+   } else if (value < -threshold) {
+      return value + threshold;
+   }
+
+   return 0;
+}
@@ -0,0 +1,74 @@
+package regression
+
+import (
+   "math"
+   "testing"
+
+   "github.com/eriq-augustine/goml/base"
+)
+
+func TestLassoSparsity(t *testing.T) {
+   // Only x0 and x2 matter: y = 4 * x0 - 2 * x2 + 5
+   var data []base.Tuple = make([]base.Tuple, 0);
+   for i := 0; i < 60; i++ {
+      var x0 float64 = float64(i % 7) - 3.0;
+      var x1 float64 = float64(i % 5) - 2.0;
+      var x2 float64 = float64(i % 11) - 5.0;
+      var x3 float64 = float64((i * 3) % 4) - 1.5;
+      data = append(data, base.NewFloatTuple([]float64{x0, x1, x2, x3}, 4.0 * x0 - 2.0 * x2 + 5.0));
+   }
+
+   var lasso *ElasticNet = NewLasso(nil, 0.1, 0, 1e-8);
+   lasso.Train(data);
+
+   var weights []float64 = lasso.Weights();
+   for _, index := range([]int{1, 3}) {
+      if (weights[index] != 0) {
+         t.Errorf("[%d] -- Irrelevant feature should have a zero weight. Got: %v", index, weights[index]);
+      }
+   }
+
+   for _, index := range([]int{0, 2}) {
+      if (weights[index] == 0) {
+         t.Errorf("[%d] -- Relevant feature should have a non-zero weight.", index);
+      }
+   }
+
+   // The L1 penalty shrinks, but not by much with a small alpha.
+   if (math.Abs(weights[0] - 4.0) > 0.1 || math.Abs(weights[2] + 2.0) > 0.1) {
+      t.Errorf("Bad weights. Expected about: [4, 0, -2, 0], Got: %v", weights);
+   }
+
+   var predictions []float64 = lasso.Predict([]base.Tuple{base.NewFloatTuple([]float64{1, 0, 1, 0}, nil)});
+   if (math.Abs(predictions[0] - 7.0) > 0.2) {
+      t.Errorf("Bad prediction. Expected about: %v, Got: %v", 7.0, predictions[0]);
+   }
+}
+
+// With no L1 penalty, the elastic net objective is just ridge scaled by 1 / (2n).
+func TestElasticNetMatchesRidge(t *testing.T) {
+   var data []base.Tuple = linearTestData();
+   var alpha float64 = 0.5;
+
+   var elasticNet *ElasticNet = NewElasticNet(nil, alpha, 0, 0, 1e-10);
+   elasticNet.Train(data);
+
+   var ridge *Ridge = NewRidge(nil, alpha * float64(len(data)));
+   ridge.Train(data);
+
+   var elasticNetWeights []float64 = elasticNet.Weights();
+   var ridgeWeights []float64 = ridge.Weights();
+   for i, _ := range(ridgeWeights) {
+      if (math.Abs(elasticNetWeights[i] - ridgeWeights[i]) > 1e-6) {
+         t.Errorf("[%d] -- Weights do not match. Ridge: %v, ElasticNet: %v", i, ridgeWeights[i], elasticNetWeights[i]);
+      }
+   }
+
+   if (math.Abs(elasticNet.Intercept() - ridge.Intercept()) > 1e-6) {
+      t.Errorf("Intercepts do not match. Ridge: %v, ElasticNet: %v", ridge.Intercept(), elasticNet.Intercept());
+   }
+
+   if (elasticNet.Iterations() >= ELASTIC_NET_DEFAULT_MAX_ITERATIONS) {
+      t.Errorf("Coordinate descent did not converge.");
+   }
+}
@@ -0,0 +1,76 @@
+package regression
+
+// Ordinary least squares.
+// Solved in closed form with an SVD (the pseudo-inverse), so collinear features are fine
+// (the minimum norm solution is chosen).
+
+import (
+   "github.com/eriq-augustine/goml/base"
+   "github.com/eriq-augustine/goml/features"
+
+   "gonum.org/v1/gonum/mat"
+)
+
+const (
+   // Singular values smaller than this (relative to the largest) are treated as zero.
+   OLS_RCOND = 1e-12
+)
+
+type LinearRegression struct {
+   reducer features.Reducer
+   model linearModel
+}
+
+func NewLinearRegression(reducer features.Reducer) *LinearRegression {
+   if (reducer == nil) {
+      reducer = features.NoReducer{};
+   }
+
+   var lr LinearRegression = LinearRegression{
+      reducer: reducer,
+   };
+
+   return &lr;
+}
+
+func (this *LinearRegression) Train(tuples []base.Tuple) {
+   this.reducer.Init(tuples);
+   tuples = this.reducer.Reduce(tuples);
+
+   data, targets := trainingData(tuples);
+   data, targets, featureMeans, targetMean := center(data, targets);
+
+   var svd mat.SVD;
+   if (!svd.Factorize(toDense(data), mat.SVDThin)) {
+      panic("SVD factorization failed.");
+   }
+
+   var weights mat.VecDense;
+   svd.SolveVecTo(&weights, mat.NewVecDense(len(targets), targets), svd.Rank(OLS_RCOND));
+
+   this.model.weights = mat.Col(nil, 0, &weights);
+   this.model.setIntercept(featureMeans, targetMean);
+}
+
+func (this LinearRegression) Predict(tuples []base.Tuple) []float64 {
+   tuples = this.reducer.Reduce(tuples);
+   return this.model.predict(numericData(tuples));
+}
+
+// Indexes are into the reduced features.
+func (this LinearRegression) Weights() []float64 {
+   return append([]float64(nil), this.model.weights...);
+}
+
+func (this LinearRegression) Intercept() float64 {
+   return this.model.intercept;
+}
+
+func toDense(data [][]float64) *mat.Dense {
+   var matrix *mat.Dense = mat.NewDense(len(data), len(data[0]), nil);
+   for i, row := range(data) {
+      matrix.SetRow(i, row);
+   }
+
+   return matrix;
+}
@@ -0,0 +1,159 @@
+package regression
+
+import (
+   "math"
+   "testing"
+
+   "github.com/eriq-augustine/goml/base"
+   "github.com/eriq-augustine/goml/features"
+)
+
+type linearTestCase struct {
+   Name string
+   Regressor Regressor
+   TestData []base.Tuple
+   Input []base.Tuple
+   ExpectedPredictions []float64
+   Tolerence float64
+}
+
+// y = 2 * x0 - 3 * x1 + 1
+func linearFunction(x0 float64, x1 float64) float64 {
+   return 2.0 * x0 - 3.0 * x1 + 1.0;
+}
+
+func linearTestData() []base.Tuple {
+   var data []base.Tuple = make([]base.Tuple, 0);
+   for x0 := -2.0; x0 <= 2.0; x0 += 0.5 {
+      for x1 := -2.0; x1 <= 2.0; x1 += 1.0 {
+         data = append(data, base.NewFloatTuple([]float64{x0, x1}, linearFunction(x0, x1)));
+      }
+   }
+
+   return data;
+}
+
+func linearTestInput() ([]base.Tuple, []float64) {
+   return []base.Tuple{
+         base.NewFloatTuple([]float64{0, 0}, nil),
+         base.NewFloatTuple([]float64{1.25, -0.75}, nil),
+         base.NewFloatTuple([]float64{-3, 4}, nil),
+      },
+      []float64{
+         linearFunction(0, 0),
+         linearFunction(1.25, -0.75),
+         linearFunction(-3, 4),
+      };
+}
+
+func TestLinearRegressionBase(t *testing.T) {
+   input, expected := linearTestInput();
+
+   // Same data but with noise columns that get reduced away.
+   var paddedData []base.Tuple = make([]base.Tuple, 0);
+   for i, tuple := range(linearTestData()) {
+      var values []float64 = tuple.(base.NumericTuple).ToFloatSlice();
+      paddedData = append(paddedData, base.NewFloatTuple([]float64{float64(i % 3), values[0], 7, values[1]}, tuple.GetClass()));
+   }
+
+   var paddedInput []base.Tuple = make([]base.Tuple, len(input));
+   for i, tuple := range(input) {
+      var values []float64 = tuple.(base.NumericTuple).ToFloatSlice();
+      paddedInput[i] = base.NewFloatTuple([]float64{-100, values[0], 100, values[1]}, nil);
+   }
+
+   // x1 is exactly 2 * x0.
+   var collinearData []base.Tuple = make([]base.Tuple, 0);
+   for x := -2.0; x <= 2.0; x += 0.5 {
+      collinearData = append(collinearData, base.NewFloatTuple([]float64{x, 2.0 * x}, 3.0 * x + 1.0));
+   }
+
+   var testCases []linearTestCase = []linearTestCase{
+      linearTestCase{
+         "OLS - Base",
+         NewLinearRegression(nil),
+         linearTestData(),
+         input,
+         expected,
+         1e-9,
+      },
+      linearTestCase{
+         "OLS - Reduced",
+         NewLinearRegression(features.NewManualReducer([]int{1, 3})),
+         paddedData,
+         paddedInput,
+         expected,
+         1e-9,
+      },
+      linearTestCase{
+         "OLS - Collinear",
+         NewLinearRegression(nil),
+         collinearData,
+         []base.Tuple{
+            base.NewFloatTuple([]float64{0.25, 0.5}, nil),
+            base.NewFloatTuple([]float64{3, 6}, nil),
+         },
+         []float64{
+            1.75,
+            10.0,
+         },
+         1e-9,
+      },
+      linearTestCase{
+         "Ridge - No Penalty",
+         NewRidge(nil, 0),
+         linearTestData(),
+         input,
+         expected,
+         1e-9,
+      },
+      linearTestCase{
+         "Ridge - Small Penalty",
+         NewRidge(nil, 0.01),
+         linearTestData(),
+         input,
+         expected,
+         0.01,
+      },
+   };
+
+   for _, testCase := range(testCases) {
+      testCase.Regressor.Train(testCase.TestData);
+      var predictions []float64 = testCase.Regressor.Predict(testCase.Input);
+
+      if (len(predictions) != len(testCase.ExpectedPredictions)) {
+         t.Errorf("(%s) -- Length of expected (%d) and actual predictions (%d) do not match", testCase.Name, len(testCase.ExpectedPredictions), len(predictions));
+         continue;
+      }
+
+      for i, _ := range(predictions) {
+         if (math.Abs(predictions[i] - testCase.ExpectedPredictions[i]) > testCase.Tolerence) {
+            t.Errorf("(%s)[%d] -- Bad prediction. Expected: %v, Got: %v", testCase.Name, i, testCase.ExpectedPredictions[i], predictions[i]);
+         }
+      }
+   }
+}
+
+// A larger penalty should always give smaller weights.
+func TestRidgeShrinkage(t *testing.T) {
+   var previousNorm float64 = math.Inf(1);
+
+   for _, penalty := range([]float64{0, 1, 10, 100, 1e6}) {
+      var ridge *Ridge = NewRidge(nil, penalty);
+      ridge.Train(linearTestData());
+
+      var norm float64 = 0;
+      for _, weight := range(ridge.Weights()) {
+         norm += weight * weight;
+      }
+
+      if (norm >= previousNorm) {
+         t.Errorf("(%v) -- Weights did not shrink. Previous norm: %v, Got: %v", penalty, previousNorm, norm);
+      }
+      previousNorm = norm;
+   }
+
+   if (previousNorm > 1e-3) {
+      t.Errorf("Weights should be near zero with a huge penalty. Got squared norm: %v", previousNorm);
+   }
+}
@@ -0,0 +1,12 @@
+package regression
+
+import (
+   "github.com/eriq-augustine/goml/base"
+)
+
+// Regressors use the class of each training tuple as the target,
+// so the class must be a base.NumericFeature.
+type Regressor interface {
+   Train([]base.Tuple)
+   Predict([]base.Tuple) []float64
+}
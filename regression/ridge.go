@@ -0,0 +1,91 @@
+package regression
+
+// Least squares with an L2 penalty on the weights (the intercept is not penalized).
+// Minimizes: ||y - Xw||^2 + l2Penalty * ||w||^2
+// Solved in closed form: w = (X'X + l2Penalty * I)^-1 X'y
+
+import (
+   "fmt"
+
+   "github.com/eriq-augustine/goml/base"
+   "github.com/eriq-augustine/goml/features"
+
+   "gonum.org/v1/gonum/mat"
+)
+
+const (
+   RIDGE_DEFAULT_L2_PENALTY = 1.0
+)
+
+type Ridge struct {
+   reducer features.Reducer
+   l2Penalty float64
+   model linearModel
+}
+
+// Pass a negative value for |l2Penalty| to get the default.
+// A zero |l2Penalty| is OLS, but the features must not be collinear (use LinearRegression instead).
+func NewRidge(reducer features.Reducer, l2Penalty float64) *Ridge {
+   if (reducer == nil) {
+      reducer = features.NoReducer{};
+   }
+
+   if (l2Penalty < 0) {
+      l2Penalty = RIDGE_DEFAULT_L2_PENALTY;
+   }
+
+   var ridge Ridge = Ridge{
+      reducer: reducer,
+      l2Penalty: l2Penalty,
+   };
+
+   return &ridge;
+}
+
+func (this *Ridge) Train(tuples []base.Tuple) {
+   this.reducer.Init(tuples);
+   tuples = this.reducer.Reduce(tuples);
+
+   data, targets := trainingData(tuples);
+   data, targets, featureMeans, targetMean := center(data, targets);
+
+   var x *mat.Dense = toDense(data);
+   var numFeatures int = len(data[0]);
+
+   var gram *mat.SymDense = mat.NewSymDense(numFeatures, nil);
+   gram.SymOuterK(1, x.T());
+   for j := 0; j < numFeatures; j++ {
+      gram.SetSym(j, j, gram.At(j, j) + this.l2Penalty);
+   }
+
+   var xty mat.VecDense;
+   xty.MulVec(x.T(), mat.NewVecDense(len(targets), targets));
+
+   var cholesky mat.Cholesky;
+   if (!cholesky.Factorize(gram)) {
+      panic(fmt.Sprintf("Ridge system is not positive definite (l2Penalty: %v). Are the features collinear?", this.l2Penalty));
+   }
+
+   var weights mat.VecDense;
+   err := cholesky.SolveVecTo(&weights, &xty);
+   if (err != nil) {
+      panic(fmt.Sprintf("Failed to solve ridge system: %v", err));
+   }
+
+   this.model.weights = mat.Col(nil, 0, &weights);
+   this.model.setIntercept(featureMeans, targetMean);
+}
+
+func (this Ridge) Predict(tuples []base.Tuple) []float64 {
+   tuples = this.reducer.Reduce(tuples);
+   return this.model.predict(numericData(tuples));
+}
+
+// Indexes are into the reduced features.
+func (this Ridge) Weights() []float64 {
+   return append([]float64(nil), this.model.weights...);
+}
+
+func (this Ridge) Intercept() float64 {
+   return this.model.intercept;
+}
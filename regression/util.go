@@ -0,0 +1,119 @@
+package regression
+
+import (
+   "fmt"
+
+   "github.com/eriq-augustine/goml/base"
+)
+
+// The weights and intercept shared by all the linear models.
+type linearModel struct {
+   weights []float64
+   intercept float64
+}
+
+func (this linearModel) predict(data [][]float64) []float64 {
+   var predictions []float64 = make([]float64, len(data));
+   for i, dataPoint := range(data) {
+      if (len(dataPoint) != len(this.weights)) {
+         panic(fmt.Sprintf("Expected %d features, got %d.", len(this.weights), len(dataPoint)));
+      }
+
+      predictions[i] = this.intercept;
+      for j, value := range(dataPoint) {
+         predictions[i] += this.weights[j] * value;
+      }
+   }
+
+   return predictions;
+}
+
+// Get the intercept for weights that were fit on centered data.
+func (this *linearModel) setIntercept(featureMeans []float64, targetMean float64) {
+   this.intercept = targetMean;
+   for j, mean := range(featureMeans) {
+      this.intercept -= this.weights[j] * mean;
+   }
+}
+
+func numericData(tuples []base.Tuple) [][]float64 {
+   var data [][]float64 = make([][]float64, len(tuples));
+   var numFeatures int = -1;
+
+   for i, tuple := range(tuples) {
+      numericTuple, ok := tuple.(base.NumericTuple);
+      if (!ok) {
+         panic(fmt.Sprintf("Regression only supports NumericTuple. Found type: %T", tuple));
+      }
+
+      data[i] = numericTuple.ToFloatSlice();
+
+      if (numFeatures == -1) {
+         numFeatures = numericTuple.DataSize();
+      } else if (numFeatures != numericTuple.DataSize()) {
+         panic(fmt.Sprintf("Inconsistent number of features. Tuple[0]: %d, Tuple[%d]: %d",
+               numFeatures, i, numericTuple.DataSize()));
+      }
+   }
+
+   return data;
+}
+
+func targets(tuples []base.Tuple) []float64 {
+   var targets []float64 = make([]float64, len(tuples));
+   for i, tuple := range(tuples) {
+      numericClass, ok := tuple.GetClass().(base.NumericFeature);
+      if (!ok) {
+         panic(fmt.Sprintf("Regression requires numeric classes. Tuple[%d] class: %T", i, tuple.GetClass()));
+      }
+      targets[i] = numericClass.NumericValue();
+   }
+
+   return targets;
+}
+
+// Returns the centered data and targets along with the means that were removed.
+// The inputs are not modified.
+func center(data [][]float64, targets []float64) ([][]float64, []float64, []float64, float64) {
+   var featureMeans []float64 = make([]float64, len(data[0]));
+   var targetMean float64 = 0;
+
+   for i, dataPoint := range(data) {
+      for j, value := range(dataPoint) {
+         featureMeans[j] += value;
+      }
+      targetMean += targets[i];
+   }
+
+   for j, _ := range(featureMeans) {
+      featureMeans[j] /= float64(len(data));
+   }
+   targetMean /= float64(len(data));
+
+   var centeredData [][]float64 = make([][]float64, len(data));
+   var centeredTargets []float64 = make([]float64, len(targets));
+   for i, dataPoint := range(data) {
+      centeredData[i] = make([]float64, len(dataPoint));
+      for j, value := range(dataPoint) {
+         centeredData[i][j] = value - featureMeans[j];
+      }
+      centeredTargets[i] = targets[i] - targetMean;
+   }
+
+   return centeredData, centeredTargets, featureMeans, targetMean;
+}
+
+// Common input checks and preprocessing for training.
+func trainingData(tuples []base.Tuple) ([][]float64, []float64) {
+   if (tuples == nil || len(tuples) == 0) {
+      panic("Must provide tuples for training.")
+   }
+
+   var data [][]float64 = numericData(tuples);
+   if (len(data[0]) == 0) {
+      panic("Must have at least one feature.");
+   }
+
+   return data, targets(tuples);
+}
+
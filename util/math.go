@@ -3,6 +3,7 @@ package util
 import (
    "fmt"
    "math"
+   "sort"
 )
 
 const EPSILON = 0.00000001
@@ -126,3 +127,24 @@ func Max(values []float64) (int, float64) {
 func Sigmoid(val float64) float64 {
    return 1.0 / (1.0 + math.Exp(-1.0 * val));
 }
+
+// The |q| quantile (in [0, 1]) of |values|, linearly interpolating between the closest ranks.
+// |values| is not modified.
+func Quantile(values []float64, q float64) float64 {
+   if (len(values) == 0) {
+      panic("No values sent to quantile.");
+   }
+
+   if (q < 0 || q > 1) {
+      panic(fmt.Sprintf("Quantile must be in [0, 1], got: %v", q));
+   }
+
+   var sorted []float64 = append([]float64(nil), values...);
+   sort.Float64s(sorted);
+
+   var position float64 = q * float64(len(sorted) - 1);
+   var lower int = int(math.Floor(position));
+   var upper int = int(math.Ceil(position));
+
+   return sorted[lower] + (position - float64(lower)) * (sorted[upper] - sorted[lower]);
+}